@@ -0,0 +1,170 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import "context"
+
+// Analyzer provides access to the information of an ArangoSearch analyzer.
+// Analyzers are only available in ArangoDB 3.5 and higher.
+type Analyzer interface {
+	// Name returns the name of the analyzer, without the database prefix.
+	Name() string
+
+	// UniqueName returns the name of the analyzer as returned by the server.
+	// For analyzers that are not built-in, this name is prefixed with
+	// the name of the database (`<db-name>::<analyzer-name>`).
+	UniqueName() string
+
+	// Type returns the type of the analyzer.
+	Type() AnalyzerType
+
+	// Definition returns the full definition of the analyzer.
+	Definition() AnalyzerDefinition
+
+	// Database returns the database containing the analyzer.
+	Database() Database
+
+	// Remove removes the analyzer.
+	// If force is set, the analyzer is removed even when it is still in use by a view.
+	// If the analyzer does not exist, a NotFoundError is returned.
+	Remove(ctx context.Context, force bool) error
+}
+
+// AnalyzerType specifies the type of an analyzer.
+type AnalyzerType string
+
+const (
+	// AnalyzerTypeIdentity treats the value as an atom (no transformation).
+	AnalyzerTypeIdentity AnalyzerType = "identity"
+	// AnalyzerTypeDelimiter splits the value into tokens on a delimiter.
+	AnalyzerTypeDelimiter AnalyzerType = "delimiter"
+	// AnalyzerTypeStem applies stemming to the value as a whole.
+	AnalyzerTypeStem AnalyzerType = "stem"
+	// AnalyzerTypeNorm applies normalization (case, accents) to the value as a whole.
+	AnalyzerTypeNorm AnalyzerType = "norm"
+	// AnalyzerTypeNGram produces n-grams of the value.
+	AnalyzerTypeNGram AnalyzerType = "ngram"
+	// AnalyzerTypeText tokenizes the value into words and applies case conversion,
+	// accent removal, stemming and stop-word removal.
+	AnalyzerTypeText AnalyzerType = "text"
+)
+
+// AnalyzerFeature specifies a feature of an analyzer.
+type AnalyzerFeature string
+
+const (
+	// AnalyzerFeatureFrequency tracks how often a term occurs.
+	// Required for PHRASE() and for the BM25() and TFIDF() scorers.
+	AnalyzerFeatureFrequency AnalyzerFeature = "frequency"
+	// AnalyzerFeatureNorm calculates and stores the field normalization factor.
+	// Used by the TFIDF() scorer when its withNorms argument is set and by BM25().
+	AnalyzerFeatureNorm AnalyzerFeature = "norm"
+	// AnalyzerFeaturePosition enumerates the tokens for position-dependent queries.
+	// Required for PHRASE(). Requires AnalyzerFeatureFrequency.
+	AnalyzerFeaturePosition AnalyzerFeature = "position"
+)
+
+// AnalyzerCase specifies the case conversion of norm and text analyzers.
+type AnalyzerCase string
+
+const (
+	// AnalyzerCaseLower converts to all lower-case characters.
+	AnalyzerCaseLower AnalyzerCase = "lower"
+	// AnalyzerCaseUpper converts to all upper-case characters.
+	AnalyzerCaseUpper AnalyzerCase = "upper"
+	// AnalyzerCaseNone does not change character case.
+	AnalyzerCaseNone AnalyzerCase = "none"
+)
+
+// AnalyzerNGramStreamType specifies the type of the input stream of an ngram analyzer.
+type AnalyzerNGramStreamType string
+
+const (
+	// AnalyzerNGramStreamTypeBinary treats the input as a sequence of bytes.
+	AnalyzerNGramStreamTypeBinary AnalyzerNGramStreamType = "binary"
+	// AnalyzerNGramStreamTypeUTF8 treats the input as a sequence of UTF-8 encoded code points.
+	AnalyzerNGramStreamTypeUTF8 AnalyzerNGramStreamType = "utf8"
+)
+
+// AnalyzerDefinition contains the definition of an analyzer.
+type AnalyzerDefinition struct {
+	// Name of the analyzer.
+	Name string `json:"name"`
+	// Type of the analyzer.
+	Type AnalyzerType `json:"type"`
+	// Properties of the analyzer. Which properties are used depends on the type of the analyzer.
+	Properties AnalyzerProperties `json:"properties,omitempty"`
+	// Features enabled for the analyzer.
+	Features []AnalyzerFeature `json:"features,omitempty"`
+}
+
+// AnalyzerProperties contains the properties of an analyzer.
+// Only the properties that apply to the type of the analyzer should be set.
+// Identity analyzers have no properties.
+type AnalyzerProperties struct {
+	// Delimiter is the delimiting character(s) used by delimiter analyzers.
+	Delimiter string `json:"delimiter,omitempty"`
+	// Locale is the locale in the format `language[_COUNTRY][.encoding][@variant]`
+	// (e.g. "de.utf-8" or "en_US.utf-8"). Used by stem, norm and text analyzers.
+	Locale string `json:"locale,omitempty"`
+	// Case specifies the case conversion. Used by norm and text analyzers.
+	// Defaults to "none" for norm analyzers and "lower" for text analyzers.
+	Case AnalyzerCase `json:"case,omitempty"`
+	// Accent specifies whether accent characters are preserved. Used by norm and text analyzers.
+	// Defaults to true for norm analyzers and false for text analyzers.
+	Accent *bool `json:"accent,omitempty"`
+	// Stemming specifies whether stemming is applied. Used by text analyzers.
+	// Defaults to true.
+	Stemming *bool `json:"stemming,omitempty"`
+	// Stopwords is a list of words to omit from the result. Used by text analyzers.
+	// If not set, the stopwords are loaded from StopwordsPath.
+	Stopwords []string `json:"stopwords,omitempty"`
+	// StopwordsPath is the path to a directory containing stopword files,
+	// relative to the server. Used by text analyzers.
+	StopwordsPath string `json:"stopwordsPath,omitempty"`
+	// EdgeNGram specifies the edge n-grams that are produced for each token. Used by text analyzers.
+	EdgeNGram *AnalyzerEdgeNGram `json:"edgeNgram,omitempty"`
+	// Min is the minimum n-gram length. Used by ngram analyzers.
+	Min *int64 `json:"min,omitempty"`
+	// Max is the maximum n-gram length. Used by ngram analyzers.
+	Max *int64 `json:"max,omitempty"`
+	// PreserveOriginal specifies whether to include the original value as well. Used by ngram analyzers.
+	PreserveOriginal *bool `json:"preserveOriginal,omitempty"`
+	// StartMarker is prepended to n-grams at the beginning of the input. Used by ngram analyzers.
+	StartMarker string `json:"startMarker,omitempty"`
+	// EndMarker is appended to n-grams at the end of the input. Used by ngram analyzers.
+	EndMarker string `json:"endMarker,omitempty"`
+	// StreamType specifies the type of the input stream. Used by ngram analyzers.
+	// Defaults to "binary".
+	StreamType AnalyzerNGramStreamType `json:"streamType,omitempty"`
+}
+
+// AnalyzerEdgeNGram specifies the edge n-grams produced by a text analyzer.
+type AnalyzerEdgeNGram struct {
+	// Min is the minimum n-gram length.
+	Min *int64 `json:"min,omitempty"`
+	// Max is the maximum n-gram length.
+	Max *int64 `json:"max,omitempty"`
+	// PreserveOriginal specifies whether to include the original token as well.
+	PreserveOriginal *bool `json:"preserveOriginal,omitempty"`
+}
@@ -0,0 +1,108 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// newAnalyzer creates a new Analyzer implementation.
+func newAnalyzer(definition AnalyzerDefinition, db *database) (Analyzer, error) {
+	if definition.Name == "" {
+		return nil, WithStack(InvalidArgumentError{Message: "definition.Name is empty"})
+	}
+	if db == nil {
+		return nil, WithStack(InvalidArgumentError{Message: "db is nil"})
+	}
+	return &analyzer{
+		definition: definition,
+		db:         db,
+		conn:       db.conn,
+	}, nil
+}
+
+type analyzer struct {
+	definition AnalyzerDefinition
+	db         *database
+	conn       Connection
+}
+
+// relPath creates the relative path to this analyzer (`_db/<db-name>/_api/analyzer/<analyzer-name>`)
+func (a *analyzer) relPath() string {
+	escapedName := pathEscape(a.Name())
+	return path.Join(a.db.relPath(), "_api", "analyzer", escapedName)
+}
+
+// Name returns the name of the analyzer, without the database prefix.
+func (a *analyzer) Name() string {
+	name := a.definition.Name
+	if idx := strings.Index(name, "::"); idx >= 0 {
+		return name[idx+2:]
+	}
+	return name
+}
+
+// UniqueName returns the name of the analyzer as returned by the server.
+// For analyzers that are not built-in, this name is prefixed with
+// the name of the database (`<db-name>::<analyzer-name>`).
+func (a *analyzer) UniqueName() string {
+	return a.definition.Name
+}
+
+// Type returns the type of the analyzer.
+func (a *analyzer) Type() AnalyzerType {
+	return a.definition.Type
+}
+
+// Definition returns the full definition of the analyzer.
+func (a *analyzer) Definition() AnalyzerDefinition {
+	return a.definition
+}
+
+// Database returns the database containing the analyzer.
+func (a *analyzer) Database() Database {
+	return a.db
+}
+
+// Remove removes the analyzer.
+// If force is set, the analyzer is removed even when it is still in use by a view.
+// If the analyzer does not exist, a NotFoundError is returned.
+func (a *analyzer) Remove(ctx context.Context, force bool) error {
+	req, err := a.conn.NewRequest("DELETE", a.relPath())
+	if err != nil {
+		return WithStack(err)
+	}
+	if force {
+		req.SetQuery("force", "true")
+	}
+	resp, err := a.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
@@ -22,6 +22,8 @@
 
 package driver
 
+import "time"
+
 type AuthenticationType int
 
 const (
@@ -31,6 +33,10 @@ const (
 	AuthenticationTypeJWT
 	// AuthenticationTypeRaw uses a raw value for the Authorization header
 	AuthenticationTypeRaw
+	// AuthenticationTypeJWTSecret uses JWT tokens signed with a secret that is shared with the servers
+	AuthenticationTypeJWTSecret
+	// AuthenticationTypeProvided obtains its credentials from a CredentialsProvider
+	AuthenticationTypeProvided
 )
 
 // Authentication implements a kind of authentication.
@@ -85,7 +91,52 @@ func (a *userNameAuthentication) Get(property string) string {
 	}
 }
 
+// JWTSecretAuthentication creates an authentication implementation that uses short-lived JWT tokens,
+// signed with the given secret (the JWT secret of the servers).
+// Such tokens give "super-user" access to the servers.
+// Tokens are signed again before they expire.
+// If options.SecretFile is set, the secret is read from that file instead
+// and the file is reloaded every time it has changed.
+// The returned authentication implements JWTTokenSource.
+func JWTSecretAuthentication(secret string, options *JWTSecretOptions) Authentication {
+	a := &jwtSecretAuthentication{secret: []byte(secret)}
+	if options != nil {
+		a.options = *options
+	}
+	if a.options.TokenLifetime <= 0 {
+		a.options.TokenLifetime = DefaultJWTSecretTokenLifetime
+	}
+	return a
+}
+
+// JWTSecretOptions contains options that customize JWTSecretAuthentication.
+type JWTSecretOptions struct {
+	// UserName is stored in the `preferred_username` claim of the tokens.
+	// If empty, the tokens do not identify a user.
+	UserName string
+	// ServerID is stored in the `server_id` claim of the tokens.
+	// If empty, the tokens have no `server_id` claim.
+	ServerID string
+	// TokenLifetime is the time after which a token expires.
+	// Defaults to DefaultJWTSecretTokenLifetime.
+	TokenLifetime time.Duration
+	// SecretFile is the path of a file containing the secret.
+	// If set, the secret passed to JWTSecretAuthentication is not used.
+	// The file is checked for changes every time a token is signed, so a rotated
+	// secret is picked up when the current token is renewed or rejected by the server.
+	SecretFile string
+}
+
+// JWTTokenSource is implemented by authentications that create their own JWT tokens
+// (see JWTSecretAuthentication).
+// It is used by connection implementations to obtain the tokens to send to the servers.
+type JWTTokenSource interface {
+	// Token returns a newly signed JWT token.
+	Token() (string, error)
+}
+
 // RawAuthentication creates a raw authentication implementation based on the given value for the Authorization header.
+// It is only supported by HTTP connections.
 func RawAuthentication(value string) Authentication {
 	return &rawAuthentication{
 		value: value,
@@ -0,0 +1,140 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultJWTSecretTokenLifetime is the default lifetime of tokens created by JWTSecretAuthentication.
+	DefaultJWTSecretTokenLifetime = time.Minute * 10
+
+	jwtIssuerArangoDB = "arangodb"
+)
+
+// jwtSecretAuthentication implements JWT secret authentication.
+type jwtSecretAuthentication struct {
+	options     JWTSecretOptions
+	mutex       sync.Mutex
+	secret      []byte
+	fileModTime time.Time
+	fileSize    int64
+}
+
+// Returns the type of authentication
+func (a *jwtSecretAuthentication) Type() AuthenticationType {
+	return AuthenticationTypeJWTSecret
+}
+
+// Get returns a configuration property of the authentication.
+// Supported properties depend on type of authentication.
+func (a *jwtSecretAuthentication) Get(property string) string {
+	switch property {
+	case "username":
+		return a.options.UserName
+	case "secretFile":
+		return a.options.SecretFile
+	default:
+		return ""
+	}
+}
+
+// jwtSecretClaims holds the claims of tokens created by JWTSecretAuthentication.
+type jwtSecretClaims struct {
+	Issuer            string `json:"iss"`
+	PreferredUserName string `json:"preferred_username,omitempty"`
+	ServerID          string `json:"server_id,omitempty"`
+	IssuedAt          int64  `json:"iat"`
+	ExpiresAt         int64  `json:"exp"`
+}
+
+// Token returns a newly signed JWT token.
+func (a *jwtSecretAuthentication) Token() (string, error) {
+	secret, err := a.currentSecret()
+	if err != nil {
+		return "", WithStack(err)
+	}
+	now := time.Now()
+	claims := jwtSecretClaims{
+		Issuer:            jwtIssuerArangoDB,
+		PreferredUserName: a.options.UserName,
+		ServerID:          a.options.ServerID,
+		IssuedAt:          now.Unix(),
+		ExpiresAt:         now.Add(a.options.TokenLifetime).Unix(),
+	}
+	token, err := signJWTHS256(claims, secret)
+	if err != nil {
+		return "", WithStack(err)
+	}
+	return token, nil
+}
+
+// currentSecret returns the secret, reloading it from the secret file if that has changed.
+func (a *jwtSecretAuthentication) currentSecret() ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.options.SecretFile != "" {
+		info, err := os.Stat(a.options.SecretFile)
+		if err != nil {
+			return nil, WithStack(err)
+		}
+		if a.secret == nil || !info.ModTime().Equal(a.fileModTime) || info.Size() != a.fileSize {
+			content, err := ioutil.ReadFile(a.options.SecretFile)
+			if err != nil {
+				return nil, WithStack(err)
+			}
+			a.secret = bytes.TrimSpace(content)
+			a.fileModTime = info.ModTime()
+			a.fileSize = info.Size()
+		}
+	}
+	if len(a.secret) == 0 {
+		return nil, WithStack(InvalidArgumentError{Message: "JWT secret is empty"})
+	}
+	return a.secret, nil
+}
+
+// signJWTHS256 creates a JWT token containing the given claims, signed with the given secret using HMAC SHA-256.
+func signJWTHS256(claims interface{}, secret []byte) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", WithStack(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", WithStack(err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
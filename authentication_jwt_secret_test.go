@@ -0,0 +1,127 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// verifyJWTSecretToken checks the signature of the given token and returns its claims.
+func verifyJWTSecretToken(t *testing.T, token, secret string) jwtSecretClaims {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 token parts, got %d", len(parts))
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if expected := base64.RawURLEncoding.EncodeToString(mac.Sum(nil)); parts[2] != expected {
+		t.Fatalf("Invalid token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("Failed to decode payload: %s", err)
+	}
+	var claims jwtSecretClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("Failed to parse claims: %s", err)
+	}
+	return claims
+}
+
+func TestJWTSecretAuthenticationToken(t *testing.T) {
+	auth := JWTSecretAuthentication("topsecret", &JWTSecretOptions{
+		UserName:      "root",
+		TokenLifetime: time.Minute * 5,
+	})
+	if auth.Type() != AuthenticationTypeJWTSecret {
+		t.Errorf("Expected type %d, got %d", AuthenticationTypeJWTSecret, auth.Type())
+	}
+	if auth.Get("username") != "root" {
+		t.Errorf("Expected username 'root', got '%s'", auth.Get("username"))
+	}
+	token, err := auth.(JWTTokenSource).Token()
+	if err != nil {
+		t.Fatalf("Token failed: %s", err)
+	}
+	claims := verifyJWTSecretToken(t, token, "topsecret")
+	if claims.Issuer != "arangodb" {
+		t.Errorf("Expected issuer 'arangodb', got '%s'", claims.Issuer)
+	}
+	if claims.PreferredUserName != "root" {
+		t.Errorf("Expected preferred_username 'root', got '%s'", claims.PreferredUserName)
+	}
+	if lifetime := claims.ExpiresAt - claims.IssuedAt; lifetime != 300 {
+		t.Errorf("Expected lifetime of 300s, got %ds", lifetime)
+	}
+}
+
+func TestJWTSecretAuthenticationEmptySecret(t *testing.T) {
+	auth := JWTSecretAuthentication("", nil)
+	if _, err := auth.(JWTTokenSource).Token(); !IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
+
+func TestJWTSecretAuthenticationSecretFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwtsecret")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	secretFile := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(secretFile, []byte("first\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+
+	source := JWTSecretAuthentication("ignored", &JWTSecretOptions{SecretFile: secretFile}).(JWTTokenSource)
+	token, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %s", err)
+	}
+	verifyJWTSecretToken(t, token, "first")
+
+	// Rotate the secret
+	if err := ioutil.WriteFile(secretFile, []byte("second-secret\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+	token, err = source.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %s", err)
+	}
+	verifyJWTSecretToken(t, token, "second-secret")
+
+	// Remove the secret file
+	os.Remove(secretFile)
+	if _, err := source.Token(); err == nil {
+		t.Error("Expected error for missing secret file")
+	}
+}
@@ -50,8 +50,38 @@ type CreateDatabaseOptions struct {
 	// If users is not specified or does not contain any users, a default user root will be created with an empty string password.
 	// This ensures that the new database will be accessible after it is created.
 	Users []CreateDatabaseUserOptions `json:"users,omitempty"`
+	// Options of the new database. These are used as defaults for collections created in the database.
+	// Only available in a cluster.
+	Options CreateDatabaseDefaultOptions `json:"options,omitempty"`
 }
 
+// CreateDatabaseDefaultOptions contains options that are used as defaults for collections
+// created in a new database.
+type CreateDatabaseDefaultOptions struct {
+	// Default replication factor for new collections created in this database.
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+	// Default write concern for new collections created in this database.
+	// It determines how many copies of each shard are required to be in sync on the different DBServers.
+	// If there are less then these many copies in the cluster a shard will refuse to write.
+	// Writes to shards with enough up-to-date copies will succeed at the same time however.
+	// The value of writeConcern can not be larger than replicationFactor.
+	WriteConcern int `json:"writeConcern,omitempty"`
+	// Sharding method to use for new collections in this database.
+	Sharding DatabaseSharding `json:"sharding,omitempty"`
+}
+
+// DatabaseSharding indicates the sharding method used for collections in a database.
+type DatabaseSharding string
+
+const (
+	// DatabaseShardingNone is the default sharding method.
+	DatabaseShardingNone DatabaseSharding = ""
+	// DatabaseShardingFlexible lets collections use independent shard distributions.
+	DatabaseShardingFlexible DatabaseSharding = "flexible"
+	// DatabaseShardingSingle places all shards of all collections of the database on a single DBServer (OneShard).
+	DatabaseShardingSingle DatabaseSharding = "single"
+)
+
 // CreateDatabaseUserOptions contains options for creating a single user for a database.
 type CreateDatabaseUserOptions struct {
 	// Loginname of the user to be created
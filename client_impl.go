@@ -81,9 +81,19 @@ func (c *client) SynchronizeEndpoints(ctx context.Context) error {
 	if err != nil {
 		return WithStack(err)
 	}
+	// Unix domain socket endpoints are local to this client, so the cluster does
+	// not report them. Keep them in addition to the reported endpoints.
 	var endpoints []string
+	for _, ep := range c.conn.Endpoints() {
+		if util.IsUnixEndpoint(ep) {
+			endpoints = append(endpoints, ep)
+		}
+	}
 	for _, ep := range cep.Endpoints {
-		endpoints = append(endpoints, util.FixupEndpointURLScheme(ep.Endpoint))
+		endpoint := util.FixupEndpointURLScheme(ep.Endpoint)
+		if !containsString(endpoints, endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
 	}
 
 	// Update connection
@@ -133,3 +143,13 @@ func (c *client) clusterEndpoints(ctx context.Context) (clusterEndpointsResponse
 	}
 	return data, nil
 }
+
+// containsString returns true if the given list contains the given string.
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
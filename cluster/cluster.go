@@ -26,6 +26,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net"
 	"net/http"
 	"sort"
 	"strings"
@@ -37,12 +38,43 @@ import (
 
 const (
 	keyFollowLeaderRedirect driver.ContextKey = "arangodb-followLeaderRedirect"
+	keyRetryPolicy          driver.ContextKey = "arangodb-retryPolicy"
 )
 
 // ConnectionConfig provides all configuration options for a cluster connection.
 type ConnectionConfig struct {
 	// DefaultTimeout is the timeout used by requests that have no timeout set in the given context.
 	DefaultTimeout time.Duration
+	// RetryPolicy specifies if and how failed requests are retried.
+	// If nil, requests are only sent to another server when they have not been written
+	// to the network, or when a server responds that there is no leader.
+	// The policy can be overridden per request using driver.WithRetryPolicy.
+	RetryPolicy *driver.RetryPolicy
+	// CircuitBreaker configures the circuit breakers of the endpoints.
+	// If nil, no circuit breakers are used, but the health of the endpoints is still tracked
+	// (see EndpointHealthReporter).
+	CircuitBreaker *CircuitBreakerConfig
+	// LoadBalancer selects the server for every request.
+	// If nil, all requests are sent to a single server until it fails.
+	// Requests bound to a specific endpoint (see driver.WithEndpoint), such as the continuation
+	// of a cursor, are always sent to that endpoint.
+	LoadBalancer LoadBalancer
+	// Metrics collects metrics of the requests sent by the cluster connection (failovers & retries)
+	// and by the connections to the individual servers.
+	// If nil, no metrics are collected.
+	Metrics driver.Metrics
+	// Resolver is used to resolve endpoints using DNS SRV records, e.g. "srv://_arangodb._tcp.example.com"
+	// (or "srv+https://..." to use TLS).
+	// Requests are sent to the endpoints with the best (lowest) priority that are available,
+	// distributed according to their weights unless LoadBalancer is set.
+	// Synchronizing the endpoints of a client (see driver.Client.SynchronizeEndpoints)
+	// replaces the `srv://` endpoints by the endpoints reported by the cluster.
+	// If nil, net.DefaultResolver is used.
+	Resolver Resolver
+	// ResolveInterval is the minimum interval between re-resolutions of `srv://` endpoints.
+	// The endpoints are re-resolved in the background when a request is sent after this interval.
+	// If 0, DefaultResolveInterval is used. If negative, endpoints are only resolved once.
+	ResolveInterval time.Duration
 }
 
 // ServerConnectionBuilder specifies a function called by the cluster connection when it
@@ -61,9 +93,28 @@ func NewConnection(config ConnectionConfig, connectionBuilder ServerConnectionBu
 	if config.DefaultTimeout == 0 {
 		config.DefaultTimeout = defaultTimeout
 	}
+	if config.Resolver == nil {
+		config.Resolver = net.DefaultResolver
+	}
+	if config.ResolveInterval == 0 {
+		config.ResolveInterval = DefaultResolveInterval
+	}
 	cConn := &clusterConnection{
 		connectionBuilder: connectionBuilder,
 		defaultTimeout:    config.DefaultTimeout,
+		loadBalancer:      config.LoadBalancer,
+		metrics:           config.Metrics,
+		resolver:          config.Resolver,
+		resolveInterval:   config.ResolveInterval,
+		srvBalancer:       &weightedLoadBalancer{current: make(map[string]int)},
+	}
+	if config.RetryPolicy != nil {
+		policy := withRetryDefaults(*config.RetryPolicy)
+		cConn.retryPolicy = &policy
+	}
+	if config.CircuitBreaker != nil {
+		cb := withCircuitBreakerDefaults(*config.CircuitBreaker)
+		cConn.circuitBreaker = &cb
 	}
 	// Initialize endpoints
 	if err := cConn.UpdateEndpoints(endpoints); err != nil {
@@ -73,17 +124,27 @@ func NewConnection(config ConnectionConfig, connectionBuilder ServerConnectionBu
 }
 
 const (
-	defaultTimeout = 9 * time.Minute
-	keyEndpoint    = "arangodb-endpoint"
+	defaultTimeout                   = 9 * time.Minute
+	keyEndpoint    driver.ContextKey = "arangodb-endpoint"
 )
 
 type clusterConnection struct {
 	connectionBuilder ServerConnectionBuilder
 	servers           []driver.Connection
+	health            []*endpointHealth // Health of servers[i]
 	endpoints         []string
 	current           int
 	mutex             sync.RWMutex
 	defaultTimeout    time.Duration
+	retryPolicy       *driver.RetryPolicy
+	circuitBreaker    *CircuitBreakerConfig
+	loadBalancer      LoadBalancer
+	metrics           driver.Metrics
+	resolver          Resolver
+	resolveInterval   time.Duration
+	resolveState      resolveState
+	srvTargets        map[string]srvTarget  // Endpoints resolved from SRV records
+	srvBalancer       *weightedLoadBalancer // Used when no loadBalancer is set and there are srvTargets
 	auth              driver.Authentication
 }
 
@@ -107,6 +168,7 @@ func (c *clusterConnection) NewRequest(method, path string) (driver.Request, err
 // Do performs a given request, returning its response.
 func (c *clusterConnection) Do(ctx context.Context, req driver.Request) (driver.Response, error) {
 	followLeaderRedirect := true
+	retryPolicy := c.retryPolicy
 	if ctx == nil {
 		ctx = context.Background()
 	} else {
@@ -115,6 +177,12 @@ func (c *clusterConnection) Do(ctx context.Context, req driver.Request) (driver.
 				followLeaderRedirect = on
 			}
 		}
+		if v := ctx.Value(keyRetryPolicy); v != nil {
+			if policy, ok := v.(driver.RetryPolicy); ok {
+				policy = withRetryDefaults(policy)
+				retryPolicy = &policy
+			}
+		}
 	}
 	// Timeout management.
 	// We take the given timeout and divide it in 3 so we allow for other servers
@@ -127,31 +195,53 @@ func (c *clusterConnection) Do(ctx context.Context, req driver.Request) (driver.
 		timeout = c.defaultTimeout
 	}
 
+	c.resolveIfDue()
+	c.mutex.RLock()
 	serverCount := len(c.servers)
+	c.mutex.RUnlock()
 	var specificServer driver.Connection
+	var health *endpointHealth
 	if v := ctx.Value(keyEndpoint); v != nil {
 		if endpoint, ok := v.(string); ok {
 			// Specific endpoint specified
 			serverCount = 1
 			var err error
-			specificServer, err = c.getSpecificServer(endpoint)
+			specificServer, health, err = c.getSpecificServer(endpoint)
 			if err != nil {
 				return nil, driver.WithStack(err)
 			}
 		}
 	}
+	maxAttempts := serverCount
+	if retryPolicy != nil {
+		maxAttempts = retryPolicy.MaxAttempts
+	}
 
-	timeoutDivider := math.Max(1.0, math.Min(3.0, float64(serverCount)))
+	timeoutDivider := math.Max(1.0, math.Min(3.0, float64(maxAttempts)))
 	attempt := 1
+	var tried map[*endpointHealth]bool
+	loadBalancer := c.activeLoadBalancer()
 	s := specificServer
 	if s == nil {
-		s = c.getCurrentServer()
+		if loadBalancer != nil {
+			tried = make(map[*endpointHealth]bool)
+			s, health = c.selectServer(loadBalancer, tried)
+		} else {
+			s, health = c.getCurrentServer()
+		}
 	}
 	for {
 		// Send request to specific endpoint with a 1/3 timeout (so we get 3 attempts)
 		serverCtx, cancel := context.WithTimeout(ctx, time.Duration(float64(timeout)/timeoutDivider))
+		if health != nil {
+			health.addOutstanding(1)
+		}
 		resp, err := s.Do(serverCtx, req)
 		cancel()
+		if health != nil {
+			health.addOutstanding(-1)
+		}
+		c.recordResult(ctx, health, resp, err)
 
 		isNoLeaderResponse := false
 		if err == nil && resp.StatusCode() == 503 {
@@ -167,7 +257,10 @@ func (c *clusterConnection) Do(ctx context.Context, req driver.Request) (driver.
 			}
 
 		}
-		if !isNoLeaderResponse || !followLeaderRedirect {
+		// A "no leader" response is only retried when we're allowed to follow the leader.
+		retryable := retryPolicy != nil && !(isNoLeaderResponse && !followLeaderRedirect) &&
+			isRetryable(*retryPolicy, req, resp, err)
+		if (!isNoLeaderResponse || !followLeaderRedirect) && !retryable {
 			if err == nil {
 				// We're done
 				return resp, nil
@@ -181,27 +274,116 @@ func (c *clusterConnection) Do(ctx context.Context, req driver.Request) (driver.
 			// otherwise we'll failover to a new server.
 			if req.Written() {
 				// Request has been written to network, do not failover
-				if driver.IsArangoError(err) {
-					// ArangoError, so we got an error response from server.
-					return nil, driver.WithStack(err)
-				}
-				// Not an ArangoError, so it must be some kind of timeout, network ... error.
-				return nil, driver.WithStack(&driver.ResponseError{Err: err})
+				return nil, driver.WithStack(attemptsError(retryPolicy, attempt, responseError(err)))
 			}
 		}
 
-		// Failed, try next server
-		attempt++
-		if specificServer != nil {
+		// Failed, try again
+		if attempt >= maxAttempts {
+			// We've used all attempts. Giving up.
+			if err == nil {
+				// Return the last response
+				return resp, nil
+			}
+			if req.Written() {
+				err = responseError(err)
+			}
+			return nil, driver.WithStack(attemptsError(retryPolicy, attempt, err))
+		}
+		if retryPolicy != nil {
+			// Wait before retrying
+			if err := sleepWithContext(ctx, retryBackoff(*retryPolicy, attempt)); err != nil {
+				return nil, driver.WithStack(err)
+			}
+		} else if specificServer != nil {
 			// A specific server was specified, no failover.
 			return nil, driver.WithStack(err)
 		}
-		if attempt > len(c.servers) {
-			// We've tried all servers. Giving up.
-			return nil, driver.WithStack(err)
+		attempt++
+		prevHealth := health
+		if specificServer == nil {
+			if loadBalancer != nil {
+				tried[health] = true
+				s, health = c.selectServer(loadBalancer, tried)
+			} else {
+				s, health = c.getNextServer()
+			}
+		}
+		if c.metrics != nil {
+			if retryPolicy != nil {
+				c.metrics.Retry(healthEndpoint(prevHealth))
+			}
+			if health != prevHealth {
+				c.metrics.Failover(healthEndpoint(prevHealth), healthEndpoint(health))
+			}
+		}
+	}
+}
+
+// recordResult updates the health of an endpoint with the result of a request.
+// Failures caused by the given (parent) context being canceled or expired are not recorded.
+func (c *clusterConnection) recordResult(ctx context.Context, health *endpointHealth, resp driver.Response, err error) {
+	if health == nil {
+		return
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// Not the fault of the server
+			return
+		}
+		if health.recordFailure(err, false, c.circuitBreaker) {
+			c.scheduleProbe(health)
+		}
+	} else if resp.StatusCode() == http.StatusServiceUnavailable {
+		if health.recordFailure(fmt.Errorf("service unavailable"), false, c.circuitBreaker) {
+			c.scheduleProbe(health)
+		}
+	} else {
+		health.recordSuccess(false)
+	}
+}
+
+// scheduleProbe probes the endpoint with given health once the open timeout of its circuit breaker
+// has elapsed. This repeats until a probe succeeds, or the endpoint is no longer used.
+func (c *clusterConnection) scheduleProbe(health *endpointHealth) {
+	cb := c.circuitBreaker
+	time.AfterFunc(cb.OpenTimeout, func() {
+		c.mutex.RLock()
+		var server driver.Connection
+		for i, h := range c.health {
+			if h == health {
+				server = c.servers[i]
+				break
+			}
+		}
+		c.mutex.RUnlock()
+		if server == nil {
+			// Endpoint has been removed
+			return
 		}
-		s = c.getNextServer()
+		if health.probe(server, cb) {
+			c.scheduleProbe(health)
+		}
+	})
+}
+
+// responseError wraps the given error in a ResponseError, unless it is an ArangoError,
+// in which case we got an error response from the server.
+func responseError(err error) error {
+	if driver.IsArangoError(err) {
+		return err
 	}
+	// Not an ArangoError, so it must be some kind of timeout, network ... error.
+	return &driver.ResponseError{Err: err}
+}
+
+// attemptsError wraps the given error in a RetryError when the request was sent more than once
+// using the given retry policy.
+func attemptsError(policy *driver.RetryPolicy, attempts int, err error) error {
+	if policy == nil || attempts <= 1 {
+		return err
+	}
+	return &driver.RetryError{Attempts: attempts, Err: err}
 }
 
 /*func printError(err error, indent string) {
@@ -250,12 +432,107 @@ func (c *clusterConnection) Endpoints() []string {
 }
 
 // UpdateEndpoints reconfigures the connection to use the given endpoints.
+// Endpoints using DNS SRV records (see ConnectionConfig.Resolver) are resolved.
 func (c *clusterConnection) UpdateEndpoints(endpoints []string) error {
 	if len(endpoints) == 0 {
 		return driver.WithStack(driver.InvalidArgumentError{Message: "Must provide at least 1 endpoint"})
 	}
+	var srv, static []string
+	for _, ep := range endpoints {
+		if IsSRVEndpoint(ep) {
+			srv = append(srv, ep)
+		} else {
+			static = append(static, ep)
+		}
+	}
+	c.resolveState.mutex.Lock()
+	c.resolveState.srv = srv
+	c.resolveState.static = static
+	c.resolveState.mutex.Unlock()
+	if len(srv) == 0 {
+		return c.updateServers(static, nil)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	if err := c.resolve(ctx); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// resolve resolves the `srv://` endpoints and updates the servers with the result.
+func (c *clusterConnection) resolve(ctx context.Context) error {
+	c.resolveState.mutex.Lock()
+	srv, static := c.resolveState.srv, c.resolveState.static
+	c.resolveState.lastResolve = time.Now()
+	c.resolveState.mutex.Unlock()
+
+	endpoints := append([]string(nil), static...)
+	targets := make(map[string]srvTarget)
+	for _, ep := range srv {
+		resolved, err := resolveSRVEndpoint(ctx, c.resolver, ep)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		for x, t := range resolved {
+			if _, found := targets[x]; !found {
+				endpoints = append(endpoints, x)
+			}
+			targets[x] = t
+		}
+	}
+	if len(endpoints) == 0 {
+		return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("No endpoints found for %s", strings.Join(srv, ", "))})
+	}
+	if err := c.updateServers(endpoints, targets); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// resolveIfDue re-resolves the `srv://` endpoints in the background,
+// when the resolve interval has passed since the last resolution.
+func (c *clusterConnection) resolveIfDue() {
+	if c.resolveInterval < 0 {
+		return
+	}
+	c.resolveState.mutex.Lock()
+	due := len(c.resolveState.srv) > 0 && !c.resolveState.resolving && time.Since(c.resolveState.lastResolve) >= c.resolveInterval
+	if due {
+		c.resolveState.resolving = true
+	}
+	c.resolveState.mutex.Unlock()
+	if !due {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		defer cancel()
+		// On failure we keep using the current endpoints
+		c.resolve(ctx)
+		c.resolveState.mutex.Lock()
+		c.resolveState.resolving = false
+		c.resolveState.mutex.Unlock()
+	}()
+}
+
+// updateServers replaces the servers with servers for the given endpoints.
+// Targets holds the priority & weight of endpoints resolved from SRV records.
+func (c *clusterConnection) updateServers(endpoints []string, targets map[string]srvTarget) error {
+	weights := make(map[string]int, len(targets))
+	for ep, t := range targets {
+		weights[ep] = int(t.weight)
+	}
+	c.srvBalancer.setWeights(weights)
+
 	sort.Strings(endpoints)
-	if strings.Join(endpoints, ",") == strings.Join(c.endpoints, ",") {
+	c.mutex.Lock()
+	unchanged := strings.Join(endpoints, ",") == strings.Join(c.endpoints, ",")
+	if unchanged {
+		c.srvTargets = targets
+	}
+	c.mutex.Unlock()
+	if unchanged {
 		// No changes
 		return nil
 	}
@@ -267,20 +544,39 @@ func (c *clusterConnection) UpdateEndpoints(endpoints []string) error {
 		if err != nil {
 			return driver.WithStack(err)
 		}
-		if c.auth != nil {
-			conn, err = conn.SetAuthentication(c.auth)
-			if err != nil {
-				return driver.WithStack(err)
-			}
-		}
 		servers = append(servers, conn)
 	}
 
-	// Swap connections
+	// Swap connections, keeping the health of endpoints we already know
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	// Authentication is configured while holding the lock, so a concurrent
+	// SetAuthentication is never lost.
+	if c.auth != nil {
+		for i, conn := range servers {
+			authConn, err := conn.SetAuthentication(c.auth)
+			if err != nil {
+				return driver.WithStack(err)
+			}
+			servers[i] = authConn
+		}
+	}
+	known := make(map[string]*endpointHealth, len(c.health))
+	for i, h := range c.health {
+		known[c.endpoints[i]] = h
+	}
+	health := make([]*endpointHealth, len(endpoints))
+	for i, ep := range endpoints {
+		if h, found := known[ep]; found {
+			health[i] = h
+		} else {
+			health[i] = newEndpointHealth(ep)
+		}
+	}
 	c.servers = servers
+	c.health = health
 	c.endpoints = endpoints
+	c.srvTargets = targets
 	c.current = 0
 
 	return nil
@@ -324,19 +620,37 @@ func (c *clusterConnection) Protocols() driver.ProtocolSet {
 	return result
 }
 
-// getCurrentServer returns the currently used server.
-func (c *clusterConnection) getCurrentServer() driver.Connection {
+// EndpointHealth returns the health information of all endpoints of the connection.
+func (c *clusterConnection) EndpointHealth() []EndpointHealth {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	return c.servers[c.current]
+
+	result := make([]EndpointHealth, len(c.health))
+	for i, h := range c.health {
+		result[i] = h.snapshot()
+	}
+	return result
+}
+
+// getCurrentServer returns the currently used server.
+// If its circuit breaker is open, the next available server is returned.
+func (c *clusterConnection) getCurrentServer() (driver.Connection, *endpointHealth) {
+	c.mutex.RLock()
+	available := c.isAvailable(c.current)
+	s, h := c.servers[c.current], c.health[c.current]
+	c.mutex.RUnlock()
+	if available {
+		return s, h
+	}
+	return c.getNextServer()
 }
 
 // getSpecificServer returns the server with the given endpoint.
-func (c *clusterConnection) getSpecificServer(endpoint string) (driver.Connection, error) {
+func (c *clusterConnection) getSpecificServer(endpoint string) (driver.Connection, *endpointHealth, error) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
-	for _, s := range c.servers {
+	for i, s := range c.servers {
 		endpoints := s.Endpoints()
 		found := false
 		for _, x := range endpoints {
@@ -346,17 +660,82 @@ func (c *clusterConnection) getSpecificServer(endpoint string) (driver.Connectio
 			}
 		}
 		if found {
-			return s, nil
+			return s, c.health[i], nil
 		}
 	}
 
-	return nil, driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("unknown endpoint: %s", endpoint)})
+	return nil, nil, driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("unknown endpoint: %s", endpoint)})
 }
 
 // getNextServer changes the currently used server and returns the new server.
-func (c *clusterConnection) getNextServer() driver.Connection {
+// Servers with an open circuit breaker are skipped, unless no server is available.
+func (c *clusterConnection) getNextServer() (driver.Connection, *endpointHealth) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.current = (c.current + 1) % len(c.servers)
-	return c.servers[c.current]
+	count := len(c.servers)
+	for i := 1; i <= count; i++ {
+		index := (c.current + i) % count
+		if c.isAvailable(index) {
+			c.current = index
+			return c.servers[index], c.health[index]
+		}
+	}
+	// No server is available, use the next one anyway
+	c.current = (c.current + 1) % count
+	return c.servers[c.current], c.health[c.current]
+}
+
+// selectServer returns the server selected by the load balancer.
+// Servers with an open circuit breaker and servers in the given set are only selected
+// when there are no other servers. Of the remaining servers, only those with the best
+// SRV priority are passed to the load balancer.
+func (c *clusterConnection) selectServer(loadBalancer LoadBalancer, tried map[*endpointHealth]bool) (driver.Connection, *endpointHealth) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	var candidates []LoadBalancerCandidate
+	var indexes []int
+	for pass := 0; pass < 4 && len(candidates) == 0; pass++ {
+		onlyAvailable, skipTried := pass%2 == 0, pass < 2
+		for i, h := range c.health {
+			if (skipTried && tried[h]) || (onlyAvailable && !c.isAvailable(i)) {
+				continue
+			}
+			candidates = append(candidates, LoadBalancerCandidate{
+				Endpoint:    c.endpoints[i],
+				Outstanding: h.getOutstanding(),
+			})
+			indexes = append(indexes, i)
+		}
+	}
+	candidates, indexes = filterByPriority(candidates, indexes, c.srvTargets)
+	selected := loadBalancer.Select(candidates)
+	if selected < 0 || selected >= len(candidates) {
+		selected = 0
+	}
+	index := indexes[selected]
+	return c.servers[index], c.health[index]
+}
+
+// activeLoadBalancer returns the load balancer used to select servers,
+// or nil if all requests are sent to a single server until it fails.
+func (c *clusterConnection) activeLoadBalancer() LoadBalancer {
+	if c.loadBalancer != nil {
+		return c.loadBalancer
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if len(c.srvTargets) > 0 {
+		return c.srvBalancer
+	}
+	return nil
+}
+
+// isAvailable returns true if the server with given index can be used for requests.
+// Requires c.mutex to be locked.
+func (c *clusterConnection) isAvailable(index int) bool {
+	if c.circuitBreaker == nil {
+		return true
+	}
+	return c.health[index].available()
 }
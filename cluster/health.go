@@ -0,0 +1,239 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures after which
+	// the circuit breaker of an endpoint opens.
+	DefaultFailureThreshold = 3
+	// DefaultOpenTimeout is the default time an endpoint is skipped after its circuit breaker opened,
+	// before it is probed.
+	DefaultOpenTimeout = time.Second * 10
+	// DefaultProbeTimeout is the default timeout of a probe request.
+	DefaultProbeTimeout = time.Second * 2
+)
+
+// CircuitBreakerConfig configures the circuit breakers of the endpoints of a cluster connection.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed requests after which the circuit breaker
+	// of an endpoint opens. The endpoint is then skipped when selecting a server.
+	// Defaults to DefaultFailureThreshold.
+	FailureThreshold int
+	// OpenTimeout is the time after which an endpoint with an open circuit breaker is probed
+	// (half-open state), using a GET /_api/version request.
+	// When the probe succeeds, the circuit breaker closes. Otherwise it opens again.
+	// Defaults to DefaultOpenTimeout.
+	OpenTimeout time.Duration
+	// ProbeTimeout is the timeout of a probe request.
+	// Defaults to DefaultProbeTimeout.
+	ProbeTimeout time.Duration
+}
+
+// CircuitState is the state of the circuit breaker of an endpoint.
+type CircuitState int
+
+const (
+	// CircuitClosed indicates that the endpoint is healthy and used for requests.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen indicates that the endpoint has failed too often and is skipped.
+	CircuitOpen
+	// CircuitHalfOpen indicates that the endpoint is being probed.
+	CircuitHalfOpen
+)
+
+// String returns a human readable name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// EndpointHealth holds the health information of a single endpoint.
+type EndpointHealth struct {
+	// Endpoint the information refers to
+	Endpoint string
+	// State of the circuit breaker of the endpoint.
+	// If the connection has no circuit breaker configured, this is always CircuitClosed.
+	State CircuitState
+	// ConsecutiveFailures is the number of failed requests since the last successful request
+	ConsecutiveFailures int
+	// Requests is the total number of requests sent to the endpoint (excluding probes)
+	Requests int64
+	// Failures is the total number of failed requests
+	Failures int64
+	// LastError is the error message of the last failed request
+	LastError string
+	// LastSuccess is the time of the last successful request (zero if none)
+	LastSuccess time.Time
+	// LastFailure is the time of the last failed request (zero if none)
+	LastFailure time.Time
+	// Outstanding is the number of requests currently in progress
+	Outstanding int
+}
+
+// EndpointHealthReporter is implemented by connections that track the health of their endpoints,
+// such as connections created by NewConnection.
+type EndpointHealthReporter interface {
+	// EndpointHealth returns the health information of all endpoints of the connection.
+	EndpointHealth() []EndpointHealth
+}
+
+// withCircuitBreakerDefaults returns the given config with all zero fields replaced by their defaults.
+func withCircuitBreakerDefaults(cfg CircuitBreakerConfig) CircuitBreakerConfig {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultOpenTimeout
+	}
+	if cfg.ProbeTimeout <= 0 {
+		cfg.ProbeTimeout = DefaultProbeTimeout
+	}
+	return cfg
+}
+
+// endpointHealth tracks the health of a single endpoint.
+type endpointHealth struct {
+	outstanding int64 // Number of requests in progress (first for 64-bit alignment)
+	mutex       sync.Mutex
+	info        EndpointHealth
+}
+
+// newEndpointHealth creates health tracking for the given endpoint.
+func newEndpointHealth(endpoint string) *endpointHealth {
+	return &endpointHealth{
+		info: EndpointHealth{Endpoint: endpoint},
+	}
+}
+
+// recordSuccess records a successful request.
+func (h *endpointHealth) recordSuccess(probe bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !probe {
+		h.info.Requests++
+	}
+	h.info.ConsecutiveFailures = 0
+	h.info.LastSuccess = time.Now()
+	h.info.State = CircuitClosed
+}
+
+// recordFailure records a failed request.
+// If a circuit breaker is given, it opens when the failure threshold has been reached,
+// or when a probe failed. Returns true if the circuit breaker was not open before.
+func (h *endpointHealth) recordFailure(err error, probe bool, cb *CircuitBreakerConfig) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !probe {
+		h.info.Requests++
+		h.info.Failures++
+	}
+	h.info.ConsecutiveFailures++
+	h.info.LastFailure = time.Now()
+	if err != nil {
+		h.info.LastError = err.Error()
+	}
+	if cb != nil && h.info.State != CircuitOpen && (probe || h.info.ConsecutiveFailures >= cb.FailureThreshold) {
+		h.info.State = CircuitOpen
+		return true
+	}
+	return false
+}
+
+// available returns true if requests can be sent to the endpoint.
+func (h *endpointHealth) available() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.info.State == CircuitClosed
+}
+
+// setHalfOpen marks the endpoint as being probed.
+func (h *endpointHealth) setHalfOpen() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.info.State = CircuitHalfOpen
+}
+
+// snapshot returns a copy of the health information.
+func (h *endpointHealth) snapshot() EndpointHealth {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	result := h.info
+	result.Outstanding = h.getOutstanding()
+	return result
+}
+
+// healthEndpoint returns the endpoint of the given health, or an empty string if it is nil.
+// The endpoint never changes, so no locking is needed.
+func healthEndpoint(h *endpointHealth) string {
+	if h == nil {
+		return ""
+	}
+	return h.info.Endpoint
+}
+
+// getOutstanding returns the number of requests in progress.
+func (h *endpointHealth) getOutstanding() int {
+	return int(atomic.LoadInt64(&h.outstanding))
+}
+
+// addOutstanding adjusts the number of requests in progress.
+func (h *endpointHealth) addOutstanding(delta int64) {
+	atomic.AddInt64(&h.outstanding, delta)
+}
+
+// probe sends a GET /_api/version request to the given server and records the result.
+// Returns true if the probe failed and the circuit breaker opened again.
+func (h *endpointHealth) probe(server driver.Connection, cb *CircuitBreakerConfig) bool {
+	h.setHalfOpen()
+	ctx, cancel := context.WithTimeout(context.Background(), cb.ProbeTimeout)
+	defer cancel()
+	req, err := server.NewRequest("GET", "/_api/version")
+	if err == nil {
+		var resp driver.Response
+		if resp, err = server.Do(ctx, req); err == nil {
+			err = resp.CheckStatus(200)
+		}
+	}
+	if err != nil {
+		return h.recordFailure(err, true, cb)
+	}
+	h.recordSuccess(true)
+	return false
+}
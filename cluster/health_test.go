@@ -0,0 +1,139 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/cluster"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+// toggleServer is a test server that answers 503 while it is down.
+type toggleServer struct {
+	*httptest.Server
+	down     int32
+	requests int32
+}
+
+func newToggleServer() *toggleServer {
+	ts := &toggleServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&ts.requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.LoadInt32(&ts.down) != 0 {
+			w.WriteHeader(503)
+			w.Write([]byte(`{"error":true,"code":503,"errorNum":503,"errorMessage":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"version":"3.7.0"}`))
+	}))
+	return ts
+}
+
+// healthOf returns the health of the given endpoint.
+func healthOf(t *testing.T, conn driver.Connection, endpoint string) cluster.EndpointHealth {
+	for _, h := range conn.(cluster.EndpointHealthReporter).EndpointHealth() {
+		if h.Endpoint == endpoint {
+			return h
+		}
+	}
+	t.Fatalf("No health for endpoint %s", endpoint)
+	return cluster.EndpointHealth{}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	servers := []*toggleServer{newToggleServer(), newToggleServer()}
+	defer servers[0].Close()
+	defer servers[1].Close()
+	// The cluster connection starts with the first endpoint (in sorted order)
+	sort.Slice(servers, func(i, j int) bool { return servers[i].URL < servers[j].URL })
+	first, second := servers[0], servers[1]
+
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: []string{first.URL, second.URL},
+		ConnectionConfig: cluster.ConnectionConfig{
+			CircuitBreaker: &cluster.CircuitBreakerConfig{
+				FailureThreshold: 2,
+				OpenTimeout:      time.Millisecond * 50,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	ctx := context.Background()
+	do := func() {
+		req, _ := conn.NewRequest("GET", "/_api/version")
+		if _, err := conn.Do(ctx, req); err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+	}
+
+	// Take the first server down until its circuit breaker opens
+	atomic.StoreInt32(&first.down, 1)
+	do()
+	do()
+	if h := healthOf(t, conn, first.URL); h.State != cluster.CircuitOpen || h.ConsecutiveFailures != 2 {
+		t.Fatalf("Expected open circuit after 2 failures, got %s after %d failures", h.State, h.ConsecutiveFailures)
+	}
+
+	// Requests now skip the first server
+	for i := 0; i < 5; i++ {
+		do()
+	}
+	if n := atomic.LoadInt32(&first.requests); n != 2 {
+		t.Errorf("Expected 2 requests on first server, got %d", n)
+	}
+	if n := atomic.LoadInt32(&second.requests); n != 5 {
+		t.Errorf("Expected 5 requests on second server, got %d", n)
+	}
+
+	// Bring the first server back; after the open timeout it is probed and closes again
+	atomic.StoreInt32(&first.down, 0)
+	time.Sleep(time.Millisecond * 60)
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		do()
+		if h := healthOf(t, conn, first.URL); h.State == cluster.CircuitClosed {
+			if h.Requests != 2 || h.Failures != 2 {
+				t.Errorf("Expected 2 requests & failures (probes excluded), got %d & %d", h.Requests, h.Failures)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Circuit breaker did not close after successful probe")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if h := healthOf(t, conn, second.URL); h.State != cluster.CircuitClosed || h.Failures != 0 {
+		t.Errorf("Expected healthy second server, got %s with %d failures", h.State, h.Failures)
+	}
+}
@@ -0,0 +1,150 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// LoadBalancerCandidate describes an endpoint that can be selected by a LoadBalancer.
+type LoadBalancerCandidate struct {
+	// Endpoint of the server
+	Endpoint string
+	// Outstanding is the number of requests currently in progress on the server
+	Outstanding int
+}
+
+// LoadBalancer selects the server to send a request to.
+// Implementations must be safe for concurrent use.
+type LoadBalancer interface {
+	// Select returns the index (in the given candidates) of the endpoint to send the next request to.
+	// The candidates are never empty. Endpoints with an open circuit breaker are not included,
+	// unless all circuit breakers are open.
+	Select(candidates []LoadBalancerCandidate) int
+}
+
+// NewRoundRobinLoadBalancer creates a LoadBalancer that selects the candidates in turn.
+func NewRoundRobinLoadBalancer() LoadBalancer {
+	return &roundRobinLoadBalancer{}
+}
+
+// NewRandomLoadBalancer creates a LoadBalancer that selects a random candidate.
+func NewRandomLoadBalancer() LoadBalancer {
+	return randomLoadBalancer{}
+}
+
+// NewLeastOutstandingLoadBalancer creates a LoadBalancer that selects the candidate
+// with the least requests in progress. Ties are broken in round-robin order.
+func NewLeastOutstandingLoadBalancer() LoadBalancer {
+	return &leastOutstandingLoadBalancer{}
+}
+
+// NewWeightedLoadBalancer creates a LoadBalancer that distributes requests over the candidates
+// proportional to the given weights (by endpoint), using smooth weighted round-robin.
+// Endpoints without a (positive) weight get a weight of 1.
+func NewWeightedLoadBalancer(weights map[string]int) LoadBalancer {
+	w := make(map[string]int, len(weights))
+	for ep, x := range weights {
+		w[ep] = x
+	}
+	return &weightedLoadBalancer{
+		weights: w,
+		current: make(map[string]int),
+	}
+}
+
+// roundRobinLoadBalancer implements round-robin selection.
+type roundRobinLoadBalancer struct {
+	mutex sync.Mutex
+	next  int
+}
+
+// Select returns the index of the endpoint to send the next request to.
+func (lb *roundRobinLoadBalancer) Select(candidates []LoadBalancerCandidate) int {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	index := lb.next % len(candidates)
+	lb.next = index + 1
+	return index
+}
+
+// randomLoadBalancer implements random selection.
+type randomLoadBalancer struct{}
+
+// Select returns the index of the endpoint to send the next request to.
+func (randomLoadBalancer) Select(candidates []LoadBalancerCandidate) int {
+	return rand.Intn(len(candidates))
+}
+
+// leastOutstandingLoadBalancer implements selection of the endpoint with the least requests in progress.
+type leastOutstandingLoadBalancer struct {
+	roundRobin roundRobinLoadBalancer
+}
+
+// Select returns the index of the endpoint to send the next request to.
+func (lb *leastOutstandingLoadBalancer) Select(candidates []LoadBalancerCandidate) int {
+	start := lb.roundRobin.Select(candidates)
+	best := start
+	for i := 1; i < len(candidates); i++ {
+		index := (start + i) % len(candidates)
+		if candidates[index].Outstanding < candidates[best].Outstanding {
+			best = index
+		}
+	}
+	return best
+}
+
+// weightedLoadBalancer implements smooth weighted round-robin selection.
+type weightedLoadBalancer struct {
+	mutex   sync.Mutex
+	weights map[string]int
+	current map[string]int
+}
+
+// setWeights replaces the weights (by endpoint).
+func (lb *weightedLoadBalancer) setWeights(weights map[string]int) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	lb.weights = weights
+}
+
+// Select returns the index of the endpoint to send the next request to.
+func (lb *weightedLoadBalancer) Select(candidates []LoadBalancerCandidate) int {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	best, total := 0, 0
+	for i, c := range candidates {
+		weight := lb.weights[c.Endpoint]
+		if weight <= 0 {
+			weight = 1
+		}
+		total += weight
+		lb.current[c.Endpoint] += weight
+		if lb.current[c.Endpoint] > lb.current[candidates[best].Endpoint] {
+			best = i
+		}
+	}
+	lb.current[candidates[best].Endpoint] -= total
+	return best
+}
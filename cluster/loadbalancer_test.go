@@ -0,0 +1,127 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster_test
+
+import (
+	"context"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/cluster"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+func testCandidates(outstanding ...int) []cluster.LoadBalancerCandidate {
+	result := make([]cluster.LoadBalancerCandidate, len(outstanding))
+	for i, x := range outstanding {
+		result[i] = cluster.LoadBalancerCandidate{Endpoint: string('a' + rune(i)), Outstanding: x}
+	}
+	return result
+}
+
+func TestRoundRobinLoadBalancer(t *testing.T) {
+	lb := cluster.NewRoundRobinLoadBalancer()
+	candidates := testCandidates(0, 0, 0)
+	for i, expected := range []int{0, 1, 2, 0, 1} {
+		if selected := lb.Select(candidates); selected != expected {
+			t.Errorf("Selection %d: expected %d, got %d", i, expected, selected)
+		}
+	}
+}
+
+func TestRandomLoadBalancer(t *testing.T) {
+	lb := cluster.NewRandomLoadBalancer()
+	candidates := testCandidates(0, 0)
+	for i := 0; i < 100; i++ {
+		if selected := lb.Select(candidates); selected < 0 || selected > 1 {
+			t.Fatalf("Selection out of range: %d", selected)
+		}
+	}
+}
+
+func TestLeastOutstandingLoadBalancer(t *testing.T) {
+	lb := cluster.NewLeastOutstandingLoadBalancer()
+	for i := 0; i < 3; i++ {
+		if selected := lb.Select(testCandidates(4, 1, 3)); selected != 1 {
+			t.Errorf("Expected 1, got %d", selected)
+		}
+	}
+	// Ties are spread
+	seen := make(map[int]bool)
+	for i := 0; i < 3; i++ {
+		seen[lb.Select(testCandidates(2, 2, 2))] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected ties to be spread over all candidates, got %v", seen)
+	}
+}
+
+func TestWeightedLoadBalancer(t *testing.T) {
+	lb := cluster.NewWeightedLoadBalancer(map[string]int{"a": 5, "b": 1})
+	candidates := testCandidates(0, 0, 0) // "c" has no weight, so 1
+	counts := make([]int, len(candidates))
+	for i := 0; i < 70; i++ {
+		counts[lb.Select(candidates)]++
+	}
+	if counts[0] != 50 || counts[1] != 10 || counts[2] != 10 {
+		t.Errorf("Expected 50/10/10 distribution, got %v", counts)
+	}
+}
+
+func TestLoadBalancerDistributesAndKeepsPinning(t *testing.T) {
+	servers := []*toggleServer{newToggleServer(), newToggleServer()}
+	defer servers[0].Close()
+	defer servers[1].Close()
+
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: []string{servers[0].URL, servers[1].URL},
+		ConnectionConfig: cluster.ConnectionConfig{
+			LoadBalancer: cluster.NewRoundRobinLoadBalancer(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	do := func(ctx context.Context) {
+		req, _ := conn.NewRequest("GET", "/_api/version")
+		if _, err := conn.Do(ctx, req); err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		do(context.Background())
+	}
+	if servers[0].requests != 5 || servers[1].requests != 5 {
+		t.Errorf("Expected 5 requests per server, got %d & %d", servers[0].requests, servers[1].requests)
+	}
+
+	// Pinned requests stay on their endpoint
+	ctx := driver.WithEndpoint(context.Background(), servers[1].URL)
+	for i := 0; i < 4; i++ {
+		do(ctx)
+	}
+	if servers[0].requests != 5 || servers[1].requests != 9 {
+		t.Errorf("Expected pinned requests on second server, got %d & %d", servers[0].requests, servers[1].requests)
+	}
+}
@@ -0,0 +1,127 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// withRetryDefaults returns the given policy with all zero fields replaced by their defaults.
+func withRetryDefaults(p driver.RetryPolicy) driver.RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = driver.DefaultRetryMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = driver.DefaultRetryInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = driver.DefaultRetryMaxBackoff
+	}
+	if p.BackoffMultiplier < 1 {
+		p.BackoffMultiplier = driver.DefaultRetryBackoffMultiplier
+	}
+	if p.Jitter == 0 {
+		p.Jitter = driver.DefaultRetryJitter
+	} else if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if p.RetryStatusCodes == nil {
+		p.RetryStatusCodes = driver.DefaultRetryStatusCodes
+	}
+	return p
+}
+
+// retryBackoff returns the delay before the given retry (1 for the first retry).
+func retryBackoff(p driver.RetryPolicy, retry int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.BackoffMultiplier, float64(retry-1))
+	delay = math.Min(delay, float64(p.MaxBackoff))
+	if p.Jitter > 0 {
+		delay = delay*(1-p.Jitter) + rand.Float64()*delay*p.Jitter
+	}
+	return time.Duration(delay)
+}
+
+// isRetryable returns true if the given policy allows retrying a request that resulted
+// in the given response or error.
+func isRetryable(p driver.RetryPolicy, req driver.Request, resp driver.Response, err error) bool {
+	if err != nil {
+		if driver.IsCanceled(err) {
+			return false
+		}
+		if !req.Written() {
+			// The request never reached a server
+			return true
+		}
+		return p.RetryNonIdempotent || isIdempotent(req.Method())
+	}
+	if resp == nil || !(p.RetryNonIdempotent || isIdempotent(req.Method())) {
+		return false
+	}
+	statusCode := resp.StatusCode()
+	for _, x := range p.RetryStatusCodes {
+		if statusCode == x {
+			return true
+		}
+	}
+	if len(p.RetryErrorNums) > 0 && statusCode >= 400 {
+		var aerr driver.ArangoError
+		if perr := resp.ParseBody("", &aerr); perr == nil && aerr.HasError {
+			for _, x := range p.RetryErrorNums {
+				if aerr.ErrorNum == x {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isIdempotent returns true if sending a request with the given method more than once
+// has the same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepWithContext waits for the given duration, or until the given context is done.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return driver.WithStack(ctx.Err())
+	}
+}
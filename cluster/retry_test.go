@@ -0,0 +1,245 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/cluster"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+// scriptedHandler answers the n-th request (1 based) with the result of the given function,
+// returning a status code and a body.
+func scriptedHandler(count *int32, f func(n int32) (int, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, body := f(atomic.AddInt32(count, 1))
+		if status == 0 {
+			// Drop the connection after the request has been read
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				panic("no hijacker")
+			}
+			conn, _, _ := hj.Hijack()
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// newTestConnection creates a cluster connection to the given number of servers,
+// all using the given handler.
+// The returned function closes the servers.
+func newTestConnection(t *testing.T, servers int, handler http.Handler, policy *driver.RetryPolicy) (driver.Connection, func()) {
+	var endpoints []string
+	var closers []func()
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+	for i := 0; i < servers; i++ {
+		s := httptest.NewServer(handler)
+		closers = append(closers, s.Close)
+		endpoints = append(endpoints, s.URL)
+	}
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: endpoints,
+		ConnectionConfig: cluster.ConnectionConfig{
+			RetryPolicy: policy,
+		},
+	})
+	if err != nil {
+		closeAll()
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	return conn, closeAll
+}
+
+func doRequest(t *testing.T, ctx context.Context, conn driver.Connection, method string) (driver.Response, error) {
+	req, err := conn.NewRequest(method, "/_api/version")
+	if err != nil {
+		t.Fatalf("NewRequest failed: %s", err)
+	}
+	return conn.Do(ctx, req)
+}
+
+var fastRetries = &driver.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond * 5,
+}
+
+func TestRetryPolicyStatusCode(t *testing.T) {
+	var count int32
+	conn, closeServers := newTestConnection(t, 2, scriptedHandler(&count, func(n int32) (int, string) {
+		if n == 1 {
+			return 503, `{"error":true,"code":503,"errorNum":503,"errorMessage":"unavailable"}`
+		}
+		return 200, `{"version":"3.7.0"}`
+	}), fastRetries)
+	defer closeServers()
+
+	resp, err := doRequest(t, context.Background(), conn, "GET")
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 200 {
+		t.Errorf("Expected 200, got %d", resp.StatusCode())
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 attempts, got %d", count)
+	}
+}
+
+func TestRetryPolicyErrorNum(t *testing.T) {
+	var count int32
+	policy := *fastRetries
+	policy.RetryErrorNums = []int{1200}
+	conn, closeServers := newTestConnection(t, 1, scriptedHandler(&count, func(n int32) (int, string) {
+		if n == 1 {
+			return 409, `{"error":true,"code":409,"errorNum":1200,"errorMessage":"conflict"}`
+		}
+		return 200, `{"version":"3.7.0"}`
+	}), &policy)
+	defer closeServers()
+
+	resp, err := doRequest(t, context.Background(), conn, "GET")
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 200 {
+		t.Errorf("Expected 200, got %d", resp.StatusCode())
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 attempts, got %d", count)
+	}
+}
+
+func TestRetryPolicyNonIdempotent(t *testing.T) {
+	unavailable := func(n int32) (int, string) {
+		return 503, `{"error":true,"code":503,"errorNum":503,"errorMessage":"unavailable"}`
+	}
+
+	var count int32
+	conn, closeServers := newTestConnection(t, 1, scriptedHandler(&count, unavailable), fastRetries)
+	defer closeServers()
+	resp, err := doRequest(t, context.Background(), conn, "POST")
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 503 || count != 1 {
+		t.Errorf("Expected a single 503 response, got %d after %d attempts", resp.StatusCode(), count)
+	}
+
+	count = 0
+	policy := *fastRetries
+	policy.RetryNonIdempotent = true
+	conn, closeServers2 := newTestConnection(t, 1, scriptedHandler(&count, unavailable), &policy)
+	defer closeServers2()
+	resp, err = doRequest(t, context.Background(), conn, "POST")
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 503 || count != 3 {
+		t.Errorf("Expected a 503 response after 3 attempts, got %d after %d attempts", resp.StatusCode(), count)
+	}
+}
+
+func TestRetryPolicyAttemptsInError(t *testing.T) {
+	var count int32
+	conn, closeServers := newTestConnection(t, 2, scriptedHandler(&count, func(n int32) (int, string) {
+		return 0, ""
+	}), fastRetries)
+	defer closeServers()
+
+	_, err := doRequest(t, context.Background(), conn, "GET")
+	if !driver.IsRetry(err) {
+		t.Fatalf("Expected RetryError, got %v", err)
+	}
+	if attempts := driver.Cause(err).(*driver.RetryError).Attempts; attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if !driver.IsResponse(err) {
+		t.Errorf("Expected ResponseError cause, got %v", err)
+	}
+}
+
+func TestRetryPolicyContextOverride(t *testing.T) {
+	var count int32
+	conn, closeServers := newTestConnection(t, 1, scriptedHandler(&count, func(n int32) (int, string) {
+		return 429, `{"error":true,"code":429,"errorNum":429,"errorMessage":"too many requests"}`
+	}), fastRetries)
+	defer closeServers()
+
+	ctx := driver.WithRetryPolicy(context.Background(), driver.RetryPolicy{MaxAttempts: 1})
+	resp, err := doRequest(t, ctx, conn, "GET")
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 429 || count != 1 {
+		t.Errorf("Expected a single 429 response, got %d after %d attempts", resp.StatusCode(), count)
+	}
+}
+
+func TestRetryPolicyNoLeaderWithoutFollowLeaderRedirect(t *testing.T) {
+	var count int32
+	conn, closeServers := newTestConnection(t, 2, scriptedHandler(&count, func(n int32) (int, string) {
+		return 503, `{"error":true,"code":503,"errorNum":1496,"errorMessage":"no leader"}`
+	}), fastRetries)
+	defer closeServers()
+
+	ctx := driver.WithFollowLeaderRedirect(context.Background(), false)
+	_, err := doRequest(t, ctx, conn, "GET")
+	if !driver.IsNoLeader(err) {
+		t.Fatalf("Expected a no leader error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected a single attempt, got %d", count)
+	}
+}
+
+func TestNoRetryPolicy(t *testing.T) {
+	var count int32
+	conn, closeServers := newTestConnection(t, 2, scriptedHandler(&count, func(n int32) (int, string) {
+		return 503, `{"error":true,"code":503,"errorNum":503,"errorMessage":"unavailable"}`
+	}), nil)
+	defer closeServers()
+
+	resp, err := doRequest(t, context.Background(), conn, "GET")
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 503 || count != 1 {
+		t.Errorf("Expected a single 503 response, got %d after %d attempts", resp.StatusCode(), count)
+	}
+}
@@ -0,0 +1,183 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultResolveInterval is the default interval between re-resolutions of `srv://` endpoints.
+	DefaultResolveInterval = time.Minute
+
+	// resolveTimeout is the timeout of a single resolution of the `srv://` endpoints.
+	resolveTimeout = 30 * time.Second
+)
+
+// Resolver looks up DNS SRV records.
+// It is implemented by *net.Resolver.
+type Resolver interface {
+	// LookupSRV returns the SRV records of the given service, protocol & domain name.
+	// The service & protocol are empty when looking up the name directly.
+	LookupSRV(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+}
+
+// IsSRVEndpoint returns true if the given endpoint URL must be resolved using DNS SRV records.
+// E.g. "srv://_arangodb._tcp.example.com" or "srv+https://_arangodb._tcp.example.com"
+func IsSRVEndpoint(endpoint string) bool {
+	lower := strings.ToLower(endpoint)
+	return strings.HasPrefix(lower, "srv://") || strings.HasPrefix(lower, "srv+http://") || strings.HasPrefix(lower, "srv+https://")
+}
+
+// srvTarget is an endpoint resolved from an SRV record.
+type srvTarget struct {
+	priority uint16
+	weight   uint16
+}
+
+// resolveSRVEndpoint resolves the given `srv://` endpoint into the endpoints of its SRV records.
+func resolveSRVEndpoint(ctx context.Context, resolver Resolver, endpoint string) (map[string]srvTarget, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	scheme := "http"
+	if strings.ToLower(u.Scheme) == "srv+https" {
+		scheme = "https"
+	}
+	_, addrs, err := resolver.LookupSRV(ctx, "", "", u.Host)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	result := make(map[string]srvTarget, len(addrs))
+	for _, addr := range addrs {
+		host := strings.TrimSuffix(addr.Target, ".")
+		ep := scheme + "://" + net.JoinHostPort(host, strconv.Itoa(int(addr.Port)))
+		result[ep] = srvTarget{priority: addr.Priority, weight: addr.Weight}
+	}
+	return result, nil
+}
+
+// filterByPriority removes the candidates that do not have the best (lowest) priority
+// among the given candidates.
+// Endpoints that are not resolved from SRV records have priority 0.
+func filterByPriority(candidates []LoadBalancerCandidate, indexes []int, targets map[string]srvTarget) ([]LoadBalancerCandidate, []int) {
+	if len(targets) == 0 {
+		return candidates, indexes
+	}
+	best := -1
+	for _, c := range candidates {
+		if p := int(targets[c.Endpoint].priority); best < 0 || p < best {
+			best = p
+		}
+	}
+	var resultCandidates []LoadBalancerCandidate
+	var resultIndexes []int
+	for i, c := range candidates {
+		if int(targets[c.Endpoint].priority) == best {
+			resultCandidates = append(resultCandidates, c)
+			resultIndexes = append(resultIndexes, indexes[i])
+		}
+	}
+	return resultCandidates, resultIndexes
+}
+
+// FakeResolver is a Resolver that returns configured SRV records, intended for tests.
+type FakeResolver struct {
+	mutex   sync.Mutex
+	records map[string][]*net.SRV
+	errors  map[string]error
+	lookups int
+}
+
+// NewFakeResolver creates a new FakeResolver without any records.
+func NewFakeResolver() *FakeResolver {
+	return &FakeResolver{
+		records: make(map[string][]*net.SRV),
+		errors:  make(map[string]error),
+	}
+}
+
+// SetRecords replaces the SRV records of the given name.
+func (r *FakeResolver) SetRecords(name string, records ...*net.SRV) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.records[name] = records
+	delete(r.errors, name)
+}
+
+// SetError makes lookups of the given name fail with the given error.
+func (r *FakeResolver) SetError(name string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors[name] = err
+}
+
+// Lookups returns the number of lookups performed so far.
+func (r *FakeResolver) Lookups() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.lookups
+}
+
+// LookupSRV returns the SRV records configured for the given name, sorted by priority.
+func (r *FakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lookups++
+	if service != "" || proto != "" {
+		name = "_" + service + "._" + proto + "." + name
+	}
+	if err := r.errors[name]; err != nil {
+		return "", nil, err
+	}
+	records, found := r.records[name]
+	if !found {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name}
+	}
+	result := make([]*net.SRV, len(records))
+	for i, rec := range records {
+		clone := *rec
+		result[i] = &clone
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Priority < result[j].Priority })
+	return name, result, nil
+}
+
+// resolveState holds the state of the resolution of `srv://` endpoints of a cluster connection.
+type resolveState struct {
+	mutex       sync.Mutex
+	srv         []string // Endpoints to resolve
+	static      []string // Endpoints that need no resolution
+	lastResolve time.Time
+	resolving   bool
+}
@@ -0,0 +1,256 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package cluster_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/cluster"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+const testSRVName = "_arangodb._tcp.example.com"
+
+// srvRecord returns an SRV record pointing to the given test server.
+func srvRecord(t *testing.T, server *toggleServer, priority, weight uint16) *net.SRV {
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Parse failed: %s", err)
+	}
+	host, portStr, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatalf("SplitHostPort failed: %s", err)
+	}
+	port, _ := strconv.Atoi(portStr)
+	return &net.SRV{Target: host + ".", Port: uint16(port), Priority: priority, Weight: weight}
+}
+
+// doRequests sends the given number of requests.
+func doRequests(t *testing.T, conn driver.Connection, count int) {
+	for i := 0; i < count; i++ {
+		req, _ := conn.NewRequest("GET", "/_api/version")
+		if _, err := conn.Do(context.Background(), req); err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+	}
+}
+
+func TestSRVEndpointsPriorityAndWeight(t *testing.T) {
+	servers := []*toggleServer{newToggleServer(), newToggleServer(), newToggleServer()}
+	for _, s := range servers {
+		defer s.Close()
+	}
+	resolver := cluster.NewFakeResolver()
+	resolver.SetRecords(testSRVName,
+		srvRecord(t, servers[0], 10, 3),
+		srvRecord(t, servers[1], 10, 1),
+		srvRecord(t, servers[2], 20, 1),
+	)
+
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: []string{"srv://" + testSRVName},
+		ConnectionConfig: cluster.ConnectionConfig{
+			Resolver:        resolver,
+			ResolveInterval: -1,
+			CircuitBreaker:  &cluster.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	if eps := conn.Endpoints(); len(eps) != 3 {
+		t.Errorf("Expected 3 endpoints, got %v", eps)
+	}
+
+	// Requests are distributed over the best priority by weight
+	doRequests(t, conn, 40)
+	if servers[0].requests != 30 || servers[1].requests != 10 || servers[2].requests != 0 {
+		t.Errorf("Expected 30/10/0 distribution, got %d/%d/%d", servers[0].requests, servers[1].requests, servers[2].requests)
+	}
+
+	// When the best priority is unavailable, the next priority is used
+	atomic.StoreInt32(&servers[0].down, 1)
+	atomic.StoreInt32(&servers[1].down, 1)
+	doRequests(t, conn, 2) // Opens both circuit breakers
+	before := atomic.LoadInt32(&servers[2].requests)
+	doRequests(t, conn, 5)
+	if after := atomic.LoadInt32(&servers[2].requests); after-before != 5 {
+		t.Errorf("Expected 5 requests on lower priority server, got %d", after-before)
+	}
+	if resolver.Lookups() != 1 {
+		t.Errorf("Expected 1 lookup, got %d", resolver.Lookups())
+	}
+}
+
+func TestSRVEndpointsReresolve(t *testing.T) {
+	servers := []*toggleServer{newToggleServer(), newToggleServer()}
+	defer servers[0].Close()
+	defer servers[1].Close()
+	resolver := cluster.NewFakeResolver()
+	resolver.SetRecords(testSRVName, srvRecord(t, servers[0], 0, 0))
+
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: []string{"srv://" + testSRVName},
+		ConnectionConfig: cluster.ConnectionConfig{
+			Resolver:        resolver,
+			ResolveInterval: time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	if eps := conn.Endpoints(); len(eps) != 1 || eps[0] != servers[0].URL {
+		t.Errorf("Expected endpoints [%s], got %v", servers[0].URL, eps)
+	}
+
+	// A failed lookup keeps the current endpoints
+	resolver.SetError(testSRVName, &net.DNSError{Err: "timeout", Name: testSRVName, IsTimeout: true})
+	time.Sleep(5 * time.Millisecond)
+	doRequests(t, conn, 1)
+	deadline := time.Now().Add(5 * time.Second)
+	for resolver.Lookups() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if eps := conn.Endpoints(); len(eps) != 1 || eps[0] != servers[0].URL {
+		t.Errorf("Expected endpoints [%s], got %v", servers[0].URL, eps)
+	}
+
+	// Changed records are picked up by re-resolution
+	resolver.SetRecords(testSRVName, srvRecord(t, servers[1], 0, 0))
+	for time.Now().Before(deadline) {
+		if eps := conn.Endpoints(); len(eps) == 1 && eps[0] == servers[1].URL {
+			break
+		}
+		time.Sleep(2 * time.Millisecond)
+		doRequests(t, conn, 1)
+	}
+	if eps := conn.Endpoints(); len(eps) != 1 || eps[0] != servers[1].URL {
+		t.Fatalf("Expected endpoints [%s], got %v", servers[1].URL, eps)
+	}
+	before := atomic.LoadInt32(&servers[1].requests)
+	doRequests(t, conn, 3)
+	if after := atomic.LoadInt32(&servers[1].requests); after-before != 3 {
+		t.Errorf("Expected 3 requests on new server, got %d", after-before)
+	}
+}
+
+func TestSRVEndpointsReresolveWithSetAuthentication(t *testing.T) {
+	var lastAuth atomic.Value
+	servers := make([]*toggleServer, 2)
+	for i := range servers {
+		servers[i] = &toggleServer{Server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lastAuth.Store(r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"version":"3.7.0"}`))
+		}))}
+		defer servers[i].Close()
+	}
+	resolver := cluster.NewFakeResolver()
+	resolver.SetRecords(testSRVName, srvRecord(t, servers[0], 0, 0))
+
+	// A slow connection builder widens the window in which authentication can change.
+	builder := func(endpoint string) (driver.Connection, error) {
+		time.Sleep(time.Millisecond)
+		return driverhttp.NewConnection(driverhttp.ConnectionConfig{Endpoints: []string{endpoint}})
+	}
+	conn, err := cluster.NewConnection(cluster.ConnectionConfig{
+		Resolver:        resolver,
+		ResolveInterval: time.Millisecond,
+	}, builder, []string{"srv://" + testSRVName})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+
+	// Re-resolve to alternating servers, while changing the authentication
+	waitForEndpoint := func(server *toggleServer) {
+		resolver.SetRecords(testSRVName, srvRecord(t, server, 0, 0))
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if eps := conn.Endpoints(); len(eps) == 1 && eps[0] == server.URL {
+				return
+			}
+			time.Sleep(100 * time.Microsecond)
+			doRequests(t, conn, 1)
+		}
+		t.Fatalf("Expected endpoints [%s], got %v", server.URL, conn.Endpoints())
+	}
+	stop := make(chan struct{})
+	last := make(chan string, 1)
+	stopped := false
+	defer func() {
+		if !stopped {
+			close(stop)
+		}
+	}()
+	go func() {
+		for i := 0; ; i++ {
+			password := strconv.Itoa(i)
+			if _, err := conn.SetAuthentication(driver.BasicAuthentication("root", password)); err != nil {
+				t.Errorf("SetAuthentication failed: %s", err)
+			}
+			select {
+			case <-stop:
+				last <- password
+				return
+			default:
+			}
+		}
+	}()
+	for i := 1; i <= 20; i++ {
+		waitForEndpoint(servers[i%2])
+	}
+	close(stop)
+	stopped = true
+	password := <-last
+
+	// The servers of a later re-resolution use the last authentication
+	waitForEndpoint(servers[1])
+	waitForEndpoint(servers[0])
+	doRequests(t, conn, 1)
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("root:"+password))
+	if auth := lastAuth.Load(); auth != expected {
+		t.Errorf("Expected authorization %q, got %q", expected, auth)
+	}
+}
+
+func TestSRVEndpointsLookupFailure(t *testing.T) {
+	_, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: []string{"srv://" + testSRVName},
+		ConnectionConfig: cluster.ConnectionConfig{
+			Resolver: cluster.NewFakeResolver(),
+		},
+	})
+	if err == nil {
+		t.Error("Expected an error for an unknown name")
+	}
+}
@@ -0,0 +1,64 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import "context"
+
+// WithEdgesVertexCollections is used to configure a context that restricts the results
+// of the CollectionEdges functions to edges whose vertex on the other side (seen from the
+// requested vertex) is in one of the given collections.
+func WithEdgesVertexCollections(parent context.Context, collections ...string) context.Context {
+	return context.WithValue(contextOrBackground(parent), keyEdgesVertexCollections, collections)
+}
+
+// EdgeCollection is a collection of type CollectionTypeEdge.
+// Collections of that type returned by Database.Collection, Database.Collections & Database.CreateCollection,
+// as well as the edge collections of a Graph implement this interface. Use a type assertion to access it:
+//
+//	edges, err := col.(driver.EdgeCollection).Edges(ctx, vertex, driver.EdgeDirectionOut, nil)
+type EdgeCollection interface {
+	Collection
+
+	// All edge functions
+	CollectionEdges
+}
+
+// CollectionEdges provides access to the edges of vertices in a single edge collection.
+type CollectionEdges interface {
+	// Edges returns the edges in this collection that are connected to the given vertex, in the given direction.
+	// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+	// in the same order as the returned edge objects.
+	// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+	Edges(ctx context.Context, vertex DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error)
+
+	// EdgesOfVertices returns the edges in this collection that are connected to any of the given vertices, in the given direction.
+	// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+	// in the same order as the returned edge objects.
+	// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+	EdgesOfVertices(ctx context.Context, vertices []DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error)
+
+	// CountEdges returns the number of edges in this collection that are connected to the given vertex, in the given direction.
+	// The edges are counted by the server, they are not transferred.
+	// To only count edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+	CountEdges(ctx context.Context, vertex DocumentID, direction EdgeDirection) (int64, error)
+}
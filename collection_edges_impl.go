@@ -0,0 +1,282 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// edges returns the edges in this collection that are connected to the given vertex, in the given direction.
+// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+// in the same order as the returned edge objects.
+// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *collection) edges(ctx context.Context, vertex DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	raw, err := c.readEdges(ctx, vertex, direction)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	edges, err := c.decodeEdges(ctx, raw, []DocumentID{vertex}, direction, results)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return edges, nil
+}
+
+// edgesOfVertices returns the edges in this collection that are connected to any of the given vertices, in the given direction.
+// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+// in the same order as the returned edge objects.
+// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *collection) edgesOfVertices(ctx context.Context, vertices []DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	for _, v := range vertices {
+		if err := v.Validate(); err != nil {
+			return nil, WithStack(InvalidArgumentError{Message: err.Error()})
+		}
+	}
+	var filter string
+	switch direction {
+	case EdgeDirectionIn:
+		filter = "e._to IN @vertices"
+	case EdgeDirectionOut:
+		filter = "e._from IN @vertices"
+	case EdgeDirectionAny, "":
+		filter = "e._from IN @vertices OR e._to IN @vertices"
+	default:
+		return nil, WithStack(InvalidArgumentError{Message: fmt.Sprintf("unknown direction '%s'", direction)})
+	}
+	var raw []RawObject
+	if len(vertices) > 0 {
+		ids := make([]string, len(vertices))
+		for i, v := range vertices {
+			ids[i] = v.String()
+		}
+		bindVars := map[string]interface{}{
+			"@collection": c.name,
+			"vertices":    ids,
+		}
+		cursor, err := c.db.Query(ctx, "FOR e IN @@collection FILTER "+filter+" RETURN e", bindVars)
+		if err != nil {
+			return nil, WithStack(err)
+		}
+		defer cursor.Close()
+		for cursor.HasMore() {
+			var doc RawObject
+			if _, err := cursor.ReadDocument(ctx, &doc); err != nil {
+				return nil, WithStack(err)
+			}
+			raw = append(raw, doc)
+		}
+	}
+	edges, err := c.decodeEdges(ctx, raw, vertices, direction, results)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return edges, nil
+}
+
+// countEdges returns the number of edges in this collection that are connected to the given vertex, in the given direction.
+// To only count edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *collection) countEdges(ctx context.Context, vertex DocumentID, direction EdgeDirection) (int64, error) {
+	if err := vertex.Validate(); err != nil {
+		return 0, WithStack(InvalidArgumentError{Message: err.Error()})
+	}
+	var filter string
+	switch direction {
+	case EdgeDirectionIn:
+		filter = "e._to == @vertex"
+	case EdgeDirectionOut:
+		filter = "e._from == @vertex"
+	case EdgeDirectionAny, "":
+		filter = "e._from == @vertex OR e._to == @vertex"
+	default:
+		return 0, WithStack(InvalidArgumentError{Message: fmt.Sprintf("unknown direction '%s'", direction)})
+	}
+	bindVars := map[string]interface{}{
+		"@collection": c.name,
+		"vertex":      vertex.String(),
+	}
+	if ctx != nil {
+		if collections, ok := ctx.Value(keyEdgesVertexCollections).([]string); ok && len(collections) > 0 {
+			// Filter on the collection of the vertex on the other side
+			switch direction {
+			case EdgeDirectionIn:
+				filter = "e._to == @vertex AND PARSE_IDENTIFIER(e._from).collection IN @collections"
+			case EdgeDirectionOut:
+				filter = "e._from == @vertex AND PARSE_IDENTIFIER(e._to).collection IN @collections"
+			default:
+				filter = "(e._from == @vertex AND PARSE_IDENTIFIER(e._to).collection IN @collections) OR " +
+					"(e._to == @vertex AND PARSE_IDENTIFIER(e._from).collection IN @collections)"
+			}
+			bindVars["collections"] = collections
+		}
+	}
+	cursor, err := c.db.Query(ctx, "FOR e IN @@collection FILTER "+filter+" COLLECT WITH COUNT INTO n RETURN n", bindVars)
+	if err != nil {
+		return 0, WithStack(err)
+	}
+	defer cursor.Close()
+	var count int64
+	if _, err := cursor.ReadDocument(ctx, &count); err != nil {
+		return 0, WithStack(err)
+	}
+	return count, nil
+}
+
+// readEdges fetches the encoded edges of the given vertex, using the edges API.
+func (c *collection) readEdges(ctx context.Context, vertex DocumentID, direction EdgeDirection) ([]RawObject, error) {
+	if err := vertex.Validate(); err != nil {
+		return nil, WithStack(InvalidArgumentError{Message: err.Error()})
+	}
+	req, err := c.conn.NewRequest("GET", c.relPath("edges"))
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	req.SetQuery("vertex", vertex.String())
+	switch direction {
+	case EdgeDirectionIn, EdgeDirectionOut:
+		req.SetQuery("direction", string(direction))
+	case EdgeDirectionAny, "":
+		// Default of the server
+	default:
+		return nil, WithStack(InvalidArgumentError{Message: fmt.Sprintf("unknown direction '%s'", direction)})
+	}
+	resp, err := c.conn.Do(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, WithStack(err)
+	}
+	var data []RawObject
+	if err := resp.ParseBody("edges", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	return data, nil
+}
+
+// decodeEdges decodes the given encoded edges, applies the vertex collection filter of the given context
+// and stores the full edge documents into results (if not nil).
+func (c *collection) decodeEdges(ctx context.Context, raw []RawObject, vertices []DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	var resultsVal reflect.Value
+	if results != nil {
+		resultsVal = reflect.ValueOf(results)
+		if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+			return nil, WithStack(InvalidArgumentError{Message: fmt.Sprintf("results must be a pointer to a slice, got %s", resultsVal.Kind())})
+		}
+	}
+	var collections []string
+	if ctx != nil {
+		if v, ok := ctx.Value(keyEdgesVertexCollections).([]string); ok {
+			collections = v
+		}
+	}
+	var slice reflect.Value
+	if results != nil {
+		slice = reflect.MakeSlice(resultsVal.Elem().Type(), 0, len(raw))
+	}
+	edges := make([]EdgeObject, 0, len(raw))
+	for _, r := range raw {
+		var e EdgeObject
+		if err := c.conn.Unmarshal(r, &e); err != nil {
+			return nil, WithStack(err)
+		}
+		if len(collections) > 0 && !edgeMatchesVertexCollections(e, vertices, direction, collections) {
+			continue
+		}
+		edges = append(edges, e)
+		if results != nil {
+			elem := reflect.New(slice.Type().Elem())
+			if err := c.conn.Unmarshal(r, elem.Interface()); err != nil {
+				return nil, WithStack(err)
+			}
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+	if results != nil {
+		resultsVal.Elem().Set(slice)
+	}
+	return edges, nil
+}
+
+// edgeMatchesVertexCollections returns true if the vertex on the other side of the given edge,
+// seen from any of the given vertices, is in one of the given collections.
+func edgeMatchesVertexCollections(e EdgeObject, vertices []DocumentID, direction EdgeDirection, collections []string) bool {
+	var others []DocumentID
+	for _, v := range vertices {
+		if (direction == EdgeDirectionOut || direction == EdgeDirectionAny || direction == "") && e.From == v {
+			others = append(others, e.To)
+		}
+		if (direction == EdgeDirectionIn || direction == EdgeDirectionAny || direction == "") && e.To == v {
+			others = append(others, e.From)
+		}
+	}
+	for _, other := range others {
+		for _, name := range collections {
+			if other.Collection() == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// edgeTypeCollection is a collection of type CollectionTypeEdge.
+// It implements EdgeCollection.
+type edgeTypeCollection struct {
+	*collection
+}
+
+// Edges returns the edges in this collection that are connected to the given vertex, in the given direction.
+// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+// in the same order as the returned edge objects.
+// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *edgeTypeCollection) Edges(ctx context.Context, vertex DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	result, err := c.edges(ctx, vertex, direction, results)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return result, nil
+}
+
+// EdgesOfVertices returns the edges in this collection that are connected to any of the given vertices, in the given direction.
+// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+// in the same order as the returned edge objects.
+// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *edgeTypeCollection) EdgesOfVertices(ctx context.Context, vertices []DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	result, err := c.edgesOfVertices(ctx, vertices, direction, results)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return result, nil
+}
+
+// CountEdges returns the number of edges in this collection that are connected to the given vertex, in the given direction.
+// To only count edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *edgeTypeCollection) CountEdges(ctx context.Context, vertex DocumentID, direction EdgeDirection) (int64, error) {
+	result, err := c.countEdges(ctx, vertex, direction)
+	if err != nil {
+		return 0, WithStack(err)
+	}
+	return result, nil
+}
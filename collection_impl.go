@@ -42,6 +42,19 @@ func newCollection(name string, db *database) (Collection, error) {
 	}, nil
 }
 
+// newCollectionOfType creates a new Collection implementation for a collection of given type.
+// Collections of type CollectionTypeEdge implement EdgeCollection.
+func newCollectionOfType(name string, db *database, colType CollectionType) (Collection, error) {
+	col, err := newCollection(name, db)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if colType == CollectionTypeEdge {
+		return &edgeTypeCollection{collection: col.(*collection)}, nil
+	}
+	return col, nil
+}
+
 type collection struct {
 	name string
 	db   *database
@@ -60,7 +60,7 @@ func (c *collection) Index(ctx context.Context, name string) (Index, error) {
 	if err := resp.ParseBody("", &data); err != nil {
 		return nil, WithStack(err)
 	}
-	idx, err := newIndex(data.ID, data.Type, c)
+	idx, err := newIndex(data, c)
 	if err != nil {
 		return nil, WithStack(err)
 	}
@@ -106,7 +106,7 @@ func (c *collection) Indexes(ctx context.Context) ([]Index, error) {
 	}
 	result := make([]Index, 0, len(data.Indexes))
 	for _, x := range data.Indexes {
-		idx, err := newIndex(x.ID, x.Type, c)
+		idx, err := newIndex(x, c)
 		if err != nil {
 			return nil, WithStack(err)
 		}
@@ -248,7 +248,7 @@ func (c *collection) ensureIndex(ctx context.Context, options indexData) (Index,
 	if err := resp.ParseBody("", &data); err != nil {
 		return nil, false, WithStack(err)
 	}
-	idx, err := newIndex(data.ID, data.Type, c)
+	idx, err := newIndex(data, c)
 	if err != nil {
 		return nil, false, WithStack(err)
 	}
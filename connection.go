@@ -56,6 +56,10 @@ type Connection interface {
 
 // Request represents the input to a request on the server.
 type Request interface {
+	// Method returns the method of the request (GET, POST, ...).
+	Method() string
+	// Path returns the path of the request, relative to the endpoint.
+	Path() string
 	// SetQuery sets a single query argument of the request.
 	// Any existing query argument with the same key is overwritten.
 	SetQuery(key, value string) Request
@@ -81,6 +85,15 @@ type Request interface {
 	Clone() Request
 }
 
+// RawBodyRequest is implemented by requests that can be sent with a raw body.
+// The requests of the HTTP & VST connections implement it.
+type RawBodyRequest interface {
+	Request
+	// SetRawBody sets the content of the request to the given bytes, sent as-is with the given content type
+	// (e.g. "application/zip"). No marshalling is taking place.
+	SetRawBody(body []byte, contentType string) (Request, error)
+}
+
 // Response represents the response from the server on a given request.
 type Response interface {
 	// StatusCode returns an HTTP compatible status code of the response.
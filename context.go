@@ -57,6 +57,9 @@ const (
 	keyDBServerID               ContextKey = "arangodb-dbserverID"
 	keyBatchID                  ContextKey = "arangodb-batchID"
 	keyJobIDResponse            ContextKey = "arangodb-jobIDResponse"
+	keyRetryPolicy              ContextKey = "arangodb-retryPolicy"
+	keyTraceParent              ContextKey = "arangodb-traceParent"
+	keyEdgesVertexCollections   ContextKey = "arangodb-edges-vertexCollections"
 )
 
 // WithRevision is used to configure a context to make document
@@ -221,6 +224,13 @@ func WithJobIDResponse(parent context.Context, jobID *string) context.Context {
 	return context.WithValue(contextOrBackground(parent), keyJobIDResponse, jobID)
 }
 
+// WithRetryPolicy is used to configure a context that overrides the RetryPolicy
+// of a cluster connection for the requests made with it.
+// Use a policy with MaxAttempts set to 1 to disable retries.
+func WithRetryPolicy(parent context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(contextOrBackground(parent), keyRetryPolicy, policy)
+}
+
 type contextSettings struct {
 	Silent                   bool
 	WaitForSync              bool
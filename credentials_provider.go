@@ -0,0 +1,121 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import "context"
+
+// CredentialsProvider provides the credentials used to authenticate requests.
+// Connections consult the provider before making requests, so the credentials
+// can change (e.g. rotated passwords) without creating new clients.
+type CredentialsProvider interface {
+	// Credentials returns the authentication to use for the next requests.
+	// Connections compare the result with the previously returned authentication,
+	// so implementations must return the same Authentication as long as the
+	// credentials have not changed.
+	// Connections may call this before every request, so it must be cheap.
+	Credentials(ctx context.Context) (Authentication, error)
+	// Invalidate is called when the servers rejected the given authentication,
+	// which was returned by an earlier call to Credentials.
+	// Implementations should load fresh credentials on the next call to Credentials.
+	Invalidate(auth Authentication)
+}
+
+// ProvidedAuthentication creates an authentication implementation that obtains
+// its credentials from the given provider.
+// The returned authentication implements CredentialsProvider.
+func ProvidedAuthentication(provider CredentialsProvider) Authentication {
+	return &providedAuthentication{
+		provider: provider,
+	}
+}
+
+const (
+	// DefaultUserNameVariable is the default environment variable used by EnvCredentialsProvider to read the username.
+	DefaultUserNameVariable = "ARANGODB_USERNAME"
+	// DefaultPasswordVariable is the default environment variable used by EnvCredentialsProvider to read the password.
+	DefaultPasswordVariable = "ARANGODB_PASSWORD"
+)
+
+// FileCredentialsOptions contains options for NewFileCredentialsProvider.
+type FileCredentialsOptions struct {
+	// AuthenticationType is the type of authentication created from the credentials.
+	// Supported types are Basic (default), JWT, Raw & JWTSecret.
+	// For Raw authentication, the password is used as value of the Authorization header.
+	// Raw authentication is only supported by HTTP connections, VST connections fail
+	// with an InvalidArgumentError when the credentials are used.
+	// For JWTSecret authentication, the password is used as secret.
+	AuthenticationType AuthenticationType
+	// UserName is the username used when UserNameFile is empty.
+	UserName string
+	// UserNameFile is the path of a file containing the username.
+	UserNameFile string
+	// PasswordFile is the path of a file containing the password (required).
+	PasswordFile string
+}
+
+// NewFileCredentialsProvider creates a CredentialsProvider that reads the credentials from files.
+// A trailing newline in the files is ignored.
+// The files are reloaded when they have changed, or when the credentials have been rejected
+// by the servers, which allows for rotating the credentials without restarting.
+func NewFileCredentialsProvider(options FileCredentialsOptions) (CredentialsProvider, error) {
+	if options.PasswordFile == "" {
+		return nil, WithStack(InvalidArgumentError{Message: "PasswordFile must be set"})
+	}
+	if err := validateCredentialsType(options.AuthenticationType); err != nil {
+		return nil, WithStack(err)
+	}
+	return &fileCredentialsProvider{
+		options: options,
+	}, nil
+}
+
+// EnvCredentialsOptions contains options for NewEnvCredentialsProvider.
+type EnvCredentialsOptions struct {
+	// AuthenticationType is the type of authentication created from the credentials.
+	// See FileCredentialsOptions.AuthenticationType.
+	AuthenticationType AuthenticationType
+	// UserNameVariable is the name of the environment variable containing the username.
+	// Defaults to DefaultUserNameVariable.
+	UserNameVariable string
+	// PasswordVariable is the name of the environment variable containing the password.
+	// Defaults to DefaultPasswordVariable.
+	PasswordVariable string
+}
+
+// NewEnvCredentialsProvider creates a CredentialsProvider that reads the credentials from
+// environment variables.
+// The variables are read every time credentials are requested, so changes take effect immediately.
+func NewEnvCredentialsProvider(options EnvCredentialsOptions) (CredentialsProvider, error) {
+	if err := validateCredentialsType(options.AuthenticationType); err != nil {
+		return nil, WithStack(err)
+	}
+	if options.UserNameVariable == "" {
+		options.UserNameVariable = DefaultUserNameVariable
+	}
+	if options.PasswordVariable == "" {
+		options.PasswordVariable = DefaultPasswordVariable
+	}
+	return &envCredentialsProvider{
+		options: options,
+	}, nil
+}
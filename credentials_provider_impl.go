@@ -0,0 +1,196 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// providedAuthentication implements an authentication that obtains its credentials from a provider.
+type providedAuthentication struct {
+	provider CredentialsProvider
+}
+
+// Returns the type of authentication
+func (a *providedAuthentication) Type() AuthenticationType {
+	return AuthenticationTypeProvided
+}
+
+// Get returns a configuration property of the authentication.
+// Supported properties depend on type of authentication.
+func (a *providedAuthentication) Get(property string) string {
+	return ""
+}
+
+// Credentials returns the authentication to use for the next requests.
+func (a *providedAuthentication) Credentials(ctx context.Context) (Authentication, error) {
+	auth, err := a.provider.Credentials(ctx)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return auth, nil
+}
+
+// Invalidate is called when the servers rejected the given authentication.
+func (a *providedAuthentication) Invalidate(auth Authentication) {
+	a.provider.Invalidate(auth)
+}
+
+// validateCredentialsType returns an error if the given type of authentication
+// cannot be created from a username & password.
+func validateCredentialsType(authType AuthenticationType) error {
+	switch authType {
+	case AuthenticationTypeBasic, AuthenticationTypeJWT, AuthenticationTypeRaw, AuthenticationTypeJWTSecret:
+		return nil
+	default:
+		return WithStack(InvalidArgumentError{Message: fmt.Sprintf("Unsupported authentication type %d", int(authType))})
+	}
+}
+
+// newCredentialsAuthentication creates an authentication of the given type from the given username & password.
+func newCredentialsAuthentication(authType AuthenticationType, userName, password string) Authentication {
+	switch authType {
+	case AuthenticationTypeJWT:
+		return JWTAuthentication(userName, password)
+	case AuthenticationTypeRaw:
+		return RawAuthentication(password)
+	case AuthenticationTypeJWTSecret:
+		return JWTSecretAuthentication(password, &JWTSecretOptions{UserName: userName})
+	default:
+		return BasicAuthentication(userName, password)
+	}
+}
+
+// fileCredentialsProvider implements a CredentialsProvider that reads the credentials from files.
+type fileCredentialsProvider struct {
+	options  FileCredentialsOptions
+	mutex    sync.Mutex
+	auth     Authentication
+	versions map[string]fileVersion
+}
+
+// fileVersion identifies the content of a file.
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// Credentials returns the authentication to use for the next requests.
+func (p *fileCredentialsProvider) Credentials(ctx context.Context) (Authentication, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	files := []string{p.options.PasswordFile}
+	if p.options.UserNameFile != "" {
+		files = append(files, p.options.UserNameFile)
+	}
+	versions := make(map[string]fileVersion, len(files))
+	changed := p.auth == nil
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, WithStack(err)
+		}
+		v := fileVersion{modTime: info.ModTime(), size: info.Size()}
+		if v != p.versions[path] {
+			changed = true
+		}
+		versions[path] = v
+	}
+	if !changed {
+		return p.auth, nil
+	}
+
+	// (Re)load the credentials
+	userName := p.options.UserName
+	if p.options.UserNameFile != "" {
+		var err error
+		if userName, err = readCredentialsFile(p.options.UserNameFile); err != nil {
+			return nil, WithStack(err)
+		}
+	}
+	password, err := readCredentialsFile(p.options.PasswordFile)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	p.auth = newCredentialsAuthentication(p.options.AuthenticationType, userName, password)
+	p.versions = versions
+	return p.auth, nil
+}
+
+// Invalidate is called when the servers rejected the given authentication.
+func (p *fileCredentialsProvider) Invalidate(auth Authentication) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if auth == p.auth {
+		// Force a reload on the next call to Credentials
+		p.auth = nil
+	}
+}
+
+// readCredentialsFile reads the content of the given file, without a trailing newline.
+func readCredentialsFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", WithStack(err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// envCredentialsProvider implements a CredentialsProvider that reads the credentials from environment variables.
+type envCredentialsProvider struct {
+	options  EnvCredentialsOptions
+	mutex    sync.Mutex
+	auth     Authentication
+	userName string
+	password string
+}
+
+// Credentials returns the authentication to use for the next requests.
+func (p *envCredentialsProvider) Credentials(ctx context.Context) (Authentication, error) {
+	password, found := os.LookupEnv(p.options.PasswordVariable)
+	if !found {
+		return nil, WithStack(InvalidArgumentError{Message: fmt.Sprintf("Environment variable '%s' is not set", p.options.PasswordVariable)})
+	}
+	userName := os.Getenv(p.options.UserNameVariable)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.auth == nil || p.userName != userName || p.password != password {
+		p.auth = newCredentialsAuthentication(p.options.AuthenticationType, userName, password)
+		p.userName = userName
+		p.password = password
+	}
+	return p.auth, nil
+}
+
+// Invalidate is called when the servers rejected the given authentication.
+// The environment variables are read on every call to Credentials, so there is nothing to do.
+func (p *envCredentialsProvider) Invalidate(auth Authentication) {
+}
@@ -0,0 +1,130 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCredentialsProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	userNameFile := filepath.Join(dir, "username")
+	passwordFile := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(userNameFile, []byte("root\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+	if err := ioutil.WriteFile(passwordFile, []byte("first\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+
+	provider, err := NewFileCredentialsProvider(FileCredentialsOptions{
+		UserNameFile: userNameFile,
+		PasswordFile: passwordFile,
+	})
+	if err != nil {
+		t.Fatalf("NewFileCredentialsProvider failed: %s", err)
+	}
+	ctx := context.Background()
+	auth, err := provider.Credentials(ctx)
+	if err != nil {
+		t.Fatalf("Credentials failed: %s", err)
+	}
+	if auth.Type() != AuthenticationTypeBasic || auth.Get("username") != "root" || auth.Get("password") != "first" {
+		t.Errorf("Unexpected credentials %d %s:%s", auth.Type(), auth.Get("username"), auth.Get("password"))
+	}
+	if again, _ := provider.Credentials(ctx); again != auth {
+		t.Error("Expected same authentication for unchanged files")
+	}
+
+	// Rotate the password
+	if err := ioutil.WriteFile(passwordFile, []byte("second-password\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+	rotated, err := provider.Credentials(ctx)
+	if err != nil {
+		t.Fatalf("Credentials failed: %s", err)
+	}
+	if rotated == auth || rotated.Get("password") != "second-password" {
+		t.Errorf("Expected rotated password, got '%s'", rotated.Get("password"))
+	}
+
+	// Invalidation forces a reload
+	provider.Invalidate(rotated)
+	if reloaded, _ := provider.Credentials(ctx); reloaded == rotated {
+		t.Error("Expected new authentication after invalidation")
+	}
+}
+
+func TestFileCredentialsProviderInvalidOptions(t *testing.T) {
+	if _, err := NewFileCredentialsProvider(FileCredentialsOptions{}); !IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError for missing PasswordFile, got %v", err)
+	}
+	if _, err := NewFileCredentialsProvider(FileCredentialsOptions{PasswordFile: "x", AuthenticationType: AuthenticationTypeProvided}); !IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError for unsupported type, got %v", err)
+	}
+}
+
+func TestEnvCredentialsProvider(t *testing.T) {
+	const userVar, passwordVar = "GO_DRIVER_TEST_USERNAME", "GO_DRIVER_TEST_PASSWORD"
+	defer os.Unsetenv(userVar)
+	defer os.Unsetenv(passwordVar)
+	os.Unsetenv(passwordVar)
+
+	provider, err := NewEnvCredentialsProvider(EnvCredentialsOptions{
+		AuthenticationType: AuthenticationTypeJWT,
+		UserNameVariable:   userVar,
+		PasswordVariable:   passwordVar,
+	})
+	if err != nil {
+		t.Fatalf("NewEnvCredentialsProvider failed: %s", err)
+	}
+	ctx := context.Background()
+	if _, err := provider.Credentials(ctx); err == nil {
+		t.Error("Expected error for unset password variable")
+	}
+
+	os.Setenv(userVar, "root")
+	os.Setenv(passwordVar, "first")
+	auth, err := provider.Credentials(ctx)
+	if err != nil {
+		t.Fatalf("Credentials failed: %s", err)
+	}
+	if auth.Type() != AuthenticationTypeJWT || auth.Get("username") != "root" || auth.Get("password") != "first" {
+		t.Errorf("Unexpected credentials %d %s:%s", auth.Type(), auth.Get("username"), auth.Get("password"))
+	}
+	if again, _ := provider.Credentials(ctx); again != auth {
+		t.Error("Expected same authentication for unchanged variables")
+	}
+	os.Setenv(passwordVar, "second")
+	if changed, _ := provider.Credentials(ctx); changed == auth || changed.Get("password") != "second" {
+		t.Error("Expected new authentication for changed variables")
+	}
+}
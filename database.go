@@ -49,6 +49,18 @@ type Database interface {
 	// Graph functions
 	DatabaseGraphs
 
+	// View functions
+	DatabaseViews
+
+	// Analyzer functions
+	DatabaseAnalyzers
+
+	// Pregel functions
+	DatabasePregels
+
+	// Foxx functions
+	DatabaseFoxx
+
 	// Query performs an AQL query, returning a cursor used to iterate over the returned documents.
 	// Note that the returned Cursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
 	Query(ctx context.Context, query string, bindVars map[string]interface{}) (Cursor, error)
@@ -72,6 +84,15 @@ type DatabaseInfo struct {
 	Path string `json:"path,omitempty"`
 	// If true then the database is the _system database.
 	IsSystem bool `json:"isSystem,omitempty"`
+	// Default replication factor for collections in the database.
+	// Only set in a cluster.
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+	// Default write concern for collections in the database.
+	// Only set in a cluster.
+	WriteConcern int `json:"writeConcern,omitempty"`
+	// Sharding method used by the database.
+	// Only set in a cluster.
+	Sharding DatabaseSharding `json:"sharding,omitempty"`
 }
 
 // EngineType indicates type of database engine being used.
@@ -0,0 +1,43 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import "context"
+
+// DatabaseAnalyzers provides access to all analyzers in a single database.
+// Analyzers are only available in ArangoDB 3.5 and higher.
+type DatabaseAnalyzers interface {
+	// EnsureAnalyzer ensures that an analyzer with the given definition exists in the database.
+	// If an analyzer with the same name and definition already exists, existed is set to true
+	// and the existing analyzer is returned.
+	// If an analyzer with the same name but a different definition exists, a ConflictError is returned.
+	EnsureAnalyzer(ctx context.Context, definition AnalyzerDefinition) (existed bool, analyzer Analyzer, err error)
+
+	// Analyzer opens a connection to an existing analyzer within the database.
+	// If no analyzer with given name exists, an NotFoundError is returned.
+	Analyzer(ctx context.Context, name string) (Analyzer, error)
+
+	// Analyzers returns a list of all analyzers available in the database.
+	// This includes the built-in analyzers.
+	Analyzers(ctx context.Context) ([]Analyzer, error)
+}
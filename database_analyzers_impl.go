@@ -0,0 +1,123 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"path"
+)
+
+// EnsureAnalyzer ensures that an analyzer with the given definition exists in the database.
+// If an analyzer with the same name and definition already exists, existed is set to true
+// and the existing analyzer is returned.
+// If an analyzer with the same name but a different definition exists, a ConflictError is returned.
+func (d *database) EnsureAnalyzer(ctx context.Context, definition AnalyzerDefinition) (bool, Analyzer, error) {
+	if definition.Name == "" {
+		return false, nil, WithStack(InvalidArgumentError{Message: "definition.Name is empty"})
+	}
+	if definition.Type == "" {
+		return false, nil, WithStack(InvalidArgumentError{Message: "definition.Type is empty"})
+	}
+	req, err := d.conn.NewRequest("POST", path.Join(d.relPath(), "_api/analyzer"))
+	if err != nil {
+		return false, nil, WithStack(err)
+	}
+	if _, err := req.SetBody(definition); err != nil {
+		return false, nil, WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return false, nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200, 201); err != nil {
+		return false, nil, WithStack(err)
+	}
+	var data AnalyzerDefinition
+	if err := resp.ParseBody("", &data); err != nil {
+		return false, nil, WithStack(err)
+	}
+	analyzer, err := newAnalyzer(data, d)
+	if err != nil {
+		return false, nil, WithStack(err)
+	}
+	return resp.StatusCode() == 200, analyzer, nil
+}
+
+// Analyzer opens a connection to an existing analyzer within the database.
+// If no analyzer with given name exists, an NotFoundError is returned.
+func (d *database) Analyzer(ctx context.Context, name string) (Analyzer, error) {
+	escapedName := pathEscape(name)
+	req, err := d.conn.NewRequest("GET", path.Join(d.relPath(), "_api/analyzer", escapedName))
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, WithStack(err)
+	}
+	var data AnalyzerDefinition
+	if err := resp.ParseBody("", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	analyzer, err := newAnalyzer(data, d)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return analyzer, nil
+}
+
+type getAnalyzersResponse struct {
+	Result []AnalyzerDefinition `json:"result,omitempty"`
+}
+
+// Analyzers returns a list of all analyzers available in the database.
+// This includes the built-in analyzers.
+func (d *database) Analyzers(ctx context.Context) ([]Analyzer, error) {
+	req, err := d.conn.NewRequest("GET", path.Join(d.relPath(), "_api/analyzer"))
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, WithStack(err)
+	}
+	var data getAnalyzersResponse
+	if err := resp.ParseBody("", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	result := make([]Analyzer, 0, len(data.Result))
+	for _, def := range data.Result {
+		analyzer, err := newAnalyzer(def, d)
+		if err != nil {
+			return nil, WithStack(err)
+		}
+		result = append(result, analyzer)
+	}
+	return result, nil
+}
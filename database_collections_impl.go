@@ -42,7 +42,11 @@ func (d *database) Collection(ctx context.Context, name string) (Collection, err
 	if err := resp.CheckStatus(200); err != nil {
 		return nil, WithStack(err)
 	}
-	coll, err := newCollection(name, d)
+	var data CollectionInfo
+	if err := resp.ParseBody("", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	coll, err := newCollectionOfType(name, d, data.Type)
 	if err != nil {
 		return nil, WithStack(err)
 	}
@@ -92,7 +96,7 @@ func (d *database) Collections(ctx context.Context) ([]Collection, error) {
 	}
 	result := make([]Collection, 0, len(data.Result))
 	for _, info := range data.Result {
-		col, err := newCollection(info.Name, d)
+		col, err := newCollectionOfType(info.Name, d, info.Type)
 		if err != nil {
 			return nil, WithStack(err)
 		}
@@ -128,7 +132,14 @@ func (d *database) CreateCollection(ctx context.Context, name string, options *C
 	if err := resp.CheckStatus(200); err != nil {
 		return nil, WithStack(err)
 	}
-	col, err := newCollection(name, d)
+	var data CollectionInfo
+	if err := resp.ParseBody("", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	if data.Type == 0 && options != nil {
+		data.Type = options.Type
+	}
+	col, err := newCollectionOfType(name, d, data.Type)
 	if err != nil {
 		return nil, WithStack(err)
 	}
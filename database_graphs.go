@@ -39,6 +39,10 @@ type DatabaseGraphs interface {
 	// CreateGraph creates a new graph with given name and options, and opens a connection to it.
 	// If a graph with given name already exists within the database, a DuplicateError is returned.
 	CreateGraph(ctx context.Context, name string, options *CreateGraphOptions) (Graph, error)
+
+	// AnonymousGraph returns traversal functions that operate on the given edge collections,
+	// without the need for a named graph.
+	AnonymousGraph(edgeCollections ...string) (GraphTraversals, error)
 }
 
 // CreateGraphOptions contains options that customize the creating of a graph.
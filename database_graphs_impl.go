@@ -156,3 +156,16 @@ func (d *database) CreateGraph(ctx context.Context, name string, options *Create
 	}
 	return g, nil
 }
+
+// AnonymousGraph returns traversal functions that operate on the given edge collections,
+// without the need for a named graph.
+func (d *database) AnonymousGraph(edgeCollections ...string) (GraphTraversals, error) {
+	if len(edgeCollections) == 0 {
+		return nil, WithStack(InvalidArgumentError{Message: "edgeCollections is empty"})
+	}
+	t, err := newGraphTraversals(d, "", edgeCollections)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return t, nil
+}
@@ -0,0 +1,52 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"time"
+)
+
+// DatabasePregels provides access to Pregel jobs in a single database.
+type DatabasePregels interface {
+	// StartPregelJob starts a new Pregel job with given options and returns its ID.
+	StartPregelJob(ctx context.Context, options PregelJobOptions) (PregelJobID, error)
+
+	// PregelJob fetches the status of the Pregel job with given ID.
+	// If no job with given ID exists, a NotFoundError is returned.
+	PregelJob(ctx context.Context, id PregelJobID) (PregelJob, error)
+
+	// PregelJobs returns the status of all Pregel jobs in the database.
+	// Requires ArangoDB 3.7 or higher.
+	PregelJobs(ctx context.Context) ([]PregelJob, error)
+
+	// CancelPregelJob cancels the Pregel job with given ID.
+	// If no job with given ID exists, a NotFoundError is returned.
+	CancelPregelJob(ctx context.Context, id PregelJobID) error
+
+	// WaitForPregelJob polls the status of the Pregel job with given ID, until the job
+	// has reached a terminal state or the given context is done.
+	// The status is polled every interval. If interval is 0, a default of 1 second is used.
+	// The last fetched status is returned.
+	WaitForPregelJob(ctx context.Context, id PregelJobID, interval time.Duration) (PregelJob, error)
+}
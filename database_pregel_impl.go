@@ -0,0 +1,156 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"path"
+	"time"
+)
+
+const (
+	defaultPregelJobWaitInterval = time.Second
+)
+
+// StartPregelJob starts a new Pregel job with given options and returns its ID.
+func (d *database) StartPregelJob(ctx context.Context, options PregelJobOptions) (PregelJobID, error) {
+	if options.Algorithm == "" {
+		return "", WithStack(InvalidArgumentError{Message: "options.Algorithm is empty"})
+	}
+	if options.GraphName == "" && (len(options.VertexCollections) == 0 || len(options.EdgeCollections) == 0) {
+		return "", WithStack(InvalidArgumentError{Message: "options.GraphName or options.VertexCollections and options.EdgeCollections must be set"})
+	}
+	req, err := d.conn.NewRequest("POST", path.Join(d.relPath(), "_api/control_pregel"))
+	if err != nil {
+		return "", WithStack(err)
+	}
+	if _, err := req.SetBody(options); err != nil {
+		return "", WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return "", WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return "", WithStack(err)
+	}
+	var id PregelJobID
+	if err := resp.ParseBody("", &id); err != nil {
+		return "", WithStack(err)
+	}
+	return id, nil
+}
+
+// PregelJob fetches the status of the Pregel job with given ID.
+// If no job with given ID exists, a NotFoundError is returned.
+func (d *database) PregelJob(ctx context.Context, id PregelJobID) (PregelJob, error) {
+	if id == "" {
+		return PregelJob{}, WithStack(InvalidArgumentError{Message: "id is empty"})
+	}
+	req, err := d.conn.NewRequest("GET", path.Join(d.relPath(), "_api/control_pregel", pathEscape(id.String())))
+	if err != nil {
+		return PregelJob{}, WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return PregelJob{}, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return PregelJob{}, WithStack(err)
+	}
+	var data PregelJob
+	if err := resp.ParseBody("", &data); err != nil {
+		return PregelJob{}, WithStack(err)
+	}
+	if data.ID == "" {
+		data.ID = id
+	}
+	return data, nil
+}
+
+// PregelJobs returns the status of all Pregel jobs in the database.
+// Requires ArangoDB 3.7 or higher.
+func (d *database) PregelJobs(ctx context.Context) ([]PregelJob, error) {
+	req, err := d.conn.NewRequest("GET", path.Join(d.relPath(), "_api/control_pregel"))
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, WithStack(err)
+	}
+	var data []PregelJob
+	if err := resp.ParseBody("", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	return data, nil
+}
+
+// CancelPregelJob cancels the Pregel job with given ID.
+// If no job with given ID exists, a NotFoundError is returned.
+func (d *database) CancelPregelJob(ctx context.Context, id PregelJobID) error {
+	if id == "" {
+		return WithStack(InvalidArgumentError{Message: "id is empty"})
+	}
+	req, err := d.conn.NewRequest("DELETE", path.Join(d.relPath(), "_api/control_pregel", pathEscape(id.String())))
+	if err != nil {
+		return WithStack(err)
+	}
+	resp, err := d.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// WaitForPregelJob polls the status of the Pregel job with given ID, until the job
+// has reached a terminal state or the given context is done.
+// The status is polled every interval. If interval is 0, a default of 1 second is used.
+// The last fetched status is returned.
+func (d *database) WaitForPregelJob(ctx context.Context, id PregelJobID, interval time.Duration) (PregelJob, error) {
+	ctx = contextOrBackground(ctx)
+	if interval <= 0 {
+		interval = defaultPregelJobWaitInterval
+	}
+	for {
+		job, err := d.PregelJob(ctx, id)
+		if err != nil {
+			return job, WithStack(err)
+		}
+		if job.State.IsTerminal() {
+			return job, nil
+		}
+		select {
+		case <-time.After(interval):
+			// Try again
+		case <-ctx.Done():
+			return job, WithStack(ctx.Err())
+		}
+	}
+}
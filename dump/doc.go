@@ -0,0 +1,57 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+/*
+Package dump provides a logical dump and restore of an ArangoDB database,
+using the directory layout of the arangodump & arangorestore tools.
+
+A dump directory contains:
+
+	dump.json                    Name & properties of the dumped database.
+	<collection>.structure.json  Parameters & indexes of a collection.
+	<collection>.data.json       Documents of a collection, one JSON document per line.
+	<collection>.data.json.gz    Same as above, gzip compressed.
+	_graphs.structure.json       Present when named graphs are dumped.
+	_graphs.data.json            Definitions of the named graphs, one per line.
+
+To dump a database:
+
+	err := dump.Dump(ctx, db, "/tmp/mydump", &dump.DumpOptions{
+		Compress:    true,
+		Parallelism: 4,
+	})
+
+To restore it into another database:
+
+	err := dump.Restore(ctx, otherDB, "/tmp/mydump", &dump.RestoreOptions{
+		Overwrite: true,
+	})
+
+Collections are created with the dumped parameters, documents are imported with
+`isRestore` set (so revisions are kept) and indexes are created after the data
+has been imported. Named graphs are created last.
+
+Index attributes that are not exposed by the driver's Index interface
+(e.g. the minimum length of a fulltext index) are not included in a dump made
+by this package. They are honored when restoring a dump made by arangodump.
+*/
+package dump
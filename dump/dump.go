@@ -0,0 +1,233 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// DumpOptions contains options that customize a dump.
+type DumpOptions struct {
+	// Filter selects the collections to dump.
+	Filter Filter
+	// Parallelism is the number of collections that are dumped in parallel.
+	// Defaults to DefaultParallelism.
+	Parallelism int
+	// BatchSize is the number of documents fetched from the server in a single request.
+	// Defaults to DefaultBatchSize.
+	BatchSize int
+	// Compress, if set, writes gzip compressed data files.
+	Compress bool
+	// SkipGraphs, if set, does not dump the definitions of named graphs.
+	SkipGraphs bool
+	// Progress, if set, is called to report the progress of the dump.
+	Progress ProgressFunc
+}
+
+// Dump writes all selected collections (including their indexes & documents)
+// and the definitions of all named graphs of the given database to the given directory.
+// The directory is created if needed. Existing files are overwritten.
+func Dump(ctx context.Context, db driver.Database, dir string, options *DumpOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if dir == "" {
+		return driver.WithStack(driver.InvalidArgumentError{Message: "dir is empty"})
+	}
+	if options == nil {
+		options = &DumpOptions{}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return driver.WithStack(err)
+	}
+	info, err := db.Info(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if err := writeJSONFile(filepath.Join(dir, dumpFileName), dumpFile{Database: db.Name(), Properties: info}); err != nil {
+		return driver.WithStack(err)
+	}
+	cols, err := db.Collections(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	var names []string
+	for _, col := range cols {
+		if name := col.Name(); name != graphsCollectionName && options.Filter.Match(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	progress := &progressReporter{fn: options.Progress}
+	if err := forEachParallel(ctx, names, options.Parallelism, func(ctx context.Context, name string) error {
+		return dumpCollection(ctx, db, name, dir, options, progress)
+	}); err != nil {
+		return driver.WithStack(err)
+	}
+	if !options.SkipGraphs {
+		if err := dumpGraphs(ctx, db, dir, options, progress); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// dumpCollection writes the structure & data files of the collection with given name.
+func dumpCollection(ctx context.Context, db driver.Database, name, dir string, options *DumpOptions, progress *progressReporter) error {
+	col, err := db.Collection(ctx, name)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	props, err := col.Properties(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	indexes, err := col.Indexes(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	structure := structureFile{
+		Parameters: newCollectionParameters(props),
+		Indexes:    []indexDefinition{},
+	}
+	for _, idx := range indexes {
+		if isAutomaticIndex(idx.Type()) {
+			continue
+		}
+		structure.Indexes = append(structure.Indexes, newIndexDefinition(idx))
+	}
+	if err := writeJSONFile(structureFilePath(dir, name), structure); err != nil {
+		return driver.WithStack(err)
+	}
+
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if err := removeStaleDataFile(dir, name, options.Compress); err != nil {
+		return driver.WithStack(err)
+	}
+	w, err := createDataWriter(dataFilePath(dir, name, options.Compress), options.Compress)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	count, err := dumpDocuments(driver.WithQueryBatchSize(ctx, batchSize), db, name, batchSize, w, progress)
+	if err != nil {
+		w.Close()
+		return driver.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return driver.WithStack(err)
+	}
+	progress.report(Progress{Collection: name, Documents: count, Done: true})
+	return nil
+}
+
+// dumpDocuments writes all documents of the collection with given name to the given writer.
+// It returns the number of documents written.
+func dumpDocuments(ctx context.Context, db driver.Database, name string, batchSize int, w *dataWriter, progress *progressReporter) (int64, error) {
+	cursor, err := db.Query(ctx, "FOR d IN @@collection RETURN UNSET(d, '_id')", map[string]interface{}{"@collection": name})
+	if err != nil {
+		return 0, driver.WithStack(err)
+	}
+	defer cursor.Close()
+	var count int64
+	for cursor.HasMore() {
+		// Documents are copied as they are, so numbers are not converted to float64.
+		var doc json.RawMessage
+		if _, err := cursor.ReadDocument(ctx, &doc); err != nil {
+			return count, driver.WithStack(err)
+		}
+		if err := w.Write(doc); err != nil {
+			return count, driver.WithStack(err)
+		}
+		count++
+		if count%int64(batchSize) == 0 {
+			progress.report(Progress{Collection: name, Documents: count})
+		}
+	}
+	return count, nil
+}
+
+// dumpGraphs writes the definitions of all named graphs of the database
+// as documents of the _graphs collection.
+func dumpGraphs(ctx context.Context, db driver.Database, dir string, options *DumpOptions, progress *progressReporter) error {
+	graphs, err := db.Graphs(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	infos := make([]driver.GraphInfo, 0, len(graphs))
+	for _, g := range graphs {
+		info, err := g.Info(ctx)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		info.Name = g.Name()
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	structure := structureFile{
+		Parameters: collectionParameters{
+			Name:     graphsCollectionName,
+			Type:     driver.CollectionTypeDocument,
+			IsSystem: true,
+		},
+		Indexes: []indexDefinition{},
+	}
+	if err := writeJSONFile(structureFilePath(dir, graphsCollectionName), structure); err != nil {
+		return driver.WithStack(err)
+	}
+	if err := removeStaleDataFile(dir, graphsCollectionName, options.Compress); err != nil {
+		return driver.WithStack(err)
+	}
+	w, err := createDataWriter(dataFilePath(dir, graphsCollectionName, options.Compress), options.Compress)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	for _, info := range infos {
+		if err := w.Write(info); err != nil {
+			w.Close()
+			return driver.WithStack(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return driver.WithStack(err)
+	}
+	progress.report(Progress{Collection: graphsCollectionName, Documents: int64(len(infos)), Done: true})
+	return nil
+}
+
+// removeStaleDataFile removes a data file of the collection with given name that
+// was written by an earlier dump using the opposite compression setting.
+func removeStaleDataFile(dir, name string, compress bool) error {
+	if err := os.Remove(dataFilePath(dir, name, !compress)); err != nil && !os.IsNotExist(err) {
+		return driver.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		Filter   Filter
+		Name     string
+		Expected bool
+	}{
+		{Filter{}, "users", true},
+		{Filter{}, "_users", false},
+		{Filter{IncludeSystemCollections: true}, "_users", true},
+		{Filter{Include: []string{"users"}}, "users", true},
+		{Filter{Include: []string{"users"}}, "orders", false},
+		{Filter{Exclude: []string{"users"}}, "users", false},
+		{Filter{Exclude: []string{"users"}}, "orders", true},
+		{Filter{Include: []string{"users"}, Exclude: []string{"users"}}, "users", false},
+	}
+	for _, test := range tests {
+		if result := test.Filter.Match(test.Name); result != test.Expected {
+			t.Errorf("Expected %v for %+v.Match('%s'), got %v", test.Expected, test.Filter, test.Name, result)
+		}
+	}
+}
+
+func TestStructureFile(t *testing.T) {
+	// Taken from a dump made by arangodump
+	input := `{
+		"indexes": [{"id": "1234", "type": "fulltext", "fields": ["text"], "minLength": 3}],
+		"parameters": {"name": "docs", "type": 2, "waitForSync": true, "replicationFactor": "satellite",
+			"keyOptions": {"type": "autoincrement", "allowUserKeys": true}, "numberOfShards": 3, "shardKeys": ["_key"]}
+	}`
+	var s structureFile
+	if err := json.Unmarshal([]byte(input), &s); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	expected := &driver.CreateCollectionOptions{
+		Type:           driver.CollectionTypeDocument,
+		WaitForSync:    true,
+		KeyOptions:     &driver.CollectionKeyOptions{Type: driver.KeyGeneratorAutoIncrement, AllowUserKeys: true},
+		NumberOfShards: 3,
+		ShardKeys:      []string{"_key"},
+	}
+	if options := s.Parameters.createOptions(); !reflect.DeepEqual(expected, options) {
+		t.Errorf("Expected %+v, got %+v", expected, options)
+	}
+	if len(s.Indexes) != 1 || s.Indexes[0].Type != driver.FullTextIndex || s.Indexes[0].MinLength != 3 {
+		t.Errorf("Unexpected indexes %+v", s.Indexes)
+	}
+}
+
+func TestDataFileRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dump-test")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	docs := []map[string]interface{}{
+		{"_key": "1", "name": "one"},
+		{"_key": "2", "name": "two", "nested": map[string]interface{}{"a": true}},
+	}
+	for _, compress := range []bool{false, true} {
+		name := "plain"
+		if compress {
+			name = "compressed"
+		}
+		if err := writeJSONFile(structureFilePath(dir, name), structureFile{}); err != nil {
+			t.Fatalf("writeJSONFile failed: %s", err)
+		}
+		w, err := createDataWriter(dataFilePath(dir, name, compress), compress)
+		if err != nil {
+			t.Fatalf("createDataWriter failed: %s", err)
+		}
+		for _, doc := range docs {
+			if err := w.Write(doc); err != nil {
+				t.Fatalf("Write failed: %s", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %s", err)
+		}
+
+		r, err := openDataReader(dir, name)
+		if err != nil || r == nil {
+			t.Fatalf("openDataReader failed: %v", err)
+		}
+		var result []map[string]interface{}
+		for {
+			var doc map[string]interface{}
+			if err := r.Read(&doc); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Read failed: %s", err)
+			}
+			result = append(result, doc)
+		}
+		r.Close()
+		if !reflect.DeepEqual(docs, result) {
+			t.Errorf("Expected %+v, got %+v (compress=%v)", docs, result, compress)
+		}
+	}
+
+	if r, err := openDataReader(dir, "missing"); err != nil || r != nil {
+		t.Errorf("Expected no reader for missing data file, got %v, %v", r, err)
+	}
+	names, err := listCollections(dir)
+	if err != nil {
+		t.Fatalf("listCollections failed: %s", err)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual([]string{"compressed", "plain"}, names) {
+		t.Errorf("Unexpected collections %v", names)
+	}
+}
+
+func TestForEachParallel(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	results := make(chan string, len(names))
+	if err := forEachParallel(context.Background(), names, 3, func(ctx context.Context, name string) error {
+		results <- name
+		return nil
+	}); err != nil {
+		t.Fatalf("forEachParallel failed: %s", err)
+	}
+	close(results)
+	var processed []string
+	for name := range results {
+		processed = append(processed, name)
+	}
+	sort.Strings(processed)
+	if !reflect.DeepEqual(names, processed) {
+		t.Errorf("Expected %v, got %v", names, processed)
+	}
+
+	failure := errors.New("failure")
+	if err := forEachParallel(context.Background(), names, 2, func(ctx context.Context, name string) error {
+		if name == "b" {
+			return failure
+		}
+		return nil
+	}); driver.Cause(err) != failure {
+		t.Errorf("Expected failure, got %v", err)
+	}
+}
+
+// TestNilContext checks that Dump & Restore accept a nil context.
+func TestNilContext(t *testing.T) {
+	// A server with an empty database
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/_api/database/current") {
+			w.Write([]byte(`{"result":{"name":"empty","id":"1","path":"","isSystem":false}}`))
+		} else {
+			w.Write([]byte(`{"result":[]}`))
+		}
+	}))
+	defer srv.Close()
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{Endpoints: []string{srv.URL}})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	c, err := driver.NewClient(driver.ClientConfig{Connection: conn})
+	if err != nil {
+		t.Fatalf("NewClient failed: %s", err)
+	}
+	db, err := c.Database(nil, "empty")
+	if err != nil {
+		t.Fatalf("Database failed: %s", err)
+	}
+	dir, err := ioutil.TempDir("", "dump-nil-ctx")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := Dump(nil, db, dir, &DumpOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Dump failed: %s", err)
+	}
+	if err := Restore(nil, db, dir, &RestoreOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Restore failed: %s", err)
+	}
+	if err := forEachParallel(nil, []string{"a"}, 1, func(ctx context.Context, name string) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("forEachParallel failed: %s", err)
+	}
+}
+
+// fakeServer is a fake server holding a single database named `fake` with a single
+// edge collection named `routes`, with given indexes & documents (JSON arrays).
+// It records the indexes created & documents imported by a restore.
+type fakeServer struct {
+	*httptest.Server
+	indexes   string
+	documents string
+
+	mutex          sync.Mutex
+	createdIndexes []map[string]interface{}
+	imported       [][]byte
+}
+
+func newFakeServer(indexes, documents string) *fakeServer {
+	s := &fakeServer{indexes: indexes, documents: documents}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	body, _ := ioutil.ReadAll(r.Body)
+	switch strings.TrimPrefix(r.URL.Path, "/_db/fake") {
+	case "/_api/database/current":
+		w.Write([]byte(`{"result":{"name":"fake","id":"1","path":"","isSystem":false}}`))
+	case "/_api/collection":
+		if r.Method == "POST" {
+			w.Write([]byte(`{"id":"2","name":"routes","type":3}`))
+		} else {
+			w.Write([]byte(`{"result":[{"id":"2","name":"routes","type":3}]}`))
+		}
+	case "/_api/collection/routes", "/_api/collection/routes/properties":
+		w.Write([]byte(`{"id":"2","name":"routes","type":3,"keyOptions":{"type":"traditional","allowUserKeys":true}}`))
+	case "/_api/index":
+		if r.Method == "POST" {
+			var created, idx map[string]interface{}
+			json.Unmarshal(body, &created)
+			json.Unmarshal(body, &idx)
+			s.createdIndexes = append(s.createdIndexes, created)
+			idx["id"] = "routes/" + strconv.Itoa(len(s.createdIndexes)+10)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(idx)
+		} else {
+			w.Write([]byte(`{"indexes":` + s.indexes + `}`))
+		}
+	case "/_api/import":
+		s.imported = append(s.imported, body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"created":1}`))
+	case "/_api/cursor":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":` + s.documents + `,"hasMore":false}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":true,"code":404,"errorNum":1203,"errorMessage":"not found"}`))
+	}
+}
+
+// database returns the `fake` database of the server.
+func (s *fakeServer) database(t *testing.T) driver.Database {
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{Endpoints: []string{s.URL}})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	c, err := driver.NewClient(driver.ClientConfig{Connection: conn})
+	if err != nil {
+		t.Fatalf("NewClient failed: %s", err)
+	}
+	db, err := c.Database(nil, "fake")
+	if err != nil {
+		t.Fatalf("Database failed: %s", err)
+	}
+	return db
+}
+
+// TestDumpEdgeCollection checks that the automatic indexes of an edge collection are not dumped.
+func TestDumpEdgeCollection(t *testing.T) {
+	srv := newFakeServer(`[
+		{"id":"routes/0","type":"primary","fields":["_key"],"unique":true,"sparse":false},
+		{"id":"routes/1","type":"edge","fields":["_from","_to"],"unique":false,"sparse":false},
+		{"id":"routes/2","type":"persistent","fields":["distance"],"unique":false,"sparse":true}
+	]`, `[{"_key":"r1","_from":"cities/a","_to":"cities/b","distance":10}]`)
+	defer srv.Close()
+	db := srv.database(t)
+	dir, err := ioutil.TempDir("", "dump-edges")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := Dump(nil, db, dir, &DumpOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Dump failed: %s", err)
+	}
+	var structure structureFile
+	if err := readJSONFile(structureFilePath(dir, "routes"), &structure); err != nil {
+		t.Fatalf("readJSONFile failed: %s", err)
+	}
+	if structure.Parameters.Type != driver.CollectionTypeEdge {
+		t.Errorf("Expected edge collection, got type %d", structure.Parameters.Type)
+	}
+	expected := []indexDefinition{{ID: "2", Type: driver.PersistentIndex, Fields: []string{"distance"}, Sparse: true}}
+	if !reflect.DeepEqual(expected, structure.Indexes) {
+		t.Errorf("Expected indexes %+v, got %+v", expected, structure.Indexes)
+	}
+}
+
+// TestIndexOptionsRoundTrip checks that the options of indexes survive a dump & restore.
+func TestIndexOptionsRoundTrip(t *testing.T) {
+	srv := newFakeServer(`[
+		{"id":"routes/0","type":"primary","fields":["_key"],"unique":true,"sparse":false},
+		{"id":"routes/1","type":"hash","fields":["tags"],"unique":false,"sparse":false,"deduplicate":false},
+		{"id":"routes/2","type":"fulltext","fields":["text"],"unique":false,"sparse":true,"minLength":3},
+		{"id":"routes/3","type":"geo","fields":["location"],"unique":false,"sparse":true,"geoJson":true}
+	]`, `[]`)
+	defer srv.Close()
+	db := srv.database(t)
+	dir, err := ioutil.TempDir("", "dump-index-options")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := Dump(nil, db, dir, &DumpOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Dump failed: %s", err)
+	}
+	if err := Restore(nil, db, dir, &RestoreOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Restore failed: %s", err)
+	}
+	expected := []map[string]interface{}{
+		{"type": "hash", "fields": []interface{}{"tags"}, "unique": false, "sparse": false, "deduplicate": false},
+		{"type": "fulltext", "fields": []interface{}{"text"}, "minLength": float64(3)},
+		{"type": "geo", "fields": []interface{}{"location"}, "geoJson": true},
+	}
+	if !reflect.DeepEqual(expected, srv.createdIndexes) {
+		t.Errorf("Expected created indexes %v, got %v", expected, srv.createdIndexes)
+	}
+}
+
+// TestLargeIntegerRoundTrip checks that integers that do not fit in a float64 survive a dump & restore.
+func TestLargeIntegerRoundTrip(t *testing.T) {
+	srv := newFakeServer(`[]`, `[{"_key":"r1","_from":"cities/a","_to":"cities/b","big":9007199254740993}]`)
+	defer srv.Close()
+	db := srv.database(t)
+	dir, err := ioutil.TempDir("", "dump-large-integer")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := Dump(nil, db, dir, &DumpOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Dump failed: %s", err)
+	}
+	data, err := ioutil.ReadFile(dataFilePath(dir, "routes", false))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %s", err)
+	}
+	if !strings.Contains(string(data), `"big":9007199254740993`) {
+		t.Errorf("Expected large integer in data file, got %s", data)
+	}
+	if err := Restore(nil, db, dir, &RestoreOptions{SkipGraphs: true}); err != nil {
+		t.Fatalf("Restore failed: %s", err)
+	}
+	if len(srv.imported) != 1 || !strings.Contains(string(srv.imported[0]), `"big":9007199254740993`) {
+		t.Errorf("Expected large integer in imported documents, got %q", srv.imported)
+	}
+}
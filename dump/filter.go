@@ -0,0 +1,74 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package dump
+
+// Filter selects the collections that are dumped or restored.
+type Filter struct {
+	// Include holds the names of the collections to include.
+	// If empty, all collections are included.
+	Include []string
+	// Exclude holds the names of the collections to exclude.
+	Exclude []string
+	// IncludeSystemCollections, if set, includes system collections (collections whose name starts with '_').
+	IncludeSystemCollections bool
+}
+
+// Match returns true if the collection with given name is selected by the filter.
+func (f Filter) Match(name string) bool {
+	if isSystemCollection(name) && !f.IncludeSystemCollections {
+		return false
+	}
+	if len(f.Include) > 0 && !contains(f.Include, name) {
+		return false
+	}
+	return !contains(f.Exclude, name)
+}
+
+// Progress describes the progress of dumping or restoring a single collection.
+type Progress struct {
+	// Collection is the name of the collection.
+	Collection string
+	// Documents is the number of documents of the collection that have been processed so far.
+	Documents int64
+	// Done is set when the collection has been processed completely.
+	Done bool
+}
+
+// ProgressFunc is called to report progress of a dump or restore.
+// Calls are serialized, even when multiple collections are processed in parallel.
+type ProgressFunc func(Progress)
+
+// isSystemCollection returns true if the given name is the name of a system collection.
+func isSystemCollection(name string) bool {
+	return len(name) > 0 && name[0] == '_'
+}
+
+// contains returns true if the given list contains the given name.
+func contains(list []string, name string) bool {
+	for _, x := range list {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,397 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package dump
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultParallelism is the default number of collections that are processed in parallel.
+	DefaultParallelism = 2
+	// DefaultBatchSize is the default number of documents that are read or imported in a single request.
+	DefaultBatchSize = 1000
+
+	dumpFileName           = "dump.json"
+	structureFileSuffix    = ".structure.json"
+	dataFileSuffix         = ".data.json"
+	compressedFileSuffix   = ".gz"
+	graphsCollectionName   = "_graphs"
+	maxDataLineBufferBytes = 64 * 1024 * 1024
+)
+
+// dumpFile is the content of the dump.json file.
+type dumpFile struct {
+	Database   string              `json:"database"`
+	Properties driver.DatabaseInfo `json:"properties"`
+}
+
+// structureFile is the content of a <collection>.structure.json file.
+type structureFile struct {
+	Parameters collectionParameters `json:"parameters"`
+	Indexes    []indexDefinition    `json:"indexes"`
+}
+
+// collectionParameters holds the parameters of a dumped collection.
+type collectionParameters struct {
+	Name              string                       `json:"name"`
+	Type              driver.CollectionType        `json:"type"`
+	IsSystem          bool                         `json:"isSystem,omitempty"`
+	WaitForSync       bool                         `json:"waitForSync,omitempty"`
+	KeyOptions        *driver.CollectionKeyOptions `json:"keyOptions,omitempty"`
+	NumberOfShards    int                          `json:"numberOfShards,omitempty"`
+	ShardKeys         []string                     `json:"shardKeys,omitempty"`
+	ReplicationFactor replicationFactor            `json:"replicationFactor,omitempty"`
+}
+
+// replicationFactor is the replication factor of a collection.
+// Dumps made by arangodump can contain "satellite" instead of a number,
+// which is decoded as 0 (server default).
+type replicationFactor int
+
+// UnmarshalJSON decodes a number, ignoring non-numeric values.
+func (r *replicationFactor) UnmarshalJSON(data []byte) error {
+	var v int
+	if err := json.Unmarshal(data, &v); err != nil {
+		*r = 0
+		return nil
+	}
+	*r = replicationFactor(v)
+	return nil
+}
+
+// indexDefinition holds the definition of a dumped index.
+type indexDefinition struct {
+	ID          string           `json:"id,omitempty"`
+	Type        driver.IndexType `json:"type"`
+	Fields      []string         `json:"fields"`
+	Unique      bool             `json:"unique,omitempty"`
+	Sparse      bool             `json:"sparse,omitempty"`
+	Deduplicate *bool            `json:"deduplicate,omitempty"`
+	MinLength   int              `json:"minLength,omitempty"`
+	GeoJSON     bool             `json:"geoJson,omitempty"`
+}
+
+// newIndexDefinition creates the definition of a dump from the given index.
+func newIndexDefinition(idx driver.Index) indexDefinition {
+	d := indexDefinition{
+		ID:     idx.Name(),
+		Type:   idx.Type(),
+		Fields: idx.Fields(),
+		Unique: idx.Unique(),
+		Sparse: idx.Sparse(),
+	}
+	switch d.Type {
+	case driver.HashIndex, driver.SkipListIndex:
+		deduplicate := idx.Deduplicate()
+		d.Deduplicate = &deduplicate
+	case driver.FullTextIndex:
+		d.MinLength = idx.MinLength()
+	case driver.GeoIndex:
+		d.GeoJSON = idx.GeoJSON()
+	}
+	return d
+}
+
+// newCollectionParameters creates the parameters of a dump from the given collection properties.
+func newCollectionParameters(props driver.CollectionProperties) collectionParameters {
+	p := collectionParameters{
+		Name:              props.Name,
+		Type:              props.Type,
+		IsSystem:          props.IsSystem,
+		WaitForSync:       props.WaitForSync,
+		NumberOfShards:    props.NumberOfShards,
+		ShardKeys:         props.ShardKeys,
+		ReplicationFactor: replicationFactor(props.ReplicationFactor),
+	}
+	if props.KeyOptions.Type != "" {
+		p.KeyOptions = &driver.CollectionKeyOptions{
+			Type:          props.KeyOptions.Type,
+			AllowUserKeys: props.KeyOptions.AllowUserKeys,
+		}
+	}
+	return p
+}
+
+// createOptions returns the options needed to create a collection with the parameters.
+func (p collectionParameters) createOptions() *driver.CreateCollectionOptions {
+	return &driver.CreateCollectionOptions{
+		Type:              p.Type,
+		IsSystem:          p.IsSystem,
+		WaitForSync:       p.WaitForSync,
+		KeyOptions:        p.KeyOptions,
+		NumberOfShards:    p.NumberOfShards,
+		ShardKeys:         p.ShardKeys,
+		ReplicationFactor: int(p.ReplicationFactor),
+	}
+}
+
+// isAutomaticIndex returns true if the index of given type is created automatically
+// with the collection and must not be dumped or restored.
+func isAutomaticIndex(t driver.IndexType) bool {
+	return t == driver.PrimaryIndex || t == driver.EdgeIndex
+}
+
+// ensure creates the index in the given collection.
+func (d indexDefinition) ensure(ctx context.Context, col driver.Collection) error {
+	var err error
+	noDeduplicate := d.Deduplicate != nil && !*d.Deduplicate
+	switch d.Type {
+	case driver.HashIndex:
+		_, _, err = col.EnsureHashIndex(ctx, d.Fields, &driver.EnsureHashIndexOptions{Unique: d.Unique, Sparse: d.Sparse, NoDeduplicate: noDeduplicate})
+	case driver.SkipListIndex:
+		_, _, err = col.EnsureSkipListIndex(ctx, d.Fields, &driver.EnsureSkipListIndexOptions{Unique: d.Unique, Sparse: d.Sparse, NoDeduplicate: noDeduplicate})
+	case driver.PersistentIndex:
+		_, _, err = col.EnsurePersistentIndex(ctx, d.Fields, &driver.EnsurePersistentIndexOptions{Unique: d.Unique, Sparse: d.Sparse})
+	case driver.FullTextIndex:
+		_, _, err = col.EnsureFullTextIndex(ctx, d.Fields, &driver.EnsureFullTextIndexOptions{MinLength: d.MinLength})
+	case driver.GeoIndex, "geo1", "geo2":
+		_, _, err = col.EnsureGeoIndex(ctx, d.Fields, &driver.EnsureGeoIndexOptions{GeoJSON: d.GeoJSON})
+	default:
+		return driver.WithStack(driver.InvalidArgumentError{Message: "unsupported index type '" + string(d.Type) + "' in collection '" + col.Name() + "'"})
+	}
+	return driver.WithStack(err)
+}
+
+// structureFilePath returns the path of the structure file of the collection with given name.
+func structureFilePath(dir, name string) string {
+	return filepath.Join(dir, name+structureFileSuffix)
+}
+
+// dataFilePath returns the path of the data file of the collection with given name.
+func dataFilePath(dir, name string, compressed bool) string {
+	p := filepath.Join(dir, name+dataFileSuffix)
+	if compressed {
+		p += compressedFileSuffix
+	}
+	return p
+}
+
+// listCollections returns the names of all collections that have a structure file in the given directory.
+func listCollections(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+structureFileSuffix))
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), structureFileSuffix))
+	}
+	return names, nil
+}
+
+// writeJSONFile writes the given value as JSON to a file with given path.
+func writeJSONFile(filePath string, value interface{}) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if err := ioutil.WriteFile(filePath, encoded, 0644); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// readJSONFile reads a JSON encoded value from a file with given path.
+func readJSONFile(filePath string, value interface{}) error {
+	encoded, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if err := json.Unmarshal(encoded, value); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// dataWriter writes documents to a data file, one document per line.
+type dataWriter struct {
+	f   *os.File
+	buf *bufio.Writer
+	gz  *gzip.Writer
+	enc *json.Encoder
+}
+
+// createDataWriter creates a data file with given path.
+func createDataWriter(filePath string, compress bool) (*dataWriter, error) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	w := &dataWriter{f: f, buf: bufio.NewWriter(f)}
+	var out io.Writer = w.buf
+	if compress {
+		w.gz = gzip.NewWriter(w.buf)
+		out = w.gz
+	}
+	w.enc = json.NewEncoder(out)
+	return w, nil
+}
+
+// Write writes a single document.
+func (w *dataWriter) Write(doc interface{}) error {
+	return driver.WithStack(w.enc.Encode(doc))
+}
+
+// Close flushes all buffers and closes the file.
+func (w *dataWriter) Close() error {
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			w.f.Close()
+			return driver.WithStack(err)
+		}
+	}
+	if err := w.buf.Flush(); err != nil {
+		w.f.Close()
+		return driver.WithStack(err)
+	}
+	return driver.WithStack(w.f.Close())
+}
+
+// dataReader reads documents from a data file, one document per line.
+type dataReader struct {
+	f       *os.File
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+}
+
+// openDataReader opens the data file of the collection with given name in given directory.
+// If there is no (compressed or uncompressed) data file, nil is returned.
+func openDataReader(dir, name string) (*dataReader, error) {
+	for _, compressed := range []bool{false, true} {
+		f, err := os.Open(dataFilePath(dir, name, compressed))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, driver.WithStack(err)
+		}
+		r := &dataReader{f: f}
+		var in io.Reader = f
+		if compressed {
+			r.gz, err = gzip.NewReader(f)
+			if err != nil {
+				f.Close()
+				return nil, driver.WithStack(err)
+			}
+			in = r.gz
+		}
+		r.scanner = bufio.NewScanner(in)
+		r.scanner.Buffer(make([]byte, 64*1024), maxDataLineBufferBytes)
+		return r, nil
+	}
+	return nil, nil
+}
+
+// Read reads the next document into the given value.
+// Empty lines are skipped. At the end of the file, io.EOF is returned.
+func (r *dataReader) Read(value interface{}) error {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		return driver.WithStack(json.Unmarshal(line, value))
+	}
+	if err := r.scanner.Err(); err != nil {
+		return driver.WithStack(err)
+	}
+	return io.EOF
+}
+
+// Close closes the data file.
+func (r *dataReader) Close() error {
+	if r.gz != nil {
+		r.gz.Close()
+	}
+	return driver.WithStack(r.f.Close())
+}
+
+// progressReporter serializes calls to a ProgressFunc.
+type progressReporter struct {
+	mutex sync.Mutex
+	fn    ProgressFunc
+}
+
+// report calls the progress function (if any) with the given progress.
+func (r *progressReporter) report(p Progress) {
+	if r.fn == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.fn(p)
+}
+
+// forEachParallel calls fn for all given names, running at most parallelism calls at the same time.
+// The first error cancels the context passed to all other calls and is returned.
+func forEachParallel(ctx context.Context, names []string, parallelism int, fn func(ctx context.Context, name string) error) error {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	work := make(chan string)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range work {
+				if err := fn(ctx, name); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	for _, name := range names {
+		select {
+		case work <- name:
+		case <-ctx.Done():
+		}
+	}
+	close(work)
+	wg.Wait()
+	if firstErr != nil {
+		return driver.WithStack(firstErr)
+	}
+	return driver.WithStack(ctx.Err())
+}
@@ -0,0 +1,249 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// RestoreOptions contains options that customize a restore.
+type RestoreOptions struct {
+	// Filter selects the collections to restore.
+	Filter Filter
+	// Parallelism is the number of collections that are restored in parallel.
+	// Defaults to DefaultParallelism.
+	Parallelism int
+	// BatchSize is the number of documents imported in a single request.
+	// Defaults to DefaultBatchSize.
+	BatchSize int
+	// Overwrite, if set, removes existing collections & graphs before restoring them.
+	// If not set, restoring a collection that already exists results in a DuplicateError
+	// and graphs that already exist are left as they are.
+	Overwrite bool
+	// SkipGraphs, if set, does not create the named graphs found in the dump.
+	SkipGraphs bool
+	// Progress, if set, is called to report the progress of the restore.
+	Progress ProgressFunc
+}
+
+// Restore creates all selected collections found in the given dump directory in the given database,
+// imports their documents, creates their indexes and finally creates the named graphs.
+// When Overwrite is set, the named graphs found in the dump are removed from the database first.
+func Restore(ctx context.Context, db driver.Database, dir string, options *RestoreOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if dir == "" {
+		return driver.WithStack(driver.InvalidArgumentError{Message: "dir is empty"})
+	}
+	if options == nil {
+		options = &RestoreOptions{}
+	}
+	if _, err := os.Stat(filepath.Join(dir, dumpFileName)); err != nil {
+		return driver.WithStack(err)
+	}
+	all, err := listCollections(dir)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	var names []string
+	for _, name := range all {
+		if name != graphsCollectionName && options.Filter.Match(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var graphs []driver.GraphInfo
+	if !options.SkipGraphs {
+		if graphs, err = readGraphs(dir); err != nil {
+			return driver.WithStack(err)
+		}
+		if options.Overwrite {
+			// Remove graphs first, so their collections can be replaced.
+			if err := removeGraphs(ctx, db, graphs); err != nil {
+				return driver.WithStack(err)
+			}
+		}
+	}
+	progress := &progressReporter{fn: options.Progress}
+	if err := forEachParallel(ctx, names, options.Parallelism, func(ctx context.Context, name string) error {
+		return restoreCollection(ctx, db, name, dir, options, progress)
+	}); err != nil {
+		return driver.WithStack(err)
+	}
+	if !options.SkipGraphs {
+		if err := createGraphs(ctx, db, graphs, progress); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// restoreCollection creates the collection with given name, imports its documents & creates its indexes.
+func restoreCollection(ctx context.Context, db driver.Database, name, dir string, options *RestoreOptions, progress *progressReporter) error {
+	var structure structureFile
+	if err := readJSONFile(structureFilePath(dir, name), &structure); err != nil {
+		return driver.WithStack(err)
+	}
+	if options.Overwrite {
+		if col, err := db.Collection(ctx, name); err == nil {
+			if err := col.Remove(ctx); err != nil {
+				return driver.WithStack(err)
+			}
+		} else if !driver.IsNotFound(err) {
+			return driver.WithStack(err)
+		}
+	}
+	col, err := db.CreateCollection(ctx, name, structure.Parameters.createOptions())
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	count, err := restoreDocuments(ctx, col, dir, options, progress)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	for _, idx := range structure.Indexes {
+		if isAutomaticIndex(idx.Type) {
+			continue
+		}
+		if err := idx.ensure(ctx, col); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	progress.report(Progress{Collection: name, Documents: count, Done: true})
+	return nil
+}
+
+// restoreDocuments imports all documents of the data file of the given collection.
+// It returns the number of documents imported.
+func restoreDocuments(ctx context.Context, col driver.Collection, dir string, options *RestoreOptions, progress *progressReporter) (int64, error) {
+	r, err := openDataReader(dir, col.Name())
+	if err != nil {
+		return 0, driver.WithStack(err)
+	} else if r == nil {
+		// No data
+		return 0, nil
+	}
+	defer r.Close()
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	ctx = driver.WithIsRestore(ctx, true)
+	var count int64
+	// Documents are copied as they are, so numbers are not converted to float64.
+	batch := make([]json.RawMessage, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := col.ImportDocuments(ctx, batch, &driver.ImportDocumentOptions{Complete: true}); err != nil {
+			return driver.WithStack(err)
+		}
+		count += int64(len(batch))
+		batch = batch[:0]
+		progress.report(Progress{Collection: col.Name(), Documents: count})
+		return nil
+	}
+	for {
+		var doc json.RawMessage
+		if err := r.Read(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return count, driver.WithStack(err)
+		}
+		batch = append(batch, doc)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return count, driver.WithStack(err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return count, driver.WithStack(err)
+	}
+	return count, nil
+}
+
+// readGraphs reads the graph definitions found in the _graphs data file (if any).
+func readGraphs(dir string) ([]driver.GraphInfo, error) {
+	r, err := openDataReader(dir, graphsCollectionName)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	} else if r == nil {
+		// No graphs
+		return nil, nil
+	}
+	defer r.Close()
+	var result []driver.GraphInfo
+	for {
+		var info driver.GraphInfo
+		if err := r.Read(&info); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return nil, driver.WithStack(err)
+		}
+		result = append(result, info)
+	}
+}
+
+// removeGraphs removes the given graphs from the database (if they exist).
+// The collections of the graphs are not removed.
+func removeGraphs(ctx context.Context, db driver.Database, graphs []driver.GraphInfo) error {
+	for _, info := range graphs {
+		if g, err := db.Graph(ctx, info.Name); err == nil {
+			if err := g.Remove(ctx); err != nil {
+				return driver.WithStack(err)
+			}
+		} else if !driver.IsNotFound(err) {
+			return driver.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// createGraphs creates the given graphs, skipping graphs that already exist.
+func createGraphs(ctx context.Context, db driver.Database, graphs []driver.GraphInfo, progress *progressReporter) error {
+	for _, info := range graphs {
+		if _, err := db.CreateGraph(ctx, info.Name, &driver.CreateGraphOptions{
+			EdgeDefinitions:         info.EdgeDefinitions,
+			OrphanVertexCollections: info.OrphanCollections,
+			IsSmart:                 info.IsSmart,
+			SmartGraphAttribute:     info.SmartGraphAttribute,
+			NumberOfShards:          info.NumberOfShards,
+		}); driver.IsConflict(err) {
+			// Graph already exists
+		} else if err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	progress.report(Progress{Collection: graphsCollectionName, Documents: int64(len(graphs)), Done: true})
+	return nil
+}
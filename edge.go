@@ -29,3 +29,34 @@ type EdgeDocument struct {
 	From DocumentID `json:"_from,omitempty"`
 	To   DocumentID `json:"_to,omitempty"`
 }
+
+// EdgeDirection specifies which edges of a vertex are selected.
+type EdgeDirection string
+
+const (
+	// EdgeDirectionIn selects edges that have the vertex as their `_to` vertex.
+	EdgeDirectionIn = EdgeDirection("in")
+	// EdgeDirectionOut selects edges that have the vertex as their `_from` vertex.
+	EdgeDirectionOut = EdgeDirection("out")
+	// EdgeDirectionAny selects edges that have the vertex as either their `_from` or `_to` vertex.
+	EdgeDirectionAny = EdgeDirection("any")
+)
+
+// EdgeObject contains the meta data and the connected vertices of an edge.
+type EdgeObject struct {
+	DocumentMeta
+	EdgeDocument
+}
+
+// OtherVertex returns the vertex on the other side of the edge, seen from the given vertex.
+// If the given vertex is not connected to the edge, an empty ID is returned.
+func (e EdgeObject) OtherVertex(vertex DocumentID) DocumentID {
+	switch vertex {
+	case e.From:
+		return e.To
+	case e.To:
+		return e.From
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,81 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import "context"
+
+// Edges returns the edges in this collection that are connected to the given vertex, in the given direction.
+// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+// in the same order as the returned edge objects.
+// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *edgeCollection) Edges(ctx context.Context, vertex DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	col, err := c.rawEdgeCollection()
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	result, err := col.Edges(ctx, vertex, direction, results)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return result, nil
+}
+
+// EdgesOfVertices returns the edges in this collection that are connected to any of the given vertices, in the given direction.
+// If results is not nil, it must be a pointer to a slice. The full edge documents are stored into it,
+// in the same order as the returned edge objects.
+// To only return edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *edgeCollection) EdgesOfVertices(ctx context.Context, vertices []DocumentID, direction EdgeDirection, results interface{}) ([]EdgeObject, error) {
+	col, err := c.rawEdgeCollection()
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	result, err := col.EdgesOfVertices(ctx, vertices, direction, results)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return result, nil
+}
+
+// CountEdges returns the number of edges in this collection that are connected to the given vertex, in the given direction.
+// To only count edges whose other vertex is in specific collections, prepare a context with `WithEdgesVertexCollections`.
+func (c *edgeCollection) CountEdges(ctx context.Context, vertex DocumentID, direction EdgeDirection) (int64, error) {
+	col, err := c.rawEdgeCollection()
+	if err != nil {
+		return 0, WithStack(err)
+	}
+	result, err := col.CountEdges(ctx, vertex, direction)
+	if err != nil {
+		return 0, WithStack(err)
+	}
+	return result, nil
+}
+
+// rawEdgeCollection returns a standard edge implementation of Collection
+// for this edge collection.
+func (c *edgeCollection) rawEdgeCollection() (EdgeCollection, error) {
+	result, err := newCollectionOfType(c.name, c.g.db, CollectionTypeEdge)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return result.(EdgeCollection), nil
+}
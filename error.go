@@ -69,19 +69,19 @@ func newArangoError(code, errorNum int, errorMessage string) error {
 
 // IsArangoError returns true when the given error is an ArangoError.
 func IsArangoError(err error) bool {
-	ae, ok := Cause(err).(ArangoError)
+	ae, ok := unwrapRetryError(Cause(err)).(ArangoError)
 	return ok && ae.HasError
 }
 
 // IsArangoErrorWithCode returns true when the given error is an ArangoError and its Code field is equal to the given code.
 func IsArangoErrorWithCode(err error, code int) bool {
-	ae, ok := Cause(err).(ArangoError)
+	ae, ok := unwrapRetryError(Cause(err)).(ArangoError)
 	return ok && ae.Code == code
 }
 
 // IsArangoErrorWithErrorNum returns true when the given error is an ArangoError and its ErrorNum field is equal to one of the given numbers.
 func IsArangoErrorWithErrorNum(err error, errorNum ...int) bool {
-	ae, ok := Cause(err).(ArangoError)
+	ae, ok := unwrapRetryError(Cause(err)).(ArangoError)
 	if !ok {
 		return false
 	}
@@ -110,12 +110,12 @@ func IsForbidden(err error) bool {
 
 // IsNotFound returns true if the given error is an ArangoError with code 404, indicating a object not found.
 func IsNotFound(err error) bool {
-	return IsArangoErrorWithCode(err, 404) || IsArangoErrorWithErrorNum(err, 1202, 1203)
+	return IsArangoErrorWithCode(err, 404) || IsArangoErrorWithErrorNum(err, 1202, 1203, 3009)
 }
 
 // IsConflict returns true if the given error is an ArangoError with code 409, indicating a conflict.
 func IsConflict(err error) bool {
-	return IsArangoErrorWithCode(err, 409) || IsArangoErrorWithErrorNum(err, 1702)
+	return IsArangoErrorWithCode(err, 409) || IsArangoErrorWithErrorNum(err, 1702, 3011)
 }
 
 // IsPreconditionFailed returns true if the given error is an ArangoError with code 412, indicating a failed precondition.
@@ -199,6 +199,8 @@ func isCausedBy(err error, p func(error) bool) bool {
 		}
 		if xerr, ok := err.(*ResponseError); ok {
 			err = xerr.Err
+		} else if xerr, ok := err.(*RetryError); ok {
+			err = Cause(xerr.Err)
 		} else if xerr, ok := err.(*url.Error); ok {
 			err = xerr.Err
 		} else if xerr, ok := err.(*net.OpError); ok {
@@ -211,6 +213,15 @@ func isCausedBy(err error, p func(error) bool) bool {
 	}
 }
 
+// unwrapRetryError returns the cause of the error of the last attempt if the given error is a RetryError.
+// Otherwise the given error is returned.
+func unwrapRetryError(err error) error {
+	if xerr, ok := err.(*RetryError); ok {
+		return Cause(xerr.Err)
+	}
+	return err
+}
+
 var (
 	// WithStack is called on every return of an error to add stacktrace information to the error.
 	// When setting this function, also set the Cause function.
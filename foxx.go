@@ -0,0 +1,224 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"io"
+)
+
+// Foxx provides access to the Foxx services installed in a database.
+// All services are identified by their mount path (e.g. "/my-service").
+type Foxx interface {
+	// Install installs a new service from the given zip bundle at the given mount path.
+	// If a service is already installed at that mount path, a ConflictError is returned.
+	Install(ctx context.Context, mount string, zip io.Reader, options *FoxxInstallOptions) (FoxxService, error)
+
+	// Replace removes the service at the given mount path and installs the given zip bundle in its place.
+	// This replaces the configuration & dependencies of the service.
+	Replace(ctx context.Context, mount string, zip io.Reader, options *FoxxInstallOptions) (FoxxService, error)
+
+	// Upgrade installs the given zip bundle in place of the service at the given mount path.
+	// Unlike Replace, the existing configuration & dependencies of the service are kept.
+	Upgrade(ctx context.Context, mount string, zip io.Reader, options *FoxxInstallOptions) (FoxxService, error)
+
+	// Uninstall removes the service at the given mount path.
+	// If no such service exists, a NotFoundError is returned.
+	Uninstall(ctx context.Context, mount string, options *FoxxUninstallOptions) error
+
+	// Services returns a summary of all services installed in the database.
+	// If excludeSystem is set, system services are not included.
+	Services(ctx context.Context, excludeSystem bool) ([]FoxxService, error)
+
+	// Service returns detailed information about the service at the given mount path.
+	// If no such service exists, a NotFoundError is returned.
+	Service(ctx context.Context, mount string) (FoxxService, error)
+
+	// Configuration returns the configuration options of the service at the given mount path,
+	// including their current values.
+	Configuration(ctx context.Context, mount string) (map[string]FoxxConfigurationOption, error)
+
+	// SetConfiguration sets the values of configuration options of the service at the given mount path.
+	// If replace is set, options that are not in the given values are reset to their default value,
+	// otherwise they keep their current value.
+	SetConfiguration(ctx context.Context, mount string, values map[string]interface{}, replace bool) error
+
+	// Dependencies returns the dependencies of the service at the given mount path,
+	// including their current values.
+	Dependencies(ctx context.Context, mount string) (map[string]FoxxDependency, error)
+
+	// SetDependencies sets the mount paths of dependencies of the service at the given mount path.
+	// The value of a dependency is a mount path (string), or a list of mount paths ([]string)
+	// for dependencies that allow multiple services.
+	// If replace is set, dependencies that are not in the given values are unset,
+	// otherwise they keep their current value.
+	SetDependencies(ctx context.Context, mount string, values map[string]interface{}, replace bool) error
+
+	// EnableDevelopment puts the service at the given mount path into development mode.
+	// In development mode, the service is reloaded from the filesystem of the server on every request.
+	EnableDevelopment(ctx context.Context, mount string) (FoxxService, error)
+
+	// DisableDevelopment puts the service at the given mount path into production mode.
+	DisableDevelopment(ctx context.Context, mount string) (FoxxService, error)
+
+	// Scripts returns the names (keys) & titles (values) of the scripts of the service at the given mount path.
+	Scripts(ctx context.Context, mount string) (map[string]string, error)
+
+	// RunScript runs the script with given name of the service at the given mount path.
+	// The given args (if not nil) are passed to the script.
+	// The value exported by the script is stored in the given result (if not nil).
+	RunScript(ctx context.Context, mount, name string, args interface{}, result interface{}) error
+
+	// RunTests runs the tests of the service at the given mount path.
+	// The test report is stored in the given result (if not nil).
+	// With the default reporter, the report can be stored in a FoxxTestReport.
+	RunTests(ctx context.Context, mount string, options *FoxxTestOptions, result interface{}) error
+}
+
+// FoxxInstallOptions contains options that customize the installation, replacement or upgrade of a Foxx service.
+type FoxxInstallOptions struct {
+	// Development, if set, installs the service in development mode.
+	Development bool
+	// Setup determines if the setup script of the service is executed (default is true).
+	Setup *bool
+	// Teardown determines if the teardown script of the existing service is executed
+	// when replacing or upgrading a service (default is true).
+	// Not used by Install.
+	Teardown *bool
+	// Legacy, if set, installs the service in 2.8 legacy compatibility mode.
+	Legacy bool
+	// Force, if set, installs the service when replacing or upgrading a service that does not exist.
+	// Not used by Install.
+	Force bool
+	// Configuration holds the values of configuration options of the service.
+	// If set, the service bundle is sent as multipart/form-data.
+	Configuration map[string]interface{}
+	// Dependencies holds the mount paths of dependencies of the service.
+	// If set, the service bundle is sent as multipart/form-data.
+	Dependencies map[string]interface{}
+}
+
+// FoxxUninstallOptions contains options that customize the removal of a Foxx service.
+type FoxxUninstallOptions struct {
+	// Teardown determines if the teardown script of the service is executed (default is true).
+	Teardown *bool
+}
+
+// FoxxTestOptions contains options that customize running the tests of a Foxx service.
+type FoxxTestOptions struct {
+	// Reporter is the name of the test reporter to use ("default", "suite", "stream", "xunit" or "tap").
+	Reporter string
+	// Idiomatic, if set, makes the stream, xunit & tap reporters use their native format instead of JSON.
+	Idiomatic bool
+	// Filter, if set, only runs tests whose full name (including the full test suite name) contains this string.
+	Filter string
+}
+
+// FoxxService contains information about a Foxx service.
+type FoxxService struct {
+	// Mount path of the service.
+	Mount string `json:"mount"`
+	// Path of the service on the filesystem of the server.
+	// Only available from Foxx.Service.
+	Path string `json:"path,omitempty"`
+	// Name of the service.
+	Name string `json:"name,omitempty"`
+	// Version of the service.
+	Version string `json:"version,omitempty"`
+	// Development is true when the service is running in development mode.
+	Development bool `json:"development,omitempty"`
+	// Legacy is true when the service is running in 2.8 legacy compatibility mode.
+	Legacy bool `json:"legacy,omitempty"`
+	// Provides holds the services (names & versions) provided by the service.
+	// Only available from Foxx.Services.
+	Provides map[string]interface{} `json:"provides,omitempty"`
+	// Manifest of the service.
+	// Only available from Foxx.Service.
+	Manifest map[string]interface{} `json:"manifest,omitempty"`
+	// Checksum of the service bundle.
+	// Only available from Foxx.Service.
+	Checksum string `json:"checksum,omitempty"`
+	// Options of the service.
+	// Only available from Foxx.Service.
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// FoxxConfigurationOption contains the definition & current value of a configuration option of a Foxx service.
+type FoxxConfigurationOption struct {
+	// Title of the option.
+	Title string `json:"title,omitempty"`
+	// Description of the option.
+	Description string `json:"description,omitempty"`
+	// Type of the option (e.g. "string", "int", "boolean", "json").
+	Type string `json:"type,omitempty"`
+	// Default value of the option.
+	Default interface{} `json:"default,omitempty"`
+	// Required is true when the option must have a value.
+	Required bool `json:"required,omitempty"`
+	// Current value of the option.
+	Current interface{} `json:"current,omitempty"`
+}
+
+// FoxxDependency contains the definition & current value of a dependency of a Foxx service.
+type FoxxDependency struct {
+	// Title of the dependency.
+	Title string `json:"title,omitempty"`
+	// Description of the dependency.
+	Description string `json:"description,omitempty"`
+	// Name of the service that is required.
+	Name string `json:"name,omitempty"`
+	// Version (range) of the service that is required.
+	Version string `json:"version,omitempty"`
+	// Required is true when the dependency must be set.
+	Required bool `json:"required,omitempty"`
+	// Multiple is true when the dependency accepts a list of services.
+	Multiple bool `json:"multiple,omitempty"`
+	// Current mount path (string) or list of mount paths ([]interface{}) of the dependency.
+	Current interface{} `json:"current,omitempty"`
+}
+
+// FoxxTestReport is the result of running the tests of a Foxx service with the default reporter.
+type FoxxTestReport struct {
+	Stats struct {
+		Suites   int `json:"suites"`
+		Tests    int `json:"tests"`
+		Passes   int `json:"passes"`
+		Pending  int `json:"pending"`
+		Failures int `json:"failures"`
+		// Duration of the tests in milliseconds.
+		Duration float64 `json:"duration"`
+	} `json:"stats"`
+	Tests    []FoxxTestResult `json:"tests"`
+	Pending  []FoxxTestResult `json:"pending"`
+	Failures []FoxxTestResult `json:"failures"`
+	Passes   []FoxxTestResult `json:"passes"`
+}
+
+// FoxxTestResult is the result of a single test in a FoxxTestReport.
+type FoxxTestResult struct {
+	Title     string `json:"title"`
+	FullTitle string `json:"fullTitle"`
+	// Duration of the test in milliseconds.
+	Duration float64     `json:"duration"`
+	Err      interface{} `json:"err,omitempty"`
+}
@@ -0,0 +1,421 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+
+	velocypack "github.com/arangodb/go-velocypack"
+)
+
+// foxx implements Foxx.
+type foxx struct {
+	db *database
+}
+
+// relPath creates the relative path to the Foxx API (`_db/<db-name>/_api/foxx`)
+func (f *foxx) relPath() string {
+	return path.Join(f.db.relPath(), "_api", "foxx")
+}
+
+// Install installs a new service from the given zip bundle at the given mount path.
+// If a service is already installed at that mount path, a ConflictError is returned.
+func (f *foxx) Install(ctx context.Context, mount string, zip io.Reader, options *FoxxInstallOptions) (FoxxService, error) {
+	return f.install(ctx, "POST", f.relPath(), mount, zip, options, 201)
+}
+
+// Replace removes the service at the given mount path and installs the given zip bundle in its place.
+// This replaces the configuration & dependencies of the service.
+func (f *foxx) Replace(ctx context.Context, mount string, zip io.Reader, options *FoxxInstallOptions) (FoxxService, error) {
+	return f.install(ctx, "PUT", path.Join(f.relPath(), "service"), mount, zip, options, 200)
+}
+
+// Upgrade installs the given zip bundle in place of the service at the given mount path.
+// Unlike Replace, the existing configuration & dependencies of the service are kept.
+func (f *foxx) Upgrade(ctx context.Context, mount string, zip io.Reader, options *FoxxInstallOptions) (FoxxService, error) {
+	return f.install(ctx, "PATCH", path.Join(f.relPath(), "service"), mount, zip, options, 200)
+}
+
+// install sends the given zip bundle to the server, using the given method & path.
+func (f *foxx) install(ctx context.Context, method, relPath, mount string, zip io.Reader, options *FoxxInstallOptions, expectedStatus int) (FoxxService, error) {
+	if mount == "" {
+		return FoxxService{}, WithStack(InvalidArgumentError{Message: "mount is empty"})
+	}
+	if zip == nil {
+		return FoxxService{}, WithStack(InvalidArgumentError{Message: "zip is nil"})
+	}
+	if options == nil {
+		options = &FoxxInstallOptions{}
+	}
+	bundle, err := ioutil.ReadAll(zip)
+	if err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	req, err := f.db.conn.NewRequest(method, relPath)
+	if err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	req.SetQuery("mount", mount)
+	if options.Development {
+		req.SetQuery("development", "true")
+	}
+	if options.Setup != nil {
+		req.SetQuery("setup", strconv.FormatBool(*options.Setup))
+	}
+	if options.Legacy {
+		req.SetQuery("legacy", "true")
+	}
+	if method != "POST" {
+		if options.Teardown != nil {
+			req.SetQuery("teardown", strconv.FormatBool(*options.Teardown))
+		}
+		if options.Force {
+			req.SetQuery("force", "true")
+		}
+	}
+	rawReq, ok := req.(RawBodyRequest)
+	if !ok {
+		return FoxxService{}, WithStack(InvalidArgumentError{Message: "connection does not support requests with a raw body"})
+	}
+	if options.Configuration != nil || options.Dependencies != nil {
+		body, contentType, err := createFoxxMultipartBody(bundle, options)
+		if err != nil {
+			return FoxxService{}, WithStack(err)
+		}
+		if _, err := rawReq.SetRawBody(body, contentType); err != nil {
+			return FoxxService{}, WithStack(err)
+		}
+	} else if _, err := rawReq.SetRawBody(bundle, "application/zip"); err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	applyContextSettings(ctx, req)
+	resp, err := f.db.conn.Do(ctx, req)
+	if err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	if err := resp.CheckStatus(expectedStatus); err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	var data FoxxService
+	if err := resp.ParseBody("", &data); err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	return data, nil
+}
+
+// createFoxxMultipartBody creates a multipart/form-data body containing the given zip bundle,
+// configuration & dependencies.
+// It returns the body and its content type.
+func createFoxxMultipartBody(bundle []byte, options *FoxxInstallOptions) ([]byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	hdr := make(textproto.MIMEHeader)
+	hdr.Set("Content-Disposition", `form-data; name="source"; filename="service.zip"`)
+	hdr.Set("Content-Type", "application/zip")
+	part, err := w.CreatePart(hdr)
+	if err != nil {
+		return nil, "", WithStack(err)
+	}
+	if _, err := part.Write(bundle); err != nil {
+		return nil, "", WithStack(err)
+	}
+	fields := []struct {
+		Name  string
+		Value map[string]interface{}
+	}{
+		{"configuration", options.Configuration},
+		{"dependencies", options.Dependencies},
+	}
+	for _, field := range fields {
+		if field.Value == nil {
+			continue
+		}
+		encoded, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, "", WithStack(err)
+		}
+		if err := w.WriteField(field.Name, string(encoded)); err != nil {
+			return nil, "", WithStack(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", WithStack(err)
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// Uninstall removes the service at the given mount path.
+// If no such service exists, a NotFoundError is returned.
+func (f *foxx) Uninstall(ctx context.Context, mount string, options *FoxxUninstallOptions) error {
+	req, err := f.db.conn.NewRequest("DELETE", path.Join(f.relPath(), "service"))
+	if err != nil {
+		return WithStack(err)
+	}
+	req.SetQuery("mount", mount)
+	if options != nil && options.Teardown != nil {
+		req.SetQuery("teardown", strconv.FormatBool(*options.Teardown))
+	}
+	applyContextSettings(ctx, req)
+	resp, err := f.db.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(204); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// Services returns a summary of all services installed in the database.
+// If excludeSystem is set, system services are not included.
+func (f *foxx) Services(ctx context.Context, excludeSystem bool) ([]FoxxService, error) {
+	req, err := f.db.conn.NewRequest("GET", f.relPath())
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	req.SetQuery("excludeSystem", strconv.FormatBool(excludeSystem))
+	applyContextSettings(ctx, req)
+	resp, err := f.db.conn.Do(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, WithStack(err)
+	}
+	elems, err := resp.ParseArrayBody()
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	result := make([]FoxxService, 0, len(elems))
+	for _, elem := range elems {
+		var data FoxxService
+		if err := elem.ParseBody("", &data); err != nil {
+			return nil, WithStack(err)
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+// Service returns detailed information about the service at the given mount path.
+// If no such service exists, a NotFoundError is returned.
+func (f *foxx) Service(ctx context.Context, mount string) (FoxxService, error) {
+	var data FoxxService
+	if err := f.do(ctx, "GET", "service", mount, nil, &data); err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	return data, nil
+}
+
+// Configuration returns the configuration options of the service at the given mount path,
+// including their current values.
+func (f *foxx) Configuration(ctx context.Context, mount string) (map[string]FoxxConfigurationOption, error) {
+	var data map[string]FoxxConfigurationOption
+	if err := f.do(ctx, "GET", "configuration", mount, nil, &data); err != nil {
+		return nil, WithStack(err)
+	}
+	return data, nil
+}
+
+// SetConfiguration sets the values of configuration options of the service at the given mount path.
+// If replace is set, options that are not in the given values are reset to their default value,
+// otherwise they keep their current value.
+func (f *foxx) SetConfiguration(ctx context.Context, mount string, values map[string]interface{}, replace bool) error {
+	if err := f.do(ctx, replaceMethod(replace), "configuration", mount, values, nil); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// Dependencies returns the dependencies of the service at the given mount path,
+// including their current values.
+func (f *foxx) Dependencies(ctx context.Context, mount string) (map[string]FoxxDependency, error) {
+	var data map[string]FoxxDependency
+	if err := f.do(ctx, "GET", "dependencies", mount, nil, &data); err != nil {
+		return nil, WithStack(err)
+	}
+	return data, nil
+}
+
+// SetDependencies sets the mount paths of dependencies of the service at the given mount path.
+// The value of a dependency is a mount path (string), or a list of mount paths ([]string)
+// for dependencies that allow multiple services.
+// If replace is set, dependencies that are not in the given values are unset,
+// otherwise they keep their current value.
+func (f *foxx) SetDependencies(ctx context.Context, mount string, values map[string]interface{}, replace bool) error {
+	if err := f.do(ctx, replaceMethod(replace), "dependencies", mount, values, nil); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// EnableDevelopment puts the service at the given mount path into development mode.
+// In development mode, the service is reloaded from the filesystem of the server on every request.
+func (f *foxx) EnableDevelopment(ctx context.Context, mount string) (FoxxService, error) {
+	var data FoxxService
+	if err := f.do(ctx, "POST", "development", mount, nil, &data); err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	return data, nil
+}
+
+// DisableDevelopment puts the service at the given mount path into production mode.
+func (f *foxx) DisableDevelopment(ctx context.Context, mount string) (FoxxService, error) {
+	var data FoxxService
+	if err := f.do(ctx, "DELETE", "development", mount, nil, &data); err != nil {
+		return FoxxService{}, WithStack(err)
+	}
+	return data, nil
+}
+
+// Scripts returns the names (keys) & titles (values) of the scripts of the service at the given mount path.
+func (f *foxx) Scripts(ctx context.Context, mount string) (map[string]string, error) {
+	var data map[string]string
+	if err := f.do(ctx, "GET", "scripts", mount, nil, &data); err != nil {
+		return nil, WithStack(err)
+	}
+	return data, nil
+}
+
+// RunScript runs the script with given name of the service at the given mount path.
+// The given args (if not nil) are passed to the script.
+// The value exported by the script is stored in the given result (if not nil).
+func (f *foxx) RunScript(ctx context.Context, mount, name string, args interface{}, result interface{}) error {
+	if name == "" {
+		return WithStack(InvalidArgumentError{Message: "name is empty"})
+	}
+	req, err := f.db.conn.NewRequest("POST", path.Join(f.relPath(), "scripts", name))
+	if err != nil {
+		return WithStack(err)
+	}
+	req.SetQuery("mount", mount)
+	if args != nil {
+		if _, err := req.SetBody(args); err != nil {
+			return WithStack(err)
+		}
+	}
+	if err := f.doRaw(ctx, req, result); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// RunTests runs the tests of the service at the given mount path.
+// The test report is stored in the given result (if not nil).
+// With the default reporter, the report can be stored in a FoxxTestReport.
+func (f *foxx) RunTests(ctx context.Context, mount string, options *FoxxTestOptions, result interface{}) error {
+	req, err := f.db.conn.NewRequest("POST", path.Join(f.relPath(), "tests"))
+	if err != nil {
+		return WithStack(err)
+	}
+	req.SetQuery("mount", mount)
+	if options != nil {
+		if options.Reporter != "" {
+			req.SetQuery("reporter", options.Reporter)
+		}
+		if options.Idiomatic {
+			req.SetQuery("idiomatic", "true")
+		}
+		if options.Filter != "" {
+			req.SetQuery("filter", options.Filter)
+		}
+	}
+	if err := f.doRaw(ctx, req, result); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// do performs a request with given method on the given sub-path of the Foxx API for the service at the given mount path.
+// The given body (if not nil) is sent and the response object is parsed into the given result (if not nil).
+func (f *foxx) do(ctx context.Context, method, subPath, mount string, body interface{}, result interface{}) error {
+	req, err := f.db.conn.NewRequest(method, path.Join(f.relPath(), subPath))
+	if err != nil {
+		return WithStack(err)
+	}
+	req.SetQuery("mount", mount)
+	if body != nil {
+		if _, err := req.SetBody(body); err != nil {
+			return WithStack(err)
+		}
+	}
+	applyContextSettings(ctx, req)
+	resp, err := f.db.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return WithStack(err)
+	}
+	if result != nil {
+		if err := resp.ParseBody("", result); err != nil {
+			return WithStack(err)
+		}
+	}
+	return nil
+}
+
+// doRaw performs the given request and decodes the response into the given result (if not nil).
+// Unlike Response.ParseBody, the response does not have to be an object.
+func (f *foxx) doRaw(ctx context.Context, req Request, result interface{}) error {
+	var raw []byte
+	ctx = WithRawResponse(ctx, &raw)
+	applyContextSettings(ctx, req)
+	resp, err := f.db.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return WithStack(err)
+	}
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+	if strings.Contains(resp.Header("Content-Type"), "json") {
+		if err := json.Unmarshal(raw, result); err != nil {
+			return WithStack(err)
+		}
+		return nil
+	}
+	if err := velocypack.Unmarshal(velocypack.Slice(raw), result); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// replaceMethod returns the HTTP method used to replace (PUT) or update (PATCH) a resource.
+func replaceMethod(replace bool) string {
+	if replace {
+		return "PUT"
+	}
+	return "PATCH"
+}
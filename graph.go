@@ -29,13 +29,50 @@ type Graph interface {
 	// Name returns the name of the graph.
 	Name() string
 
+	// Info fetches the definition and properties of the graph.
+	Info(ctx context.Context) (GraphInfo, error)
+
 	// Remove removes the entire graph.
+	// The collections of the graph are not removed.
 	// If the graph does not exist, a NotFoundError is returned.
 	Remove(ctx context.Context) error
 
+	// RemoveWithOptions removes the entire graph, using the given options.
+	// If the graph does not exist, a NotFoundError is returned.
+	RemoveWithOptions(ctx context.Context, options *RemoveGraphOptions) error
+
 	// Edge collection functions
 	GraphEdgeCollections
 
 	// Vertex collection functions
 	GraphVertexCollections
+
+	// Traversal functions
+	GraphTraversals
+}
+
+// GraphInfo contains the definition and properties of a graph.
+type GraphInfo struct {
+	// Name of the graph.
+	Name string `json:"_key,omitempty"`
+	// EdgeDefinitions contains all edge definitions of the graph.
+	EdgeDefinitions []EdgeDefinition `json:"edgeDefinitions,omitempty"`
+	// OrphanCollections contains the names of vertex collections that are not used in any edge definition.
+	OrphanCollections []string `json:"orphanCollections,omitempty"`
+	// IsSmart is true if the graph is a smart graph.
+	// This only has effect in Enterprise Edition.
+	IsSmart bool `json:"isSmart,omitempty"`
+	// SmartGraphAttribute is the attribute name that is used to smartly shard the vertices of the graph.
+	SmartGraphAttribute string `json:"smartGraphAttribute,omitempty"`
+	// NumberOfShards is the number of shards that is used for every collection within the graph.
+	NumberOfShards int `json:"numberOfShards,omitempty"`
+	// ReplicationFactor is the replication factor used for every collection within the graph.
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+}
+
+// RemoveGraphOptions contains options that customize the removal of a graph.
+type RemoveGraphOptions struct {
+	// DropCollections, if set, removes all collections of the graph from the database,
+	// unless they are used in another graph.
+	DropCollections bool
 }
@@ -46,6 +46,15 @@ type GraphEdgeCollections interface {
 
 	// SetVertexConstraints modifies the vertex constraints of an existing edge collection in the graph.
 	SetVertexConstraints(ctx context.Context, collection string, constraints VertexConstraints) error
+
+	// ReplaceEdgeDefinition replaces the edge definition of an existing edge collection in the graph.
+	// The edge definition is replaced in all graphs that use the edge collection.
+	ReplaceEdgeDefinition(ctx context.Context, definition EdgeDefinition) error
+
+	// RemoveEdgeDefinition removes the edge definition of the given edge collection from the graph.
+	// If dropCollection is set, the edge collection is also removed from the database,
+	// unless it is used in another graph.
+	RemoveEdgeDefinition(ctx context.Context, collection string, dropCollection bool) error
 }
 
 // VertexConstraints limit the vertex collection you can use in an edge.
@@ -28,9 +28,7 @@ import (
 )
 
 type getGraphResponse struct {
-	Graph struct {
-		EdgeDefinitions []EdgeDefinition `json:"edgeDefinitions,omitempty"`
-	} `json:"graph"`
+	Graph GraphInfo `json:"graph"`
 }
 
 // EdgeCollection opens a connection to an existing edge-collection within the graph.
@@ -157,16 +155,28 @@ func (g *graph) CreateEdgeCollection(ctx context.Context, collection string, con
 
 // SetVertexConstraints modifies the vertex constraints of an existing edge collection in the graph.
 func (g *graph) SetVertexConstraints(ctx context.Context, collection string, constraints VertexConstraints) error {
-	req, err := g.conn.NewRequest("PUT", path.Join(g.relPath(), "edge", collection))
-	if err != nil {
-		return WithStack(err)
-	}
-	input := EdgeDefinition{
+	definition := EdgeDefinition{
 		Collection: collection,
 		From:       constraints.From,
 		To:         constraints.To,
 	}
-	if _, err := req.SetBody(input); err != nil {
+	if err := g.ReplaceEdgeDefinition(ctx, definition); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// ReplaceEdgeDefinition replaces the edge definition of an existing edge collection in the graph.
+// The edge definition is replaced in all graphs that use the edge collection.
+func (g *graph) ReplaceEdgeDefinition(ctx context.Context, definition EdgeDefinition) error {
+	if definition.Collection == "" {
+		return WithStack(InvalidArgumentError{Message: "definition.Collection is empty"})
+	}
+	req, err := g.conn.NewRequest("PUT", path.Join(g.relPath(), "edge", pathEscape(definition.Collection)))
+	if err != nil {
+		return WithStack(err)
+	}
+	if _, err := req.SetBody(definition); err != nil {
 		return WithStack(err)
 	}
 	resp, err := g.conn.Do(ctx, req)
@@ -178,3 +188,24 @@ func (g *graph) SetVertexConstraints(ctx context.Context, collection string, con
 	}
 	return nil
 }
+
+// RemoveEdgeDefinition removes the edge definition of the given edge collection from the graph.
+// If dropCollection is set, the edge collection is also removed from the database,
+// unless it is used in another graph.
+func (g *graph) RemoveEdgeDefinition(ctx context.Context, collection string, dropCollection bool) error {
+	req, err := g.conn.NewRequest("DELETE", path.Join(g.relPath(), "edge", pathEscape(collection)))
+	if err != nil {
+		return WithStack(err)
+	}
+	if dropCollection {
+		req.SetQuery("dropCollections", "true")
+	}
+	resp, err := g.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(201, 202); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
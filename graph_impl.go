@@ -59,13 +59,49 @@ func (g *graph) Name() string {
 	return g.name
 }
 
+// Info fetches the definition and properties of the graph.
+func (g *graph) Info(ctx context.Context) (GraphInfo, error) {
+	req, err := g.conn.NewRequest("GET", g.relPath())
+	if err != nil {
+		return GraphInfo{}, WithStack(err)
+	}
+	resp, err := g.conn.Do(ctx, req)
+	if err != nil {
+		return GraphInfo{}, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return GraphInfo{}, WithStack(err)
+	}
+	var data getGraphResponse
+	if err := resp.ParseBody("", &data); err != nil {
+		return GraphInfo{}, WithStack(err)
+	}
+	if data.Graph.Name == "" {
+		data.Graph.Name = g.name
+	}
+	return data.Graph, nil
+}
+
 // Remove removes the entire graph.
+// The collections of the graph are not removed.
 // If the graph does not exist, a NotFoundError is returned.
 func (g *graph) Remove(ctx context.Context) error {
+	if err := g.RemoveWithOptions(ctx, nil); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// RemoveWithOptions removes the entire graph, using the given options.
+// If the graph does not exist, a NotFoundError is returned.
+func (g *graph) RemoveWithOptions(ctx context.Context, options *RemoveGraphOptions) error {
 	req, err := g.conn.NewRequest("DELETE", g.relPath())
 	if err != nil {
 		return WithStack(err)
 	}
+	if options != nil && options.DropCollections {
+		req.SetQuery("dropCollections", "true")
+	}
 	resp, err := g.conn.Do(ctx, req)
 	if err != nil {
 		return WithStack(err)
@@ -0,0 +1,151 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"io"
+)
+
+// GraphTraversals provides graph traversal and path finding functions.
+// It is implemented by Graph, using the named graph, and by the result of
+// Database.AnonymousGraph, using a list of edge collections.
+type GraphTraversals interface {
+	// Traverse performs a traversal starting at the given vertex.
+	// Every path visited by the traversal (within the given depth limits) is returned by the cursor.
+	// Note that the returned PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+	Traverse(ctx context.Context, startVertex DocumentID, options TraversalOptions) (PathCursor, error)
+
+	// ShortestPath finds the shortest path between the given vertices.
+	// If there is no path between the vertices, a NotFoundError is returned.
+	ShortestPath(ctx context.Context, from, to DocumentID, options *ShortestPathOptions) (Path, error)
+
+	// KShortestPaths finds up to k shortest paths between the given vertices, ordered by length (or weight).
+	// Requires ArangoDB 3.5 or higher.
+	// Note that the returned PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+	KShortestPaths(ctx context.Context, from, to DocumentID, k int, options *ShortestPathOptions) (PathCursor, error)
+}
+
+// TraversalDirection specifies which edges are followed by a traversal.
+type TraversalDirection string
+
+const (
+	// TraversalDirectionOutbound follows edges from their _from to their _to vertex.
+	TraversalDirectionOutbound = TraversalDirection("OUTBOUND")
+	// TraversalDirectionInbound follows edges from their _to to their _from vertex.
+	TraversalDirectionInbound = TraversalDirection("INBOUND")
+	// TraversalDirectionAny follows edges in both directions.
+	TraversalDirectionAny = TraversalDirection("ANY")
+)
+
+// TraversalUniqueness specifies how strict a traversal is in visiting vertices or edges only once.
+type TraversalUniqueness string
+
+const (
+	// TraversalUniquenessNone performs no uniqueness check.
+	TraversalUniquenessNone = TraversalUniqueness("none")
+	// TraversalUniquenessPath ensures that a vertex or edge is not visited twice in the same path.
+	TraversalUniquenessPath = TraversalUniqueness("path")
+	// TraversalUniquenessGlobal ensures that a vertex is visited at most once during the entire traversal.
+	// Only applies to vertices and requires a breadth-first traversal.
+	TraversalUniquenessGlobal = TraversalUniqueness("global")
+)
+
+// TraversalOrder specifies the order in which a traversal visits vertices.
+type TraversalOrder string
+
+const (
+	// TraversalOrderDFS performs a depth-first traversal.
+	TraversalOrderDFS = TraversalOrder("dfs")
+	// TraversalOrderBFS performs a breadth-first traversal.
+	TraversalOrderBFS = TraversalOrder("bfs")
+)
+
+// TraversalOptions contains options that customize a graph traversal.
+type TraversalOptions struct {
+	// Direction of the traversal. (default is TraversalDirectionOutbound)
+	Direction TraversalDirection
+	// MinDepth is the minimum depth of paths to return.
+	// The default of 0 includes the start vertex as a path without edges.
+	MinDepth int
+	// MaxDepth is the maximum depth of paths to return.
+	// If 0, it defaults to MinDepth or 1, whichever is larger.
+	MaxDepth int
+	// Order in which vertices are visited. (default is TraversalOrderDFS)
+	Order TraversalOrder
+	// UniqueVertices specifies uniqueness of vertices. (default is TraversalUniquenessNone)
+	UniqueVertices TraversalUniqueness
+	// UniqueEdges specifies uniqueness of edges. (default is TraversalUniquenessPath)
+	// TraversalUniquenessGlobal is not allowed for edges.
+	UniqueEdges TraversalUniqueness
+	// EdgeFilters restricts the traversal to paths on which every edge matches all given filters.
+	EdgeFilters []TraversalEdgeFilter
+}
+
+// TraversalEdgeFilter is a condition that edges must fulfill to be followed by a traversal.
+type TraversalEdgeFilter struct {
+	// Attribute is the name of the (top level) edge attribute to compare.
+	Attribute string
+	// Value is the value the attribute must be equal to.
+	Value interface{}
+}
+
+// ShortestPathOptions contains options that customize the search for shortest paths.
+type ShortestPathOptions struct {
+	// Direction of the edges to follow. (default is TraversalDirectionOutbound)
+	Direction TraversalDirection
+	// WeightAttribute is the name of the edge attribute that contains the weight of the edge.
+	// If not set, every edge has a weight of 1.
+	WeightAttribute string
+	// DefaultWeight is the weight used for edges that do not have the WeightAttribute.
+	DefaultWeight *float64
+}
+
+// Path is a sequence of vertices, connected by edges.
+// Vertices[i] and Vertices[i+1] are connected by Edges[i].
+type Path struct {
+	// Vertices contains the encoded vertex documents of the path.
+	Vertices []RawObject `json:"vertices"`
+	// Edges contains the encoded edge documents of the path.
+	Edges []RawObject `json:"edges"`
+
+	conn Connection
+}
+
+// PathCursor is returned from a traversal, used to iterate over a list of paths.
+// Note that a PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+type PathCursor interface {
+	io.Closer
+
+	// HasMore returns true if the next call to ReadPath does not return a NoMoreDocuments error.
+	HasMore() bool
+
+	// ReadPath reads the next path from the cursor.
+	// If the cursor has no more paths, a NoMoreDocuments error is returned.
+	ReadPath(ctx context.Context) (Path, error)
+
+	// Count returns the total number of paths available.
+	// A valid return value is only available when the cursor has been created with a context that was
+	// prepared with `WithQueryCount`.
+	Count() int64
+}
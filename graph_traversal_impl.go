@@ -0,0 +1,384 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// graphTraversals implements GraphTraversals for a named graph or for a list of edge collections.
+type graphTraversals struct {
+	db              *database
+	graphName       string
+	edgeCollections []string
+}
+
+// newGraphTraversals creates a new GraphTraversals implementation.
+// Exactly one of graphName and edgeCollections must be given.
+func newGraphTraversals(db *database, graphName string, edgeCollections []string) (*graphTraversals, error) {
+	if db == nil {
+		return nil, WithStack(InvalidArgumentError{Message: "db is nil"})
+	}
+	if graphName == "" && len(edgeCollections) == 0 {
+		return nil, WithStack(InvalidArgumentError{Message: "graphName and edgeCollections are empty"})
+	}
+	for _, name := range edgeCollections {
+		if name == "" {
+			return nil, WithStack(InvalidArgumentError{Message: "edge collection name is empty"})
+		}
+	}
+	return &graphTraversals{
+		db:              db,
+		graphName:       graphName,
+		edgeCollections: edgeCollections,
+	}, nil
+}
+
+// traversals returns a GraphTraversals implementation for this graph.
+func (g *graph) traversals() *graphTraversals {
+	return &graphTraversals{db: g.db, graphName: g.name}
+}
+
+// Traverse performs a traversal starting at the given vertex.
+// Every path visited by the traversal (within the given depth limits) is returned by the cursor.
+// Note that the returned PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+func (g *graph) Traverse(ctx context.Context, startVertex DocumentID, options TraversalOptions) (PathCursor, error) {
+	return g.traversals().Traverse(ctx, startVertex, options)
+}
+
+// ShortestPath finds the shortest path between the given vertices.
+// If there is no path between the vertices, a NotFoundError is returned.
+func (g *graph) ShortestPath(ctx context.Context, from, to DocumentID, options *ShortestPathOptions) (Path, error) {
+	return g.traversals().ShortestPath(ctx, from, to, options)
+}
+
+// KShortestPaths finds up to k shortest paths between the given vertices, ordered by length (or weight).
+// Requires ArangoDB 3.5 or higher.
+// Note that the returned PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+func (g *graph) KShortestPaths(ctx context.Context, from, to DocumentID, k int, options *ShortestPathOptions) (PathCursor, error) {
+	return g.traversals().KShortestPaths(ctx, from, to, k, options)
+}
+
+// Traverse performs a traversal starting at the given vertex.
+// Every path visited by the traversal (within the given depth limits) is returned by the cursor.
+// Note that the returned PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+func (t *graphTraversals) Traverse(ctx context.Context, startVertex DocumentID, options TraversalOptions) (PathCursor, error) {
+	query, bindVars, err := t.traverseQuery(startVertex, options)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return t.queryPaths(ctx, query, bindVars)
+}
+
+// ShortestPath finds the shortest path between the given vertices.
+// If there is no path between the vertices, a NotFoundError is returned.
+func (t *graphTraversals) ShortestPath(ctx context.Context, from, to DocumentID, options *ShortestPathOptions) (Path, error) {
+	query, bindVars, err := t.shortestPathQuery(from, to, options)
+	if err != nil {
+		return Path{}, WithStack(err)
+	}
+	cursor, err := t.queryPaths(ctx, query, bindVars)
+	if err != nil {
+		return Path{}, WithStack(err)
+	}
+	defer cursor.Close()
+	p, err := cursor.ReadPath(ctx)
+	if IsNoMoreDocuments(err) {
+		return Path{}, WithStack(newArangoError(404, 0, "no path found"))
+	} else if err != nil {
+		return Path{}, WithStack(err)
+	}
+	return p, nil
+}
+
+// KShortestPaths finds up to k shortest paths between the given vertices, ordered by length (or weight).
+// Requires ArangoDB 3.5 or higher.
+// Note that the returned PathCursor must always be closed to avoid holding on to resources in the server while they are no longer needed.
+func (t *graphTraversals) KShortestPaths(ctx context.Context, from, to DocumentID, k int, options *ShortestPathOptions) (PathCursor, error) {
+	if k <= 0 {
+		return nil, WithStack(InvalidArgumentError{Message: "k must be positive"})
+	}
+	query, bindVars, err := t.kShortestPathsQuery(from, to, k, options)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return t.queryPaths(ctx, query, bindVars)
+}
+
+// queryPaths runs the given query, which must return objects with a vertices and edges field.
+func (t *graphTraversals) queryPaths(ctx context.Context, query string, bindVars map[string]interface{}) (PathCursor, error) {
+	cursor, err := t.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return &pathCursor{cursor: cursor, conn: t.db.conn}, nil
+}
+
+// source returns the AQL fragment that selects the graph or edge collections
+// to traverse and adds the bind variables it uses.
+func (t *graphTraversals) source(bindVars map[string]interface{}) string {
+	if t.graphName != "" {
+		bindVars["graph"] = t.graphName
+		return "GRAPH @graph"
+	}
+	parts := make([]string, 0, len(t.edgeCollections))
+	for i, name := range t.edgeCollections {
+		key := fmt.Sprintf("edgeCollection%d", i)
+		bindVars["@"+key] = name
+		parts = append(parts, "@@"+key)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// traverseQuery builds the AQL query for a traversal.
+func (t *graphTraversals) traverseQuery(startVertex DocumentID, options TraversalOptions) (string, map[string]interface{}, error) {
+	if err := startVertex.Validate(); err != nil {
+		return "", nil, WithStack(InvalidArgumentError{Message: err.Error()})
+	}
+	direction, err := validateTraversalDirection(options.Direction)
+	if err != nil {
+		return "", nil, WithStack(err)
+	}
+	if options.MinDepth < 0 || options.MaxDepth < 0 {
+		return "", nil, WithStack(InvalidArgumentError{Message: "depth must not be negative"})
+	}
+	maxDepth := options.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = options.MinDepth
+		if maxDepth < 1 {
+			maxDepth = 1
+		}
+	}
+	if maxDepth < options.MinDepth {
+		return "", nil, WithStack(InvalidArgumentError{Message: "MaxDepth must not be less than MinDepth"})
+	}
+	bindVars := map[string]interface{}{
+		"startVertex": startVertex.String(),
+		"minDepth":    options.MinDepth,
+		"maxDepth":    maxDepth,
+	}
+	var opts []string
+	switch options.Order {
+	case "", TraversalOrderDFS:
+		// Default
+	case TraversalOrderBFS:
+		opts = append(opts, "bfs: true")
+	default:
+		return "", nil, WithStack(InvalidArgumentError{Message: fmt.Sprintf("unknown order '%s'", options.Order)})
+	}
+	if options.UniqueVertices != "" {
+		bindVars["uniqueVertices"] = string(options.UniqueVertices)
+		opts = append(opts, "uniqueVertices: @uniqueVertices")
+	}
+	if options.UniqueEdges != "" {
+		if options.UniqueEdges == TraversalUniquenessGlobal {
+			return "", nil, WithStack(InvalidArgumentError{Message: "global uniqueness is not allowed for edges"})
+		}
+		bindVars["uniqueEdges"] = string(options.UniqueEdges)
+		opts = append(opts, "uniqueEdges: @uniqueEdges")
+	}
+	query := fmt.Sprintf("FOR v, e, p IN @minDepth..@maxDepth %s @startVertex %s", direction, t.source(bindVars))
+	if len(opts) > 0 {
+		query += " OPTIONS {" + strings.Join(opts, ", ") + "}"
+	}
+	for i, f := range options.EdgeFilters {
+		if f.Attribute == "" {
+			return "", nil, WithStack(InvalidArgumentError{Message: "edge filter attribute is empty"})
+		}
+		attrKey := fmt.Sprintf("edgeFilterAttribute%d", i)
+		valueKey := fmt.Sprintf("edgeFilterValue%d", i)
+		bindVars[attrKey] = f.Attribute
+		bindVars[valueKey] = f.Value
+		query += fmt.Sprintf(" FILTER LENGTH(p.edges[* FILTER CURRENT[@%s] != @%s]) == 0", attrKey, valueKey)
+	}
+	query += " RETURN {vertices: p.vertices, edges: p.edges}"
+	return query, bindVars, nil
+}
+
+// shortestPathQuery builds the AQL query for a shortest path.
+func (t *graphTraversals) shortestPathQuery(from, to DocumentID, options *ShortestPathOptions) (string, map[string]interface{}, error) {
+	bindVars, header, err := t.shortestPathHeader(from, to, options)
+	if err != nil {
+		return "", nil, WithStack(err)
+	}
+	query := fmt.Sprintf("LET p = (FOR v, e IN %s SHORTEST_PATH @from TO @to %s%s RETURN {v: v, e: e}) ", header.direction, header.source, header.options) +
+		"FILTER LENGTH(p) > 0 " +
+		"RETURN {vertices: p[*].v, edges: p[* FILTER CURRENT.e != null].e}"
+	return query, bindVars, nil
+}
+
+// kShortestPathsQuery builds the AQL query for k shortest paths.
+func (t *graphTraversals) kShortestPathsQuery(from, to DocumentID, k int, options *ShortestPathOptions) (string, map[string]interface{}, error) {
+	bindVars, header, err := t.shortestPathHeader(from, to, options)
+	if err != nil {
+		return "", nil, WithStack(err)
+	}
+	bindVars["k"] = k
+	query := fmt.Sprintf("FOR p IN %s K_SHORTEST_PATHS @from TO @to %s%s LIMIT @k RETURN {vertices: p.vertices, edges: p.edges}",
+		header.direction, header.source, header.options)
+	return query, bindVars, nil
+}
+
+// shortestPathHeader contains the query fragments shared by shortest path queries.
+type shortestPathHeader struct {
+	direction TraversalDirection
+	source    string
+	options   string
+}
+
+// shortestPathHeader validates the arguments of a shortest path query and builds the shared query fragments.
+func (t *graphTraversals) shortestPathHeader(from, to DocumentID, options *ShortestPathOptions) (map[string]interface{}, shortestPathHeader, error) {
+	if err := from.Validate(); err != nil {
+		return nil, shortestPathHeader{}, WithStack(InvalidArgumentError{Message: err.Error()})
+	}
+	if err := to.Validate(); err != nil {
+		return nil, shortestPathHeader{}, WithStack(InvalidArgumentError{Message: err.Error()})
+	}
+	if options == nil {
+		options = &ShortestPathOptions{}
+	}
+	direction, err := validateTraversalDirection(options.Direction)
+	if err != nil {
+		return nil, shortestPathHeader{}, WithStack(err)
+	}
+	bindVars := map[string]interface{}{
+		"from": from.String(),
+		"to":   to.String(),
+	}
+	var opts []string
+	if options.WeightAttribute != "" {
+		bindVars["weightAttribute"] = options.WeightAttribute
+		opts = append(opts, "weightAttribute: @weightAttribute")
+	}
+	if options.DefaultWeight != nil {
+		bindVars["defaultWeight"] = *options.DefaultWeight
+		opts = append(opts, "defaultWeight: @defaultWeight")
+	}
+	header := shortestPathHeader{
+		direction: direction,
+		source:    t.source(bindVars),
+	}
+	if len(opts) > 0 {
+		header.options = " OPTIONS {" + strings.Join(opts, ", ") + "}"
+	}
+	return bindVars, header, nil
+}
+
+// validateTraversalDirection validates the given direction, returning the default direction when empty.
+func validateTraversalDirection(d TraversalDirection) (TraversalDirection, error) {
+	switch d {
+	case "":
+		return TraversalDirectionOutbound, nil
+	case TraversalDirectionOutbound, TraversalDirectionInbound, TraversalDirectionAny:
+		return d, nil
+	default:
+		return "", WithStack(InvalidArgumentError{Message: fmt.Sprintf("unknown direction '%s'", d)})
+	}
+}
+
+// pathCursor implements PathCursor on top of a query cursor.
+type pathCursor struct {
+	cursor Cursor
+	conn   Connection
+}
+
+// Close deletes the cursor and frees the resources associated with it.
+func (c *pathCursor) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.cursor.Close()
+}
+
+// HasMore returns true if the next call to ReadPath does not return a NoMoreDocuments error.
+func (c *pathCursor) HasMore() bool {
+	return c.cursor.HasMore()
+}
+
+// ReadPath reads the next path from the cursor.
+// If the cursor has no more paths, a NoMoreDocuments error is returned.
+func (c *pathCursor) ReadPath(ctx context.Context) (Path, error) {
+	var p Path
+	if _, err := c.cursor.ReadDocument(ctx, &p); err != nil {
+		return Path{}, WithStack(err)
+	}
+	p.conn = c.conn
+	return p, nil
+}
+
+// Count returns the total number of paths available.
+// A valid return value is only available when the cursor has been created with a context that was
+// prepared with `WithQueryCount`.
+func (c *pathCursor) Count() int64 {
+	return c.cursor.Count()
+}
+
+// Length returns the number of edges in the path.
+func (p Path) Length() int {
+	return len(p.Edges)
+}
+
+// ReadVertex unmarshals the vertex at the given index into result and returns its meta data.
+func (p Path) ReadVertex(index int, result interface{}) (DocumentMeta, error) {
+	return p.read(p.Vertices, index, result)
+}
+
+// ReadEdge unmarshals the edge at the given index into result and returns its meta data.
+func (p Path) ReadEdge(index int, result interface{}) (DocumentMeta, error) {
+	return p.read(p.Edges, index, result)
+}
+
+// VertexIDs returns the IDs of all vertices in the path.
+func (p Path) VertexIDs() ([]DocumentID, error) {
+	result := make([]DocumentID, len(p.Vertices))
+	for i := range p.Vertices {
+		meta, err := p.read(p.Vertices, i, nil)
+		if err != nil {
+			return nil, WithStack(err)
+		}
+		result[i] = meta.ID
+	}
+	return result, nil
+}
+
+// read unmarshals the document at the given index of the given list into result (if not nil).
+func (p Path) read(list []RawObject, index int, result interface{}) (DocumentMeta, error) {
+	if index < 0 || index >= len(list) {
+		return DocumentMeta{}, WithStack(InvalidArgumentError{Message: fmt.Sprintf("index %d out of range", index)})
+	}
+	if p.conn == nil {
+		return DocumentMeta{}, WithStack(InvalidArgumentError{Message: "path is not connected"})
+	}
+	var meta DocumentMeta
+	if err := p.conn.Unmarshal(list[index], &meta); err != nil {
+		return DocumentMeta{}, WithStack(err)
+	}
+	if result != nil {
+		if err := p.conn.Unmarshal(list[index], result); err != nil {
+			return DocumentMeta{}, WithStack(err)
+		}
+	}
+	return meta, nil
+}
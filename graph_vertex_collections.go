@@ -41,4 +41,10 @@ type GraphVertexCollections interface {
 	// CreateVertexCollection creates a vertex collection in the graph.
 	// collection: The name of the vertex collection to be used.
 	CreateVertexCollection(ctx context.Context, collection string) (Collection, error)
+
+	// RemoveVertexCollection removes the vertex collection with given name from the graph.
+	// The vertex collection must not be used in any edge definition of the graph.
+	// If dropCollection is set, the vertex collection is also removed from the database,
+	// unless it is used in another graph.
+	RemoveVertexCollection(ctx context.Context, name string, dropCollection bool) error
 }
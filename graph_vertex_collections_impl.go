@@ -143,3 +143,25 @@ func (g *graph) CreateVertexCollection(ctx context.Context, collection string) (
 	}
 	return ec, nil
 }
+
+// RemoveVertexCollection removes the vertex collection with given name from the graph.
+// The vertex collection must not be used in any edge definition of the graph.
+// If dropCollection is set, the vertex collection is also removed from the database,
+// unless it is used in another graph.
+func (g *graph) RemoveVertexCollection(ctx context.Context, name string, dropCollection bool) error {
+	req, err := g.conn.NewRequest("DELETE", path.Join(g.relPath(), "vertex", pathEscape(name)))
+	if err != nil {
+		return WithStack(err)
+	}
+	if dropCollection {
+		req.SetQuery("dropCollection", "true")
+	}
+	resp, err := g.conn.Do(ctx, req)
+	if err != nil {
+		return WithStack(err)
+	}
+	if err := resp.CheckStatus(201, 202); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
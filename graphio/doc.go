@@ -0,0 +1,49 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+/*
+Package graphio exports named graphs to, and imports them from, files.
+
+An export contains the definition of the graph (edge definitions, orphan
+collections and sharding options) followed by all vertex and edge documents.
+Two formats are supported: a JSON format that closely follows the structure
+of the graph, and GraphML, for use with other graph tools.
+
+	f, err := os.Create("social.json")
+	...
+	err = graphio.Export(ctx, db, "social", f, graphio.FormatJSON)
+
+Importing recreates the collections and edge definitions using CreateGraph
+and bulk-loads the documents using ImportDocuments.
+Collections can be renamed on import, which also re-targets the `_from` and `_to`
+attributes of all edges.
+
+	f, err := os.Open("social.json")
+	...
+	g, err := graphio.Import(ctx, db, f, graphio.FormatJSON, &graphio.ImportOptions{
+		GraphName: "social_copy",
+		CollectionNames: map[string]string{"persons": "persons_copy", "knows": "knows_copy"},
+	})
+
+The document keys are preserved, the document revisions are not.
+*/
+package graphio
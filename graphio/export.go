@@ -0,0 +1,118 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package graphio
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// graphWriter is implemented by the writers of the supported formats.
+// The functions are called in the order of the exported structure.
+type graphWriter interface {
+	// Begin writes the start of the export, including the graph definition.
+	Begin(info driver.GraphInfo) error
+	// BeginCollection writes the start of a collection.
+	BeginCollection(name string, colType collectionType) error
+	// Document writes a single document of the current collection.
+	Document(collection string, colType collectionType, doc map[string]interface{}) error
+	// EndCollection writes the end of the current collection.
+	EndCollection() error
+	// End writes the end of the export.
+	End() error
+}
+
+// newGraphWriter creates a writer for the given format.
+func newGraphWriter(w io.Writer, format Format) (graphWriter, error) {
+	if err := format.validate(); err != nil {
+		return nil, driver.WithStack(err)
+	}
+	if format == FormatGraphML {
+		return newGraphMLWriter(w), nil
+	}
+	return newJSONWriter(w), nil
+}
+
+// Export writes the definition and all documents of the named graph with given name to w, using the given format.
+func Export(ctx context.Context, db driver.Database, graphName string, w io.Writer, format Format) error {
+	gw, err := newGraphWriter(w, format)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	g, err := db.Graph(ctx, graphName)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	info, err := g.Info(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	info.Name = graphName
+	if err := gw.Begin(info); err != nil {
+		return driver.WithStack(err)
+	}
+	names, types := graphCollections(info)
+	for _, name := range names {
+		if err := exportCollection(ctx, db, name, types[name], gw); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	if err := gw.End(); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// exportCollection writes all documents of the collection with given name to the given writer.
+func exportCollection(ctx context.Context, db driver.Database, name string, colType collectionType, gw graphWriter) error {
+	if err := gw.BeginCollection(name, colType); err != nil {
+		return driver.WithStack(err)
+	}
+	query := "FOR d IN @@collection SORT d._key RETURN UNSET(d, '_id', '_rev')"
+	cursor, err := db.Query(ctx, query, map[string]interface{}{"@collection": name})
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	defer cursor.Close()
+	for cursor.HasMore() {
+		// Read the raw document, so large integers are not converted to float64.
+		var raw json.RawMessage
+		if _, err := cursor.ReadDocument(ctx, &raw); err != nil {
+			return driver.WithStack(err)
+		}
+		doc, err := decodeDocument(raw)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if err := gw.Document(name, colType, doc); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	if err := gw.EndCollection(); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
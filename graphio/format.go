@@ -0,0 +1,142 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package graphio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// Format is the file format of an exported graph.
+type Format string
+
+const (
+	// FormatJSON is a JSON document containing the graph definition and all documents.
+	FormatJSON = Format("json")
+	// FormatGraphML is a GraphML document. The graph definition and the documents
+	// are stored as JSON encoded data elements.
+	FormatGraphML = Format("graphml")
+)
+
+// validate returns an error if the format is not supported.
+func (f Format) validate() error {
+	switch f {
+	case FormatJSON, FormatGraphML:
+		return nil
+	default:
+		return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("unknown format '%s'", f)})
+	}
+}
+
+// collectionType is the type of a collection in an export.
+type collectionType string
+
+const (
+	collectionTypeDocument = collectionType("document")
+	collectionTypeEdge     = collectionType("edge")
+)
+
+// graphData is the in-memory representation of an exported graph.
+type graphData struct {
+	Graph       driver.GraphInfo `json:"graph"`
+	Collections []collectionData `json:"collections"`
+}
+
+// collectionData contains all documents of a single collection of an exported graph.
+type collectionData struct {
+	Name      string                   `json:"name"`
+	Type      collectionType           `json:"type"`
+	Documents []map[string]interface{} `json:"documents"`
+}
+
+// graphCollections returns the names of all collections of the given graph,
+// vertex collections first, followed by the edge collections.
+// The type of every collection is returned as well.
+func graphCollections(info driver.GraphInfo) ([]string, map[string]collectionType) {
+	var names []string
+	types := make(map[string]collectionType)
+	add := func(name string, t collectionType) {
+		if _, found := types[name]; !found {
+			names = append(names, name)
+			types[name] = t
+		}
+	}
+	for _, def := range info.EdgeDefinitions {
+		for _, name := range def.From {
+			add(name, collectionTypeDocument)
+		}
+		for _, name := range def.To {
+			add(name, collectionTypeDocument)
+		}
+	}
+	for _, name := range info.OrphanCollections {
+		add(name, collectionTypeDocument)
+	}
+	for _, def := range info.EdgeDefinitions {
+		add(def.Collection, collectionTypeEdge)
+	}
+	return names, types
+}
+
+// decodeDocument decodes a JSON encoded document.
+// Numbers are decoded by exactNumbers, so large integers are not corrupted.
+func decodeDocument(encoded []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, driver.WithStack(err)
+	}
+	exactNumbers(doc)
+	return doc, nil
+}
+
+// exactNumbers replaces all json.Number values in the given decoded value
+// with an int64, uint64 or (if not an integer) float64 value.
+// A json.Number is not used as such, since it is encoded as a string by velocypack.
+func exactNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+			return u
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = exactNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range x {
+			x[i] = exactNumbers(e)
+		}
+	}
+	return v
+}
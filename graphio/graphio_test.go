@@ -0,0 +1,174 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package graphio
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// testGraphData returns a small graph for use in tests.
+func testGraphData() graphData {
+	return graphData{
+		Graph: driver.GraphInfo{
+			Name: "social",
+			EdgeDefinitions: []driver.EdgeDefinition{
+				{Collection: "knows", From: []string{"persons"}, To: []string{"persons", "robots"}},
+			},
+			OrphanCollections: []string{"cities"},
+			NumberOfShards:    2,
+		},
+		Collections: []collectionData{
+			{Name: "persons", Type: collectionTypeDocument, Documents: []map[string]interface{}{
+				{"_key": "alice", "name": "Alice <&>", "age": int64(42), "height": 1.65, "id": int64(9007199254740993)},
+				{"_key": "bob", "name": "Bob"},
+			}},
+			{Name: "robots", Type: collectionTypeDocument, Documents: []map[string]interface{}{
+				{"_key": "r2", "model": "astromech"},
+			}},
+			{Name: "cities", Type: collectionTypeDocument},
+			{Name: "knows", Type: collectionTypeEdge, Documents: []map[string]interface{}{
+				{"_key": "1", "_from": "persons/alice", "_to": "persons/bob", "since": int64(2010), "tags": []interface{}{uint64(18446744073709551615)}},
+				{"_key": "2", "_from": "persons/bob", "_to": "robots/r2"},
+			}},
+		},
+	}
+}
+
+// writeGraphData writes the given graph data using the given format.
+func writeGraphData(data graphData, format Format, t *testing.T) []byte {
+	var buf bytes.Buffer
+	gw, err := newGraphWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("newGraphWriter failed: %s", err)
+	}
+	if err := gw.Begin(data.Graph); err != nil {
+		t.Fatalf("Begin failed: %s", err)
+	}
+	for _, cd := range data.Collections {
+		if err := gw.BeginCollection(cd.Name, cd.Type); err != nil {
+			t.Fatalf("BeginCollection failed: %s", err)
+		}
+		for _, doc := range cd.Documents {
+			if err := gw.Document(cd.Name, cd.Type, doc); err != nil {
+				t.Fatalf("Document failed: %s", err)
+			}
+		}
+		if err := gw.EndCollection(); err != nil {
+			t.Fatalf("EndCollection failed: %s", err)
+		}
+	}
+	if err := gw.End(); err != nil {
+		t.Fatalf("End failed: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	expected := testGraphData()
+	encoded := writeGraphData(expected, FormatJSON, t)
+	actual, err := readJSON(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("readJSON failed: %s\n%s", err, string(encoded))
+	}
+	// Empty document lists are decoded as empty slices
+	expected.Collections[2].Documents = []map[string]interface{}{}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Round trip failed.\nExpected %+v\nGot      %+v", expected, actual)
+	}
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	expected := testGraphData()
+	encoded := writeGraphData(expected, FormatGraphML, t)
+	actual, err := readGraphML(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("readGraphML failed: %s\n%s", err, string(encoded))
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Round trip failed.\nExpected %+v\nGot      %+v", expected, actual)
+	}
+}
+
+func TestGraphMLWithoutDocuments(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <graph id="g" edgedefault="directed">
+    <data key="definition">{"edgeDefinitions":[{"collection":"e","from":["v"],"to":["v"]}]}</data>
+    <node id="v/1"/>
+    <node id="v/2"/>
+    <edge id="e/a" source="v/1" target="v/2"/>
+  </graph>
+</graphml>`
+	actual, err := readGraphML(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("readGraphML failed: %s", err)
+	}
+	if actual.Graph.Name != "g" {
+		t.Errorf("Expected graph name 'g', got '%s'", actual.Graph.Name)
+	}
+	expected := []collectionData{
+		{Name: "v", Type: collectionTypeDocument, Documents: []map[string]interface{}{{"_key": "1"}, {"_key": "2"}}},
+		{Name: "e", Type: collectionTypeEdge, Documents: []map[string]interface{}{{"_key": "a", "_from": "v/1", "_to": "v/2"}}},
+	}
+	if !reflect.DeepEqual(expected, actual.Collections) {
+		t.Errorf("Expected %+v, got %+v", expected, actual.Collections)
+	}
+}
+
+func TestRetargetEdges(t *testing.T) {
+	data := testGraphData()
+	rename := func(name string) string {
+		if name == "robots" {
+			return "droids"
+		}
+		return name
+	}
+	docs, fromPrefix, toPrefix := retargetEdges(data.Collections[3].Documents, data.Graph.EdgeDefinitions[0], rename)
+	if fromPrefix != "persons" {
+		t.Errorf("Expected fromPrefix 'persons', got '%s'", fromPrefix)
+	}
+	if toPrefix != "" {
+		t.Errorf("Expected empty toPrefix, got '%s'", toPrefix)
+	}
+	expected := []map[string]interface{}{
+		{"_key": "1", "_from": "alice", "_to": "persons/bob", "since": int64(2010), "tags": []interface{}{uint64(18446744073709551615)}},
+		{"_key": "2", "_from": "bob", "_to": "droids/r2"},
+	}
+	if !reflect.DeepEqual(expected, docs) {
+		t.Errorf("Expected %+v, got %+v", expected, docs)
+	}
+	// Input must not be modified
+	if data.Collections[3].Documents[0]["_from"] != "persons/alice" {
+		t.Errorf("Input documents have been modified")
+	}
+}
+
+func TestInvalidFormat(t *testing.T) {
+	if _, err := newGraphWriter(&bytes.Buffer{}, Format("csv")); !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
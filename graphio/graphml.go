@@ -0,0 +1,243 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package graphio
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="definition" for="graph" attr.name="definition" attr.type="string"/>
+  <key id="collection" for="all" attr.name="collection" attr.type="string"/>
+  <key id="document" for="all" attr.name="document" attr.type="string"/>
+`
+	graphMLKeyDefinition = "definition"
+	graphMLKeyCollection = "collection"
+	graphMLKeyDocument   = "document"
+)
+
+// graphMLWriter writes an export in the GraphML format.
+// Vertices are written as nodes, edges as edges. The graph definition and
+// the documents are stored as JSON encoded data elements.
+type graphMLWriter struct {
+	w io.Writer
+}
+
+// newGraphMLWriter creates a new writer for the GraphML format.
+func newGraphMLWriter(w io.Writer) *graphMLWriter {
+	return &graphMLWriter{w: w}
+}
+
+// write writes the given strings.
+func (gw *graphMLWriter) write(s ...string) error {
+	for _, x := range s {
+		if _, err := io.WriteString(gw.w, x); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// escape returns the given string, escaped for use in XML text and attribute values.
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// encodeJSON returns the escaped JSON encoding of the given value.
+func encodeJSON(v interface{}) (string, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", driver.WithStack(err)
+	}
+	return escape(string(encoded)), nil
+}
+
+// Begin writes the start of the export, including the graph definition.
+func (gw *graphMLWriter) Begin(info driver.GraphInfo) error {
+	definition, err := encodeJSON(info)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	return driver.WithStack(gw.write(
+		graphMLHeader,
+		`  <graph id="`, escape(info.Name), `" edgedefault="directed">`, "\n",
+		`    <data key="`, graphMLKeyDefinition, `">`, definition, "</data>\n",
+	))
+}
+
+// BeginCollection writes the start of a collection.
+// GraphML has no notion of collections, so nothing is written.
+func (gw *graphMLWriter) BeginCollection(name string, colType collectionType) error {
+	return nil
+}
+
+// Document writes a single document of the current collection.
+func (gw *graphMLWriter) Document(collection string, colType collectionType, doc map[string]interface{}) error {
+	key, _ := doc["_key"].(string)
+	id := escape(collection + "/" + key)
+	encoded, err := encodeJSON(doc)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	data := fmt.Sprintf(`<data key="%s">%s</data><data key="%s">%s</data>`, graphMLKeyCollection, escape(collection), graphMLKeyDocument, encoded)
+	if colType == collectionTypeEdge {
+		from, _ := doc["_from"].(string)
+		to, _ := doc["_to"].(string)
+		return driver.WithStack(gw.write(`    <edge id="`, id, `" source="`, escape(from), `" target="`, escape(to), `">`, data, "</edge>\n"))
+	}
+	return driver.WithStack(gw.write(`    <node id="`, id, `">`, data, "</node>\n"))
+}
+
+// EndCollection writes the end of the current collection.
+func (gw *graphMLWriter) EndCollection() error {
+	return nil
+}
+
+// End writes the end of the export.
+func (gw *graphMLWriter) End() error {
+	return driver.WithStack(gw.write("  </graph>\n</graphml>\n"))
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name `xml:"graphml"`
+	Graph   struct {
+		ID    string           `xml:"id,attr"`
+		Data  []graphMLData    `xml:"data"`
+		Nodes []graphMLElement `xml:"node"`
+		Edges []graphMLElement `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLElement struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// data returns the value of the data element with given key.
+func data(list []graphMLData, key string) (string, bool) {
+	for _, d := range list {
+		if d.Key == key {
+			return d.Value, true
+		}
+	}
+	return "", false
+}
+
+// readGraphML reads an export in the GraphML format.
+// Nodes and edges without a document data element get a document that only
+// contains the key (and for edges `_from` & `_to`) derived from the element.
+func readGraphML(r io.Reader) (graphData, error) {
+	var doc graphMLDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return graphData{}, driver.WithStack(err)
+	}
+	var result graphData
+	definition, found := data(doc.Graph.Data, graphMLKeyDefinition)
+	if !found {
+		return graphData{}, driver.WithStack(driver.InvalidArgumentError{Message: "GraphML document contains no graph definition"})
+	}
+	if err := json.Unmarshal([]byte(definition), &result.Graph); err != nil {
+		return graphData{}, driver.WithStack(err)
+	}
+	if result.Graph.Name == "" {
+		result.Graph.Name = doc.Graph.ID
+	}
+	names, types := graphCollections(result.Graph)
+	collections := make(map[string]*collectionData)
+	for _, name := range names {
+		result.Collections = append(result.Collections, collectionData{Name: name, Type: types[name]})
+	}
+	for i := range result.Collections {
+		collections[result.Collections[i].Name] = &result.Collections[i]
+	}
+	add := func(e graphMLElement, colType collectionType) error {
+		collection, found := data(e.Data, graphMLKeyCollection)
+		if !found {
+			collection = collectionOf(e.ID)
+		}
+		col, found := collections[collection]
+		if !found || col.Type != colType {
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("element '%s' refers to unknown %s collection '%s'", e.ID, colType, collection)})
+		}
+		var d map[string]interface{}
+		if encoded, found := data(e.Data, graphMLKeyDocument); found {
+			var err error
+			if d, err = decodeDocument([]byte(encoded)); err != nil {
+				return driver.WithStack(err)
+			}
+		} else {
+			d = map[string]interface{}{"_key": keyOf(e.ID)}
+			if colType == collectionTypeEdge {
+				d["_from"] = e.Source
+				d["_to"] = e.Target
+			}
+		}
+		col.Documents = append(col.Documents, d)
+		return nil
+	}
+	for _, e := range doc.Graph.Nodes {
+		if err := add(e, collectionTypeDocument); err != nil {
+			return graphData{}, driver.WithStack(err)
+		}
+	}
+	for _, e := range doc.Graph.Edges {
+		if err := add(e, collectionTypeEdge); err != nil {
+			return graphData{}, driver.WithStack(err)
+		}
+	}
+	return result, nil
+}
+
+// collectionOf returns the collection part of the given ID, or an empty string if it contains no collection part.
+func collectionOf(id string) string {
+	if idx := strings.Index(id, "/"); idx >= 0 {
+		return id[:idx]
+	}
+	return ""
+}
+
+// keyOf returns the key part of the given ID, or the ID itself if it contains no collection part.
+func keyOf(id string) string {
+	if idx := strings.Index(id, "/"); idx >= 0 {
+		return id[idx+1:]
+	}
+	return id
+}
@@ -0,0 +1,199 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package graphio
+
+import (
+	"context"
+	"io"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultBatchSize is the default number of documents imported per request.
+	DefaultBatchSize = 1000
+)
+
+// ImportOptions contains options that customize the import of a graph.
+type ImportOptions struct {
+	// GraphName is the name of the graph to create.
+	// Defaults to the name of the exported graph.
+	GraphName string
+	// CollectionNames maps names of collections in the export to names of collections to import into.
+	// Collections that are not in the map keep their name.
+	// The `_from` and `_to` attributes of all edges are re-targeted accordingly.
+	CollectionNames map[string]string
+	// UseExistingGraph, if set, imports the documents into the collections of an existing graph
+	// with the same name, instead of failing with a DuplicateError.
+	UseExistingGraph bool
+	// Overwrite, if set, removes all documents from the collections before importing.
+	Overwrite bool
+	// BatchSize is the number of documents imported per request.
+	// Defaults to DefaultBatchSize.
+	BatchSize int
+}
+
+// Import reads a graph in the given format from r, creates the graph with all its collections
+// and edge definitions and imports all documents.
+// Note that the replication factor of the exported graph is not restored.
+func Import(ctx context.Context, db driver.Database, r io.Reader, format Format, options *ImportOptions) (driver.Graph, error) {
+	if err := format.validate(); err != nil {
+		return nil, driver.WithStack(err)
+	}
+	if options == nil {
+		options = &ImportOptions{}
+	}
+	var data graphData
+	var err error
+	if format == FormatGraphML {
+		data, err = readGraphML(r)
+	} else {
+		data, err = readJSON(r)
+	}
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	rename := func(name string) string {
+		if newName, found := options.CollectionNames[name]; found {
+			return newName
+		}
+		return name
+	}
+	graphName := options.GraphName
+	if graphName == "" {
+		graphName = data.Graph.Name
+	}
+	if graphName == "" {
+		return nil, driver.WithStack(driver.InvalidArgumentError{Message: "graph name is empty"})
+	}
+
+	// Create the graph
+	createOptions := &driver.CreateGraphOptions{
+		IsSmart:             data.Graph.IsSmart,
+		SmartGraphAttribute: data.Graph.SmartGraphAttribute,
+		NumberOfShards:      data.Graph.NumberOfShards,
+	}
+	definitions := make(map[string]driver.EdgeDefinition)
+	for _, def := range data.Graph.EdgeDefinitions {
+		definitions[def.Collection] = def
+		createOptions.EdgeDefinitions = append(createOptions.EdgeDefinitions, driver.EdgeDefinition{
+			Collection: rename(def.Collection),
+			From:       renameAll(def.From, rename),
+			To:         renameAll(def.To, rename),
+		})
+	}
+	createOptions.OrphanVertexCollections = renameAll(data.Graph.OrphanCollections, rename)
+	g, err := db.CreateGraph(ctx, graphName, createOptions)
+	if driver.IsConflict(err) && options.UseExistingGraph {
+		g, err = db.Graph(ctx, graphName)
+	}
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+
+	// Import the documents
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	for _, cd := range data.Collections {
+		col, err := db.Collection(ctx, rename(cd.Name))
+		if err != nil {
+			return nil, driver.WithStack(err)
+		}
+		if options.Overwrite {
+			if err := col.Truncate(ctx); err != nil {
+				return nil, driver.WithStack(err)
+			}
+		}
+		importOptions := &driver.ImportDocumentOptions{
+			Complete: true,
+		}
+		docs := cd.Documents
+		if cd.Type == collectionTypeEdge {
+			def := definitions[cd.Name]
+			docs, importOptions.FromPrefix, importOptions.ToPrefix = retargetEdges(cd.Documents, def, rename)
+		}
+		for start := 0; start < len(docs); start += batchSize {
+			end := start + batchSize
+			if end > len(docs) {
+				end = len(docs)
+			}
+			if _, err := col.ImportDocuments(ctx, docs[start:end], importOptions); err != nil {
+				return nil, driver.WithStack(err)
+			}
+		}
+	}
+	return g, nil
+}
+
+// renameAll returns the given names, renamed by the given function.
+func renameAll(names []string, rename func(string) string) []string {
+	if names == nil {
+		return nil
+	}
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = rename(name)
+	}
+	return result
+}
+
+// retargetEdges returns copies of the given edge documents with their `_from` and `_to`
+// attributes re-targeted to the renamed vertex collections.
+// When all edges come from (or go to) a single vertex collection, the attribute is reduced
+// to the key and the renamed collection is returned as prefix, to be used as FromPrefix (or ToPrefix)
+// of the import.
+func retargetEdges(docs []map[string]interface{}, def driver.EdgeDefinition, rename func(string) string) ([]map[string]interface{}, string, string) {
+	var fromPrefix, toPrefix string
+	if len(def.From) == 1 {
+		fromPrefix = rename(def.From[0])
+	}
+	if len(def.To) == 1 {
+		toPrefix = rename(def.To[0])
+	}
+	retarget := func(value interface{}, prefix string) interface{} {
+		id, ok := value.(string)
+		if !ok {
+			return value
+		}
+		if prefix != "" {
+			return keyOf(id)
+		}
+		if collection := collectionOf(id); collection != "" {
+			return rename(collection) + "/" + keyOf(id)
+		}
+		return id
+	}
+	result := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		c := make(map[string]interface{}, len(doc))
+		for k, v := range doc {
+			c[k] = v
+		}
+		c["_from"] = retarget(doc["_from"], fromPrefix)
+		c["_to"] = retarget(doc["_to"], toPrefix)
+		result[i] = c
+	}
+	return result, fromPrefix, toPrefix
+}
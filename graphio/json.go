@@ -0,0 +1,140 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package graphio
+
+import (
+	"encoding/json"
+	"io"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// jsonWriter writes an export in the JSON format.
+// Documents are written one by one, so the export is never held in memory completely.
+type jsonWriter struct {
+	w               io.Writer
+	collectionCount int
+	documentCount   int
+}
+
+// newJSONWriter creates a new writer for the JSON format.
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: w}
+}
+
+// write writes the given strings.
+func (jw *jsonWriter) write(s ...string) error {
+	for _, x := range s {
+		if _, err := io.WriteString(jw.w, x); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// writeValue writes the JSON encoding of the given value.
+func (jw *jsonWriter) writeValue(v interface{}) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if _, err := jw.w.Write(encoded); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// Begin writes the start of the export, including the graph definition.
+func (jw *jsonWriter) Begin(info driver.GraphInfo) error {
+	if err := jw.write(`{"graph":`); err != nil {
+		return driver.WithStack(err)
+	}
+	if err := jw.writeValue(info); err != nil {
+		return driver.WithStack(err)
+	}
+	return driver.WithStack(jw.write(",\n", `"collections":[`))
+}
+
+// BeginCollection writes the start of a collection.
+func (jw *jsonWriter) BeginCollection(name string, colType collectionType) error {
+	if jw.collectionCount > 0 {
+		if err := jw.write(","); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	jw.collectionCount++
+	jw.documentCount = 0
+	if err := jw.write("\n", `{"name":`); err != nil {
+		return driver.WithStack(err)
+	}
+	if err := jw.writeValue(name); err != nil {
+		return driver.WithStack(err)
+	}
+	if err := jw.write(`,"type":`); err != nil {
+		return driver.WithStack(err)
+	}
+	if err := jw.writeValue(colType); err != nil {
+		return driver.WithStack(err)
+	}
+	return driver.WithStack(jw.write(`,"documents":[`))
+}
+
+// Document writes a single document of the current collection.
+func (jw *jsonWriter) Document(collection string, colType collectionType, doc map[string]interface{}) error {
+	if jw.documentCount > 0 {
+		if err := jw.write(","); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+	jw.documentCount++
+	if err := jw.write("\n"); err != nil {
+		return driver.WithStack(err)
+	}
+	return driver.WithStack(jw.writeValue(doc))
+}
+
+// EndCollection writes the end of the current collection.
+func (jw *jsonWriter) EndCollection() error {
+	return driver.WithStack(jw.write("]}"))
+}
+
+// End writes the end of the export.
+func (jw *jsonWriter) End() error {
+	return driver.WithStack(jw.write("\n]}\n"))
+}
+
+// readJSON reads an export in the JSON format.
+func readJSON(r io.Reader) (graphData, error) {
+	var data graphData
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return graphData{}, driver.WithStack(err)
+	}
+	for _, cd := range data.Collections {
+		for _, doc := range cd.Documents {
+			exactNumbers(doc)
+		}
+	}
+	return data, nil
+}
@@ -28,8 +28,10 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/util"
 )
 
 // Authentication implements a kind of authentication.
@@ -41,6 +43,46 @@ type httpAuthentication interface {
 	Configure(req driver.Request) error
 }
 
+// httpRenewableAuthentication is implemented by authentications that use credentials which expire.
+// Such credentials are renewed by calling Prepare again.
+type httpRenewableAuthentication interface {
+	httpAuthentication
+
+	// NeedsRenewal returns true when the credentials must be renewed before making another request.
+	NeedsRenewal() bool
+}
+
+// newHTTPAuthentication creates an authentication implementation for the given authentication.
+func newHTTPAuthentication(auth driver.Authentication) (httpAuthentication, error) {
+	switch auth.Type() {
+	case driver.AuthenticationTypeBasic:
+		userName := auth.Get("username")
+		password := auth.Get("password")
+		return newBasicAuthentication(userName, password), nil
+	case driver.AuthenticationTypeJWT:
+		userName := auth.Get("username")
+		password := auth.Get("password")
+		return newJWTAuthentication(userName, password), nil
+	case driver.AuthenticationTypeJWTSecret:
+		source, ok := auth.(driver.JWTTokenSource)
+		if !ok {
+			return nil, driver.WithStack(driver.InvalidArgumentError{Message: "JWT secret authentication must implement JWTTokenSource"})
+		}
+		return newJWTSecretAuthentication(source), nil
+	case driver.AuthenticationTypeRaw:
+		value := auth.Get("value")
+		return newRawAuthentication(value), nil
+	case driver.AuthenticationTypeProvided:
+		provider, ok := auth.(driver.CredentialsProvider)
+		if !ok {
+			return nil, driver.WithStack(driver.InvalidArgumentError{Message: "Provided authentication must implement CredentialsProvider"})
+		}
+		return newProvidedAuthentication(provider), nil
+	default:
+		return nil, driver.WithStack(fmt.Errorf("Unsupported authentication type %d", int(auth.Type())))
+	}
+}
+
 // newBasicAuthentication creates an authentication implementation based on the given username & password.
 func newBasicAuthentication(userName, password string) httpAuthentication {
 	auth := fmt.Sprintf("%s:%s", userName, password)
@@ -58,6 +100,14 @@ func newJWTAuthentication(userName, password string) httpAuthentication {
 	}
 }
 
+// newJWTSecretAuthentication creates a JWT token authentication implementation that obtains
+// its tokens from the given source, instead of requesting them from the server.
+func newJWTSecretAuthentication(source driver.JWTTokenSource) httpAuthentication {
+	return &jwtAuthentication{
+		source: source,
+	}
+}
+
 // newRawAuthentication creates a Raw authentication implementation based on the given value.
 func newRawAuthentication(value string) httpAuthentication {
 	return &basicAuthentication{
@@ -86,7 +136,10 @@ func (a *basicAuthentication) Configure(req driver.Request) error {
 type jwtAuthentication struct {
 	userName string
 	password string
+	source   driver.JWTTokenSource // If set, tokens are obtained from here instead of the server
+	mutex    sync.RWMutex
 	token    string
+	renewAt  time.Time
 }
 
 type jwtOpenRequest struct {
@@ -101,10 +154,39 @@ type jwtOpenResponse struct {
 
 // Prepare is called before the first request of the given connection is made.
 func (a *jwtAuthentication) Prepare(ctx context.Context, conn driver.Connection) error {
+	token, err := a.fetchToken(ctx, conn)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+
+	// Store token
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.token = token
+	if expiresAt, found := util.JWTExpiresAt(token); found {
+		a.renewAt = util.JWTRenewAt(time.Now(), expiresAt)
+	} else {
+		a.renewAt = time.Time{}
+	}
+
+	// Ok
+	return nil
+}
+
+// fetchToken obtains a new token, either from the token source or from the server.
+func (a *jwtAuthentication) fetchToken(ctx context.Context, conn driver.Connection) (string, error) {
+	if a.source != nil {
+		token, err := a.source.Token()
+		if err != nil {
+			return "", driver.WithStack(err)
+		}
+		return token, nil
+	}
+
 	// Prepare request
 	r, err := conn.NewRequest("POST", "/_open/auth")
 	if err != nil {
-		return driver.WithStack(err)
+		return "", driver.WithStack(err)
 	}
 	r.SetBody(jwtOpenRequest{
 		UserName: a.userName,
@@ -114,31 +196,126 @@ func (a *jwtAuthentication) Prepare(ctx context.Context, conn driver.Connection)
 	// Perform request
 	resp, err := conn.Do(ctx, r)
 	if err != nil {
-		return driver.WithStack(err)
+		return "", driver.WithStack(err)
 	}
 	if err := resp.CheckStatus(200); err != nil {
-		return driver.WithStack(err)
+		return "", driver.WithStack(err)
 	}
 
 	// Parse response
 	var data jwtOpenResponse
 	if err := resp.ParseBody("", &data); err != nil {
-		return driver.WithStack(err)
+		return "", driver.WithStack(err)
 	}
+	return data.Token, nil
+}
 
-	// Store token
-	a.token = data.Token
+// Configure is called for every request made on a connection.
+func (a *jwtAuthentication) Configure(req driver.Request) error {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	req.SetHeader("Authorization", "bearer "+a.token)
+	return nil
+}
 
-	// Ok
+// NeedsRenewal returns true when the token is about to expire.
+func (a *jwtAuthentication) NeedsRenewal() bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return !a.renewAt.IsZero() && !time.Now().Before(a.renewAt)
+}
+
+// newProvidedAuthentication creates an authentication implementation that obtains
+// its credentials from the given provider.
+func newProvidedAuthentication(provider driver.CredentialsProvider) httpAuthentication {
+	return &providedAuthentication{
+		provider: provider,
+	}
+}
+
+// providedAuthentication implements authentication with credentials obtained from a provider.
+// It wraps the authentication implementation for the current credentials and
+// replaces it when the provider returns different credentials.
+type providedAuthentication struct {
+	provider driver.CredentialsProvider
+	mutex    sync.RWMutex
+	current  driver.Authentication // Credentials used to create auth
+	auth     httpAuthentication
+}
+
+// Prepare is called before the first request of the given connection is made,
+// when the credentials have changed or must be renewed and when the credentials
+// have been rejected by the server.
+func (a *providedAuthentication) Prepare(ctx context.Context, conn driver.Connection) error {
+	a.mutex.RLock()
+	current, auth := a.current, a.auth
+	a.mutex.RUnlock()
+
+	creds, err := a.provider.Credentials(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if auth != nil && creds == current {
+		if renewable, ok := auth.(httpRenewableAuthentication); ok && renewable.NeedsRenewal() {
+			// Renew the credentials of the current authentication
+			if err := auth.Prepare(ctx, conn); err != nil {
+				return driver.WithStack(err)
+			}
+			return nil
+		}
+		// The credentials have not changed, so they were rejected by the server.
+		a.provider.Invalidate(creds)
+		if creds, err = a.provider.Credentials(ctx); err != nil {
+			return driver.WithStack(err)
+		}
+	}
+
+	// Create & prepare an authentication for the new credentials
+	newAuth, err := newHTTPAuthentication(creds)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if err := newAuth.Prepare(ctx, conn); err != nil {
+		return driver.WithStack(err)
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.current = creds
+	a.auth = newAuth
 	return nil
 }
 
 // Configure is called for every request made on a connection.
-func (a *jwtAuthentication) Configure(req driver.Request) error {
-	req.SetHeader("Authorization", "bearer "+a.token)
+func (a *providedAuthentication) Configure(req driver.Request) error {
+	a.mutex.RLock()
+	auth := a.auth
+	a.mutex.RUnlock()
+	if auth == nil {
+		return driver.WithStack(driver.InvalidArgumentError{Message: "authentication is not prepared"})
+	}
+	if err := auth.Configure(req); err != nil {
+		return driver.WithStack(err)
+	}
 	return nil
 }
 
+// NeedsRenewal returns true when the provider returns different credentials,
+// or the credentials of the current authentication must be renewed.
+func (a *providedAuthentication) NeedsRenewal() bool {
+	a.mutex.RLock()
+	current, auth := a.current, a.auth
+	a.mutex.RUnlock()
+	if renewable, ok := auth.(httpRenewableAuthentication); ok && renewable.NeedsRenewal() {
+		return true
+	}
+	creds, err := a.provider.Credentials(context.Background())
+	if err != nil {
+		// Let Prepare report the error
+		return true
+	}
+	return creds != current
+}
+
 // newAuthenticatedConnection creates a Connection that applies the given connection on the given underlying connection.
 func newAuthenticatedConnection(conn driver.Connection, auth httpAuthentication) (driver.Connection, error) {
 	if conn == nil {
@@ -155,6 +332,7 @@ func newAuthenticatedConnection(conn driver.Connection, auth httpAuthentication)
 
 // authenticatedConnection implements authentication behavior for connections.
 type authenticatedConnection struct {
+	generation   int64             // Incremented every time the authentication is prepared (first for 64-bit alignment)
 	conn         driver.Connection // Un-authenticated connection
 	auth         httpAuthentication
 	prepareMutex sync.Mutex
@@ -172,13 +350,35 @@ func (c *authenticatedConnection) NewRequest(method, path string) (driver.Reques
 
 // Do performs a given request, returning its response.
 func (c *authenticatedConnection) Do(ctx context.Context, req driver.Request) (driver.Response, error) {
-	if atomic.LoadInt32(&c.prepared) == 0 {
-		// Probably we're not yet prepared
+	if atomic.LoadInt32(&c.prepared) == 0 || c.needsRenewal() {
+		// Probably we're not yet prepared, or our credentials are about to expire
 		if err := c.prepare(ctx); err != nil {
 			// Authentication failed
 			return nil, driver.WithStack(err)
 		}
 	}
+	generation := atomic.LoadInt64(&c.generation)
+	resp, err := c.do(ctx, req)
+	// An unauthorized `text/plain` response is turned into an ArangoError by the connection.
+	unauthorized := driver.IsArangoErrorWithCode(err, 401) || (err == nil && resp.StatusCode() == 401)
+	if unauthorized {
+		if _, ok := c.auth.(httpRenewableAuthentication); ok {
+			// Our credentials may have expired unexpectedly.
+			// Renew them and try once more.
+			if err := c.renew(ctx, generation); err != nil {
+				return nil, driver.WithStack(err)
+			}
+			resp, err = c.do(ctx, req)
+		}
+	}
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	return resp, nil
+}
+
+// do configures the given request for authentication and performs it.
+func (c *authenticatedConnection) do(ctx context.Context, req driver.Request) (driver.Response, error) {
 	// Configure the request for authentication.
 	if err := c.auth.Configure(req); err != nil {
 		// Failed to configure request for authentication
@@ -231,16 +431,42 @@ func (c *authenticatedConnection) Protocols() driver.ProtocolSet {
 func (c *authenticatedConnection) prepare(ctx context.Context) error {
 	c.prepareMutex.Lock()
 	defer c.prepareMutex.Unlock()
-	if c.prepared == 0 {
+	if c.prepared == 0 || c.needsRenewal() {
 		// We need to prepare first
 		if err := c.auth.Prepare(ctx, c.conn); err != nil {
 			// Authentication failed
 			return driver.WithStack(err)
 		}
 		// We're now prepared
+		atomic.AddInt64(&c.generation, 1)
 		atomic.StoreInt32(&c.prepared, 1)
 	} else {
 		// We're already prepared, do nothing
 	}
 	return nil
 }
+
+// renew calls Authentication.Prepare again, unless that has already been done
+// since the given generation of the authentication was used.
+func (c *authenticatedConnection) renew(ctx context.Context, generation int64) error {
+	c.prepareMutex.Lock()
+	defer c.prepareMutex.Unlock()
+	if atomic.LoadInt64(&c.generation) == generation {
+		if err := c.auth.Prepare(ctx, c.conn); err != nil {
+			// Authentication failed
+			return driver.WithStack(err)
+		}
+		atomic.AddInt64(&c.generation, 1)
+	} else {
+		// Another request has already renewed the authentication, do nothing
+	}
+	return nil
+}
+
+// needsRenewal returns true when the authentication uses credentials that must be renewed.
+func (c *authenticatedConnection) needsRenewal() bool {
+	if auth, ok := c.auth.(httpRenewableAuthentication); ok {
+		return auth.NeedsRenewal()
+	}
+	return false
+}
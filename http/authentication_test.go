@@ -0,0 +1,302 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// tokenServerConnection is a fake connection that issues JWT tokens and
+// rejects requests that do not use the most recently issued token.
+type tokenServerConnection struct {
+	mutex      sync.Mutex
+	lifetime   time.Duration
+	issued     int
+	validToken string
+	requests   int
+	// textPlain makes rejected requests fail the way an unauthorized
+	// `text/plain` response does.
+	textPlain bool
+}
+
+func (c *tokenServerConnection) NewRequest(method, path string) (driver.Request, error) {
+	return &httpJSONRequest{method: method, path: path}, nil
+}
+
+func (c *tokenServerConnection) Do(ctx context.Context, req driver.Request) (driver.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	r := req.(*httpJSONRequest)
+	if r.path == "/_open/auth" {
+		c.issued++
+		payload := fmt.Sprintf(`{"exp":%d,"n":%d}`, time.Now().Add(c.lifetime).Unix(), c.issued)
+		c.validToken = "e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+		return newTestResponse(200, `{"jwt":"`+c.validToken+`"}`), nil
+	}
+	c.requests++
+	if r.hdr["Authorization"] != "bearer "+c.validToken {
+		if c.textPlain {
+			return nil, driver.WithStack(driver.ArangoError{HasError: true, Code: 401, ErrorMessage: "unauthorized"})
+		}
+		return newTestResponse(401, `{"error":true,"code":401}`), nil
+	}
+	return newTestResponse(200, `{}`), nil
+}
+
+// revoke invalidates the current token, without the client knowing.
+func (c *tokenServerConnection) revoke() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.validToken = "revoked"
+}
+
+func (c *tokenServerConnection) Unmarshal(data driver.RawObject, result interface{}) error {
+	return nil
+}
+func (c *tokenServerConnection) Endpoints() []string               { return nil }
+func (c *tokenServerConnection) UpdateEndpoints(ep []string) error { return nil }
+func (c *tokenServerConnection) Protocols() driver.ProtocolSet     { return nil }
+func (c *tokenServerConnection) SetAuthentication(driver.Authentication) (driver.Connection, error) {
+	return c, nil
+}
+
+func newTestResponse(statusCode int, body string) driver.Response {
+	return &httpJSONResponse{resp: &http.Response{StatusCode: statusCode}, rawResponse: []byte(body)}
+}
+
+func TestJWTAuthenticationRetryOn401(t *testing.T) {
+	server := &tokenServerConnection{lifetime: time.Hour}
+	conn, err := newAuthenticatedConnection(server, newJWTAuthentication("root", ""))
+	if err != nil {
+		t.Fatalf("newAuthenticatedConnection failed: %s", err)
+	}
+	do := func() int {
+		req, _ := conn.NewRequest("GET", "_api/version")
+		resp, err := conn.Do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+		return resp.StatusCode()
+	}
+	if code := do(); code != 200 {
+		t.Errorf("Expected 200, got %d", code)
+	}
+	server.revoke()
+	if code := do(); code != 200 {
+		t.Errorf("Expected 200 after re-authentication, got %d", code)
+	}
+	if server.issued != 2 {
+		t.Errorf("Expected 2 tokens to be issued, got %d", server.issued)
+	}
+	if server.requests != 3 {
+		t.Errorf("Expected 3 requests (including 1 retry), got %d", server.requests)
+	}
+}
+
+func TestJWTAuthenticationRetryOn401Error(t *testing.T) {
+	server := &tokenServerConnection{lifetime: time.Hour, textPlain: true}
+	conn, err := newAuthenticatedConnection(server, newJWTAuthentication("root", ""))
+	if err != nil {
+		t.Fatalf("newAuthenticatedConnection failed: %s", err)
+	}
+	do := func() error {
+		req, _ := conn.NewRequest("GET", "_api/version")
+		_, err := conn.Do(context.Background(), req)
+		return err
+	}
+	if err := do(); err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	server.revoke()
+	if err := do(); err != nil {
+		t.Errorf("Expected success after re-authentication, got %s", err)
+	}
+	if server.issued != 2 {
+		t.Errorf("Expected 2 tokens to be issued, got %d", server.issued)
+	}
+	if server.requests != 3 {
+		t.Errorf("Expected 3 requests (including 1 retry), got %d", server.requests)
+	}
+}
+
+func TestJWTAuthenticationRenewBeforeExpiry(t *testing.T) {
+	// Tokens expire within a second, so they are renewed after half a second.
+	server := &tokenServerConnection{lifetime: time.Second}
+	conn, err := newAuthenticatedConnection(server, newJWTAuthentication("root", ""))
+	if err != nil {
+		t.Fatalf("newAuthenticatedConnection failed: %s", err)
+	}
+	for i := 0; i < 2; i++ {
+		req, _ := conn.NewRequest("GET", "_api/version")
+		if _, err := conn.Do(context.Background(), req); err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+		if i == 0 {
+			time.Sleep(time.Second)
+		}
+	}
+	if server.issued != 2 {
+		t.Errorf("Expected 2 tokens to be issued, got %d", server.issued)
+	}
+	if server.requests != 2 {
+		t.Errorf("Expected 2 requests (no retries), got %d", server.requests)
+	}
+}
+
+func TestBasicAuthenticationNoRetryOn401(t *testing.T) {
+	server := &tokenServerConnection{}
+	conn, err := newAuthenticatedConnection(server, newBasicAuthentication("root", "wrong"))
+	if err != nil {
+		t.Fatalf("newAuthenticatedConnection failed: %s", err)
+	}
+	req, _ := conn.NewRequest("GET", "_api/version")
+	resp, err := conn.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 401 {
+		t.Errorf("Expected 401, got %d", resp.StatusCode())
+	}
+	if server.requests != 1 {
+		t.Errorf("Expected 1 request, got %d", server.requests)
+	}
+}
+
+func TestJWTSecretAuthenticationSignsLocally(t *testing.T) {
+	server := &tokenServerConnection{}
+	auth := driver.JWTSecretAuthentication("topsecret", &driver.JWTSecretOptions{UserName: "root"})
+	httpAuth := newJWTSecretAuthentication(auth.(driver.JWTTokenSource))
+	if err := httpAuth.Prepare(context.Background(), server); err != nil {
+		t.Fatalf("Prepare failed: %s", err)
+	}
+	if server.issued != 0 {
+		t.Errorf("Expected no token requests, got %d", server.issued)
+	}
+	req := &httpJSONRequest{}
+	if err := httpAuth.Configure(req); err != nil {
+		t.Fatalf("Configure failed: %s", err)
+	}
+	if value := req.hdr["Authorization"]; !strings.HasPrefix(value, "bearer ") || len(value) <= len("bearer ") {
+		t.Errorf("Expected bearer token, got '%s'", value)
+	}
+	if httpAuth.(httpRenewableAuthentication).NeedsRenewal() {
+		t.Error("Expected fresh token to not need renewal")
+	}
+}
+
+// testCredentialsProvider is a CredentialsProvider that returns a configurable authentication.
+type testCredentialsProvider struct {
+	mutex         sync.Mutex
+	auth          driver.Authentication
+	next          driver.Authentication // Returned after invalidation
+	invalidations int
+}
+
+func (p *testCredentialsProvider) Credentials(ctx context.Context) (driver.Authentication, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.auth, nil
+}
+
+func (p *testCredentialsProvider) Invalidate(auth driver.Authentication) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.invalidations++
+	if p.next != nil {
+		p.auth, p.next = p.next, nil
+	}
+}
+
+// passwordServerConnection is a fake connection that accepts only a single raw Authorization value.
+type passwordServerConnection struct {
+	tokenServerConnection
+	validValue string
+}
+
+func (c *passwordServerConnection) Do(ctx context.Context, req driver.Request) (driver.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.requests++
+	if req.(*httpJSONRequest).hdr["Authorization"] != c.validValue {
+		return newTestResponse(401, `{"error":true,"code":401}`), nil
+	}
+	return newTestResponse(200, `{}`), nil
+}
+
+func TestProvidedAuthenticationFollowsProvider(t *testing.T) {
+	server := &passwordServerConnection{validValue: "first"}
+	provider := &testCredentialsProvider{auth: driver.RawAuthentication("first")}
+	conn, err := newAuthenticatedConnection(server, newProvidedAuthentication(provider))
+	if err != nil {
+		t.Fatalf("newAuthenticatedConnection failed: %s", err)
+	}
+	ctx := context.Background()
+	do := func() int {
+		req, _ := conn.NewRequest("GET", "_api/version")
+		resp, err := conn.Do(ctx, req)
+		if err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+		return resp.StatusCode()
+	}
+	if status := do(); status != 200 {
+		t.Errorf("Expected 200, got %d", status)
+	}
+
+	// Rotate the credentials in the provider and on the server
+	provider.mutex.Lock()
+	provider.auth = driver.RawAuthentication("second")
+	provider.mutex.Unlock()
+	server.mutex.Lock()
+	server.validValue = "second"
+	server.mutex.Unlock()
+	if status := do(); status != 200 {
+		t.Errorf("Expected 200 after rotation, got %d", status)
+	}
+	if provider.invalidations != 0 {
+		t.Errorf("Expected no invalidations, got %d", provider.invalidations)
+	}
+
+	// Rotate on the server only; the provider learns of it on invalidation
+	server.mutex.Lock()
+	server.validValue = "third"
+	server.mutex.Unlock()
+	provider.mutex.Lock()
+	provider.next = driver.RawAuthentication("third")
+	provider.mutex.Unlock()
+	if status := do(); status != 200 {
+		t.Errorf("Expected 200 after invalidation, got %d", status)
+	}
+	if provider.invalidations != 1 {
+		t.Errorf("Expected 1 invalidation, got %d", provider.invalidations)
+	}
+}
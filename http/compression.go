@@ -0,0 +1,158 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// CompressionMethod specifies the algorithm used to compress request bodies.
+type CompressionMethod string
+
+const (
+	// CompressionNone sends request bodies uncompressed.
+	CompressionNone CompressionMethod = ""
+	// CompressionGzip compresses request bodies using gzip.
+	CompressionGzip CompressionMethod = "gzip"
+	// CompressionDeflate compresses request bodies using deflate (zlib format).
+	CompressionDeflate CompressionMethod = "deflate"
+
+	// DefaultCompressionThreshold is the minimum size (in bytes) of request bodies that are compressed,
+	// used when CompressionConfig.Threshold is 0.
+	DefaultCompressionThreshold = 1024
+
+	// acceptEncoding is the value of the Accept-Encoding header sent when compression is enabled.
+	acceptEncoding = "gzip, deflate"
+)
+
+// CompressionConfig configures the compression of request & response bodies.
+// When set, responses may be compressed by the server using gzip or deflate,
+// they are decompressed transparently.
+type CompressionConfig struct {
+	// Method used to compress request bodies.
+	// If CompressionNone, request bodies are not compressed.
+	Method CompressionMethod
+	// Threshold is the minimum size (in bytes) of a request body to compress it.
+	// If 0, DefaultCompressionThreshold is used.
+	Threshold int
+	// Level is the compression level (see compress/flate).
+	// If 0, the default compression level is used.
+	Level int
+}
+
+// validate checks the given configuration.
+func (c CompressionConfig) validate() error {
+	switch c.Method {
+	case CompressionNone, CompressionGzip, CompressionDeflate:
+		// Ok
+	default:
+		return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("Unsupported compression method '%s'", c.Method)})
+	}
+	if c.Level != 0 {
+		if _, err := gzip.NewWriterLevel(ioutil.Discard, c.Level); err != nil {
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("Invalid compression level %d", c.Level)})
+		}
+	}
+	return nil
+}
+
+// compressBody compresses the given request body according to the given configuration.
+// It returns the body to send and the content encoding of that body.
+// If config is nil or the body is smaller than the threshold, the body is returned unmodified
+// with an empty content encoding.
+func compressBody(body []byte, config *CompressionConfig) ([]byte, string, error) {
+	if config == nil || config.Method == CompressionNone {
+		return body, "", nil
+	}
+	threshold := config.Threshold
+	if threshold == 0 {
+		threshold = DefaultCompressionThreshold
+	}
+	if len(body) < threshold {
+		return body, "", nil
+	}
+	level := config.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	buf := &bytes.Buffer{}
+	var w io.WriteCloser
+	var err error
+	switch config.Method {
+	case CompressionGzip:
+		w, err = gzip.NewWriterLevel(buf, level)
+	case CompressionDeflate:
+		w, err = zlib.NewWriterLevel(buf, level)
+	}
+	if err != nil {
+		return nil, "", driver.WithStack(err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, "", driver.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", driver.WithStack(err)
+	}
+	return buf.Bytes(), string(config.Method), nil
+}
+
+// decompressBody decompresses the given body of the given response, according to
+// its Content-Encoding header.
+// Bodies with another (or no) content encoding are returned unmodified.
+func decompressBody(resp *http.Response, body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+	var r io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	default:
+		return body, nil
+	}
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	defer r.Close()
+	result, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return result, nil
+}
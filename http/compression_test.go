@@ -0,0 +1,142 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// newCompressionTestServer creates a server that decompresses request bodies,
+// and responds with the request body, compressed like the request or else using deflate
+// when accepted by the client.
+func newCompressionTestServer(t *testing.T, contentEncodings *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		encoding := r.Header.Get("Content-Encoding")
+		*contentEncodings = append(*contentEncodings, encoding)
+		switch encoding {
+		case "gzip":
+			body, _ = gzip.NewReader(r.Body)
+		case "deflate":
+			body, _ = zlib.NewReader(r.Body)
+		}
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			t.Errorf("Failed to read request body: %s", err)
+		}
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		buf := &bytes.Buffer{}
+		var cw io.WriteCloser
+		switch {
+		case encoding == "gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			cw = gzip.NewWriter(buf)
+		case strings.Contains(r.Header.Get("Accept-Encoding"), "deflate"):
+			w.Header().Set("Content-Encoding", "deflate")
+			cw = zlib.NewWriter(buf)
+		default:
+			cw = nopWriteCloser{buf}
+		}
+		cw.Write(data)
+		cw.Close()
+		w.Write(buf.Bytes())
+	}))
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// TestCompression checks compression of request bodies & decompression of responses
+// for JSON & Velocypack requests.
+func TestCompression(t *testing.T) {
+	largeValue := strings.Repeat("x", 2*DefaultCompressionThreshold)
+	tests := []struct {
+		Method      CompressionMethod
+		ContentType driver.ContentType
+		Value       string
+		Expected    string
+	}{
+		{CompressionGzip, driver.ContentTypeJSON, largeValue, "gzip"},
+		{CompressionDeflate, driver.ContentTypeJSON, largeValue, "deflate"},
+		{CompressionGzip, driver.ContentTypeVelocypack, largeValue, "gzip"},
+		{CompressionGzip, driver.ContentTypeJSON, "small", ""},
+		{CompressionNone, driver.ContentTypeJSON, largeValue, ""},
+	}
+	for _, test := range tests {
+		var encodings []string
+		srv := newCompressionTestServer(t, &encodings)
+		conn, err := newHTTPConnection(srv.URL, ConnectionConfig{
+			ContentType: test.ContentType,
+			Compression: &CompressionConfig{Method: test.Method},
+		})
+		if err != nil {
+			t.Fatalf("newHTTPConnection failed: %s", err)
+		}
+		req, err := conn.NewRequest("POST", "_api/echo")
+		if err != nil {
+			t.Fatalf("NewRequest failed: %s", err)
+		}
+		input := map[string]string{"value": test.Value}
+		if _, err := req.SetBody(input); err != nil {
+			t.Fatalf("SetBody failed: %s", err)
+		}
+		resp, err := conn.Do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+		var output map[string]string
+		if err := resp.ParseBody("", &output); err != nil {
+			t.Errorf("ParseBody failed for %s/%s: %s", test.Method, test.ContentType, err)
+		} else if output["value"] != test.Value {
+			t.Errorf("Expected echoed value of length %d, got %d", len(test.Value), len(output["value"]))
+		}
+		if len(encodings) != 1 || encodings[0] != test.Expected {
+			t.Errorf("Expected content encoding '%s', got %v", test.Expected, encodings)
+		}
+		srv.Close()
+	}
+}
+
+// TestCompressionInvalidMethod checks that an unsupported method is rejected.
+func TestCompressionInvalidMethod(t *testing.T) {
+	_, err := newHTTPConnection("http://localhost:8529", ConnectionConfig{
+		Compression: &CompressionConfig{Method: "brotli"},
+	})
+	if !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
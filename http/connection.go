@@ -48,12 +48,14 @@ const (
 
 	keyRawResponse driver.ContextKey = "arangodb-rawResponse"
 	keyResponse    driver.ContextKey = "arangodb-response"
+	keySpanEndInfo driver.ContextKey = "arangodb-spanEndInfo"
 )
 
 // ConnectionConfig provides all configuration options for a HTTP connection.
 type ConnectionConfig struct {
 	// Endpoints holds 1 or more URL's used to connect to the database.
 	// In case of a connection to an ArangoDB cluster, you must provide the URL's of all coordinators.
+	// Use `unix:///path/to/socket` or `http+unix:///path/to/socket` to connect to a unix domain socket.
 	Endpoints []string
 	// TLSConfig holds settings used to configure a TLS (HTTPS) connection.
 	// This is only used for endpoints using the HTTPS scheme.
@@ -68,6 +70,7 @@ type ConnectionConfig struct {
 	// A lower number will cause the golang runtime to create additional connections and close them
 	// directly after use, resulting in a large number of connections in `TIME_WAIT` state.
 	// When this value is not set, the driver will set it to 64 automatically.
+	// A custom Transport must dial the socket of unix domain socket endpoints itself.
 	Transport http.RoundTripper
 	// DontFollowRedirect; if set, redirect will not be followed, response from the initial request will be returned without an error
 	// DontFollowRedirect takes precendance over FailOnRedirect.
@@ -80,8 +83,23 @@ type ConnectionConfig struct {
 	ContentType driver.ContentType
 	// ConnLimit is the upper limit to the number of connections to a single server.
 	// The default is 32 (DefaultConnLimit).
+	// When HTTP2 is set, it is the upper limit to the number of concurrent streams to a single server
+	// and the default is 100 (DefaultHTTP2ConnLimit).
 	// Set this value to -1 if you do not want any upper limit.
 	ConnLimit int
+	// HTTP2 enables the use of HTTP/2, multiplexing concurrent requests to a server over a few connections.
+	// For `https` endpoints HTTP/2 is negotiated using TLS ALPN, for `http` endpoints cleartext
+	// HTTP/2 (h2c) is used with prior knowledge.
+	// The servers must support HTTP/2, there is no fallback to HTTP/1.1.
+	// HTTP2 is ignored when Transport is set.
+	HTTP2 bool
+	// Tracer is called around every request sent to a server.
+	// If nil, requests are not traced, but a traceparent set with driver.WithTraceParent is still sent.
+	Tracer driver.Tracer
+	// Compression configures the compression of request & response bodies.
+	// If nil, request bodies are not compressed and only gzip compressed responses
+	// are accepted (if supported by Transport).
+	Compression *CompressionConfig
 }
 
 // NewConnection creates a new HTTP connection based on the given configuration settings.
@@ -102,30 +120,63 @@ func NewConnection(config ConnectionConfig) (driver.Connection, error) {
 // newHTTPConnection creates a new HTTP connection for a single endpoint and the remainder of the given configuration settings.
 func newHTTPConnection(endpoint string, config ConnectionConfig) (driver.Connection, error) {
 	if config.ConnLimit == 0 {
-		config.ConnLimit = DefaultConnLimit
+		if config.HTTP2 {
+			config.ConnLimit = DefaultHTTP2ConnLimit
+		} else {
+			config.ConnLimit = DefaultConnLimit
+		}
+	}
+	var compression *CompressionConfig
+	if config.Compression != nil {
+		if err := config.Compression.validate(); err != nil {
+			return nil, driver.WithStack(err)
+		}
+		cc := *config.Compression
+		compression = &cc
 	}
 	endpoint = util.FixupEndpointURLScheme(endpoint)
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, driver.WithStack(err)
 	}
+	requestURL := *u
+	isUnix := util.IsUnixEndpoint(endpoint)
+	if isUnix {
+		// Requests are sent over the unix domain socket, the host of the request URL
+		// is only used for the Host header.
+		requestURL = url.URL{Scheme: "http", Host: "localhost"}
+	}
+	if config.HTTP2 && config.Transport == nil {
+		config.Transport, err = newHTTP2Transport(*u, config.TLSConfig)
+		if err != nil {
+			return nil, driver.WithStack(err)
+		}
+	}
 	var httpTransport *http.Transport
 	if config.Transport != nil {
 		httpTransport, _ = config.Transport.(*http.Transport)
 	} else {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}
 		httpTransport = &http.Transport{
 			// Copy default values from http.DefaultTransport
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
 			MaxIdleConns:          100,
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
 		}
+		if isUnix {
+			socketPath := u.Path
+			httpTransport.Proxy = nil
+			httpTransport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socketPath)
+			}
+		}
 		config.Transport = httpTransport
 	}
 	if httpTransport != nil {
@@ -173,9 +224,13 @@ func newHTTPConnection(endpoint string, config ConnectionConfig) (driver.Connect
 	}
 	c := &httpConnection{
 		endpoint:    *u,
+		requestURL:  requestURL,
 		contentType: config.ContentType,
 		client:      httpClient,
 		connPool:    connPool,
+		tracer:      config.Tracer,
+		metrics:     config.Metrics,
+		compression: compression,
 	}
 	return c, nil
 }
@@ -183,9 +238,13 @@ func newHTTPConnection(endpoint string, config ConnectionConfig) (driver.Connect
 // httpConnection implements an HTTP + JSON connection to an arangodb server.
 type httpConnection struct {
 	endpoint    url.URL
+	requestURL  url.URL // Base URL of requests, differs from endpoint for unix domain sockets
 	contentType driver.ContentType
 	client      *http.Client
 	connPool    chan int
+	tracer      driver.Tracer
+	metrics     driver.Metrics
+	compression *CompressionConfig
 }
 
 // String returns the endpoint as string
@@ -226,11 +285,71 @@ func (c *httpConnection) NewRequest(method, path string) (driver.Request, error)
 
 // Do performs a given request, returning its response.
 func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c.tracer == nil && c.metrics == nil {
+		if v := driver.TraceParentFromContext(ctx); v != "" {
+			req.SetHeader(driver.TraceParentHeader, v)
+		}
+		return c.do(ctx, req)
+	}
+
+	// Trace the request and/or collect its metrics
+	endpoint := c.endpoint.String()
+	var span driver.Span
+	if c.tracer != nil {
+		ctx, span = c.tracer.StartSpan(ctx, driver.SpanStartInfo{
+			Method:   req.Method(),
+			Path:     req.Path(),
+			Endpoint: endpoint,
+		})
+	}
+	traceParent := ""
+	if span != nil {
+		traceParent = span.TraceParent()
+	}
+	if traceParent == "" {
+		traceParent = driver.TraceParentFromContext(ctx)
+	}
+	if traceParent != "" {
+		req.SetHeader(driver.TraceParentHeader, traceParent)
+	}
+	var start time.Time
+	if c.metrics != nil {
+		c.metrics.RequestStarted(endpoint)
+		start = time.Now()
+	}
+	var info driver.SpanEndInfo
+	resp, err := c.do(context.WithValue(ctx, keySpanEndInfo, &info), req)
+	if resp != nil {
+		info.StatusCode = resp.StatusCode()
+	}
+	info.Err = err
+	if span != nil {
+		span.End(info)
+	}
+	if c.metrics != nil {
+		c.metrics.RequestDone(driver.RequestMetrics{
+			Endpoint:      endpoint,
+			Operation:     driver.RequestOperation(req.Method(), req.Path()),
+			Duration:      time.Since(start),
+			StatusCode:    info.StatusCode,
+			BytesSent:     info.RequestBytes,
+			BytesReceived: info.ResponseBytes,
+			Err:           err,
+		})
+	}
+	return resp, err
+}
+
+// do performs a given request, returning its response.
+func (c *httpConnection) do(ctx context.Context, req driver.Request) (driver.Response, error) {
 	httpReq, ok := req.(httpRequest)
 	if !ok {
 		return nil, driver.WithStack(driver.InvalidArgumentError{Message: "request is not a httpRequest"})
 	}
-	r, err := httpReq.createHTTPRequest(c.endpoint)
+	r, err := httpReq.createHTTPRequest(c.requestURL, c.compression)
 	rctx := ctx
 	if rctx == nil {
 		rctx = context.Background()
@@ -240,13 +359,22 @@ func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Res
 			httpReq.WroteRequest(info)
 		},
 	})
-	r = r.WithContext(rctx)
 	if err != nil {
 		return nil, driver.WithStack(err)
 	}
+	r = r.WithContext(rctx)
+	if c.compression != nil {
+		// Setting Accept-Encoding disables the transparent gzip decompression of the transport,
+		// so the response body is decompressed by readBody.
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+	}
 
 	// Block on too many concurrent connections
 	if c.connPool != nil {
+		var waitStart time.Time
+		if c.metrics != nil {
+			waitStart = time.Now()
+		}
 		select {
 		case t := <-c.connPool:
 			// Ok, we're allowed to continue
@@ -254,6 +382,9 @@ func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Res
 				// Give back token
 				c.connPool <- t
 			}()
+			if c.metrics != nil {
+				c.metrics.ConnectionWaited(c.endpoint.String(), time.Since(waitStart))
+			}
 		case <-rctx.Done():
 			// Context cancelled or expired
 			return nil, driver.WithStack(rctx.Err())
@@ -278,6 +409,12 @@ func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Res
 	if err != nil {
 		return nil, driver.WithStack(err)
 	}
+	if info, ok := ctx.Value(keySpanEndInfo).(*driver.SpanEndInfo); ok {
+		if r.ContentLength > 0 {
+			info.RequestBytes = r.ContentLength
+		}
+		info.ResponseBytes = int64(len(body))
+	}
 	if rawResponse != nil {
 		*rawResponse = body
 	}
@@ -286,9 +423,9 @@ func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Res
 	var httpResp driver.Response
 	switch strings.Split(ct, ";")[0] {
 	case "application/json", "application/x-arango-dump":
-		httpResp = &httpJSONResponse{resp: resp, rawResponse: body}
+		httpResp = &httpJSONResponse{resp: resp, rawResponse: body, endpoint: c.endpoint.String()}
 	case "application/x-velocypack":
-		httpResp = &httpVPackResponse{resp: resp, rawResponse: body}
+		httpResp = &httpVPackResponse{resp: resp, rawResponse: body, endpoint: c.endpoint.String()}
 	default:
 		if resp.StatusCode == http.StatusUnauthorized {
 			// When unauthorized the server sometimes return a `text/plain` response.
@@ -304,7 +441,7 @@ func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Res
 			if rawResponse != nil {
 				*rawResponse = body
 			}
-			httpResp = &httpJSONResponse{resp: resp, rawResponse: body}
+			httpResp = &httpJSONResponse{resp: resp, rawResponse: body, endpoint: c.endpoint.String()}
 		} else {
 			return nil, driver.WithStack(fmt.Errorf("Unsupported content type '%s' with status %d and content '%s'", ct, resp.StatusCode, string(body)))
 		}
@@ -319,8 +456,22 @@ func (c *httpConnection) Do(ctx context.Context, req driver.Request) (driver.Res
 	return httpResp, nil
 }
 
-// readBody reads the body of the given response into a byte slice.
+// readBody reads the body of the given response into a byte slice,
+// decompressing it when it has a gzip or deflate content encoding.
 func readBody(resp *http.Response) ([]byte, error) {
+	body, err := readRawBody(resp)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	result, err := decompressBody(resp, body)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	return result, nil
+}
+
+// readRawBody reads the body of the given response into a byte slice.
+func readRawBody(resp *http.Response) ([]byte, error) {
 	defer resp.Body.Close()
 	contentLength := resp.ContentLength
 	if contentLength < 0 {
@@ -382,21 +533,9 @@ func (c *httpConnection) UpdateEndpoints(endpoints []string) error {
 
 // Configure the authentication used for this connection.
 func (c *httpConnection) SetAuthentication(auth driver.Authentication) (driver.Connection, error) {
-	var httpAuth httpAuthentication
-	switch auth.Type() {
-	case driver.AuthenticationTypeBasic:
-		userName := auth.Get("username")
-		password := auth.Get("password")
-		httpAuth = newBasicAuthentication(userName, password)
-	case driver.AuthenticationTypeJWT:
-		userName := auth.Get("username")
-		password := auth.Get("password")
-		httpAuth = newJWTAuthentication(userName, password)
-	case driver.AuthenticationTypeRaw:
-		value := auth.Get("value")
-		httpAuth = newRawAuthentication(value)
-	default:
-		return nil, driver.WithStack(fmt.Errorf("Unsupported authentication type %d", int(auth.Type())))
+	httpAuth, err := newHTTPAuthentication(auth)
+	if err != nil {
+		return nil, driver.WithStack(err)
 	}
 
 	result, err := newAuthenticatedConnection(c, httpAuth)
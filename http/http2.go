@@ -0,0 +1,89 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// DefaultHTTP2ConnLimit is the default maximum number of concurrent streams
+	// to a single server when HTTP/2 is used.
+	DefaultHTTP2ConnLimit = 100
+)
+
+// newHTTP2Transport creates a transport that sends all requests to the given endpoint using HTTP/2.
+// For `https` endpoints, HTTP/2 is negotiated using TLS ALPN.
+// For `http` & unix domain socket endpoints, cleartext HTTP/2 (h2c) is used with prior knowledge,
+// so the server must accept HTTP/2 without an upgrade from HTTP/1.1.
+func newHTTP2Transport(endpoint url.URL, tlsConfig *tls.Config) (http.RoundTripper, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	switch strings.ToLower(endpoint.Scheme) {
+	case "http":
+		return &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				// Cleartext HTTP/2, ignore the TLS configuration
+				return dialer.Dial(network, addr)
+			},
+		}, nil
+	case "https":
+		return &http2.Transport{
+			TLSClientConfig: tlsConfig,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				conn, err := tls.DialWithDialer(dialer, network, addr, cfg)
+				if err != nil {
+					return nil, driver.WithStack(err)
+				}
+				if p := conn.ConnectionState().NegotiatedProtocol; p != http2.NextProtoTLS {
+					conn.Close()
+					return nil, driver.WithStack(fmt.Errorf("Server at %s does not support HTTP/2 (negotiated protocol '%s')", addr, p))
+				}
+				return conn, nil
+			},
+		}, nil
+	case "unix", "http+unix":
+		socketPath := endpoint.Path
+		return &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				// Cleartext HTTP/2 over the unix domain socket, ignore the TLS configuration
+				return dialer.Dial("unix", socketPath)
+			},
+		}, nil
+	default:
+		return nil, driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("Unsupported scheme '%s' for HTTP/2", endpoint.Scheme)})
+	}
+}
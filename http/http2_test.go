@@ -0,0 +1,157 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// http2TestHandler responds with the HTTP version of the request and tracks
+// the maximum number of concurrent requests.
+type http2TestHandler struct {
+	current, max int32
+}
+
+func (h *http2TestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt32(&h.current, 1)
+	defer atomic.AddInt32(&h.current, -1)
+	for {
+		max := atomic.LoadInt32(&h.max)
+		if n <= max || atomic.CompareAndSwapInt32(&h.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"proto":"` + r.Proto + `"}`))
+}
+
+// newHTTP2TLSServer starts a server that supports HTTP/2 using TLS ALPN.
+func newHTTP2TLSServer(t *testing.T, handler http.Handler) (*httptest.Server, *tls.Config) {
+	srv := httptest.NewUnstartedServer(handler)
+	if err := http2.ConfigureServer(srv.Config, nil); err != nil {
+		t.Fatalf("ConfigureServer failed: %s", err)
+	}
+	srv.TLS = srv.Config.TLSConfig
+	srv.StartTLS()
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	return srv, &tls.Config{RootCAs: pool}
+}
+
+// checkHTTP2Proto sends a request using the given connection & checks that it used HTTP/2.
+func checkHTTP2Proto(t *testing.T, conn driver.Connection) {
+	req, err := conn.NewRequest("GET", "_api/version")
+	if err != nil {
+		t.Fatalf("NewRequest failed: %s", err)
+	}
+	resp, err := conn.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	var result struct {
+		Proto string `json:"proto"`
+	}
+	if err := resp.ParseBody("", &result); err != nil {
+		t.Fatalf("ParseBody failed: %s", err)
+	}
+	if result.Proto != "HTTP/2.0" {
+		t.Errorf("Expected HTTP/2.0, got '%s'", result.Proto)
+	}
+}
+
+// TestHTTP2TLS checks HTTP/2 negotiated using TLS ALPN.
+func TestHTTP2TLS(t *testing.T) {
+	srv, tlsConfig := newHTTP2TLSServer(t, &http2TestHandler{})
+	defer srv.Close()
+	conn, err := newHTTPConnection(srv.URL, ConnectionConfig{HTTP2: true, TLSConfig: tlsConfig})
+	if err != nil {
+		t.Fatalf("newHTTPConnection failed: %s", err)
+	}
+	checkHTTP2Proto(t, conn)
+}
+
+// TestHTTP2TLSNotSupported checks that a server without HTTP/2 support results in an error.
+func TestHTTP2TLSNotSupported(t *testing.T) {
+	srv := httptest.NewTLSServer(&http2TestHandler{})
+	defer srv.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	conn, err := newHTTPConnection(srv.URL, ConnectionConfig{HTTP2: true, TLSConfig: &tls.Config{RootCAs: pool}})
+	if err != nil {
+		t.Fatalf("newHTTPConnection failed: %s", err)
+	}
+	req, err := conn.NewRequest("GET", "_api/version")
+	if err != nil {
+		t.Fatalf("NewRequest failed: %s", err)
+	}
+	if _, err := conn.Do(context.Background(), req); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestHTTP2Cleartext checks cleartext HTTP/2 with prior knowledge.
+func TestHTTP2Cleartext(t *testing.T) {
+	srv := httptest.NewServer(h2c.NewHandler(&http2TestHandler{}, &http2.Server{}))
+	defer srv.Close()
+	conn, err := newHTTPConnection(srv.URL, ConnectionConfig{HTTP2: true})
+	if err != nil {
+		t.Fatalf("newHTTPConnection failed: %s", err)
+	}
+	checkHTTP2Proto(t, conn)
+}
+
+// TestHTTP2ConnLimit checks that ConnLimit limits the number of concurrent streams.
+func TestHTTP2ConnLimit(t *testing.T) {
+	handler := &http2TestHandler{}
+	srv, tlsConfig := newHTTP2TLSServer(t, handler)
+	defer srv.Close()
+	conn, err := newHTTPConnection(srv.URL, ConnectionConfig{HTTP2: true, TLSConfig: tlsConfig, ConnLimit: 3})
+	if err != nil {
+		t.Fatalf("newHTTPConnection failed: %s", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkHTTP2Proto(t, conn)
+		}()
+	}
+	wg.Wait()
+	if max := atomic.LoadInt32(&handler.max); max > 3 || max < 2 {
+		t.Errorf("Expected at most 3 concurrent streams, got %d", max)
+	}
+}
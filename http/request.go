@@ -31,7 +31,8 @@ import (
 // httpRequest implements driver.Request using standard golang http requests.
 type httpRequest interface {
 	// createHTTPRequest creates a golang http.Request based on the configured arguments.
-	createHTTPRequest(endpoint url.URL) (*http.Request, error)
+	// The body is compressed according to the given compression configuration (if any).
+	createHTTPRequest(endpoint url.URL, compression *CompressionConfig) (*http.Request, error)
 	// WroteRequest implements the WroteRequest function of an httptrace.
 	// It sets written to true.
 	WroteRequest(httptrace.WroteRequestInfo)
@@ -45,8 +45,13 @@ type httpJSONRequest struct {
 	hdr     map[string]string
 	body    []byte
 	written bool
+	// contentType of the body, if set using SetRawBody
+	contentType string
 }
 
+// Ensure the request supports a raw body
+var _ driver.RawBodyRequest = &httpJSONRequest{}
+
 // Clone creates a new request containing the same data as this request
 func (r *httpJSONRequest) Clone() driver.Request {
 	clone := *r
@@ -177,6 +182,14 @@ func isNil(v reflect.Value) bool {
 	}
 }
 
+// SetRawBody sets the content of the request to the given bytes, sent as-is with the given content type
+// (e.g. "application/zip"). No marshalling is taking place.
+func (r *httpJSONRequest) SetRawBody(body []byte, contentType string) (driver.Request, error) {
+	r.body = body
+	r.contentType = contentType
+	return r, nil
+}
+
 // SetHeader sets a single header arguments of the request.
 // Any existing header argument with the same key is overwritten.
 func (r *httpJSONRequest) SetHeader(key, value string) driver.Request {
@@ -187,6 +200,16 @@ func (r *httpJSONRequest) SetHeader(key, value string) driver.Request {
 	return r
 }
 
+// Method returns the method of the request (GET, POST, ...).
+func (r *httpJSONRequest) Method() string {
+	return r.method
+}
+
+// Path returns the path of the request, relative to the endpoint.
+func (r *httpJSONRequest) Path() string {
+	return r.path
+}
+
 // Written returns true as soon as this request has been written completely to the network.
 // This does not guarantee that the server has received or processed the request.
 func (r *httpJSONRequest) Written() bool {
@@ -200,7 +223,8 @@ func (r *httpJSONRequest) WroteRequest(httptrace.WroteRequestInfo) {
 }
 
 // createHTTPRequest creates a golang http.Request based on the configured arguments.
-func (r *httpJSONRequest) createHTTPRequest(endpoint url.URL) (*http.Request, error) {
+// The body is compressed according to the given compression configuration (if any).
+func (r *httpJSONRequest) createHTTPRequest(endpoint url.URL, compression *CompressionConfig) (*http.Request, error) {
 	r.written = false
 	u := endpoint
 	u.Path = ""
@@ -220,8 +244,16 @@ func (r *httpJSONRequest) createHTTPRequest(endpoint url.URL) (*http.Request, er
 		}
 	}
 	var body io.Reader
+	var contentEncoding string
+	var contentLength int
 	if r.body != nil {
-		body = bytes.NewReader(r.body)
+		data, encoding, err := compressBody(r.body, compression)
+		if err != nil {
+			return nil, driver.WithStack(err)
+		}
+		body = bytes.NewReader(data)
+		contentEncoding = encoding
+		contentLength = len(data)
 	}
 	req, err := http.NewRequest(r.method, url, body)
 	if err != nil {
@@ -235,8 +267,15 @@ func (r *httpJSONRequest) createHTTPRequest(endpoint url.URL) (*http.Request, er
 	}
 
 	if r.body != nil {
-		req.Header.Set("Content-Length", strconv.Itoa(len(r.body)))
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Length", strconv.Itoa(contentLength))
+		if contentEncoding != "" {
+			req.Header.Set("Content-Encoding", contentEncoding)
+		}
+		if r.contentType != "" {
+			req.Header.Set("Content-Type", r.contentType)
+		} else {
+			req.Header.Set("Content-Type", "application/json")
+		}
 	}
 	return req, nil
 }
@@ -23,6 +23,7 @@
 package http
 
 import (
+	"net/url"
 	"strings"
 	"testing"
 )
@@ -123,3 +124,20 @@ func TestSetBodyImportArrayMaps(t *testing.T) {
 		t.Errorf("Encoding failed: Expected\n%s\nGot\n%s\n", expected, data)
 	}
 }
+
+func TestSetRawBody(t *testing.T) {
+	r := &httpJSONRequest{method: "POST", path: "_api/foxx"}
+	if _, err := r.SetRawBody([]byte("PK\x03\x04"), "application/zip"); err != nil {
+		t.Fatalf("SetRawBody failed: %v", err)
+	}
+	req, err := r.createHTTPRequest(url.URL{Scheme: "http", Host: "localhost:8529"}, nil)
+	if err != nil {
+		t.Fatalf("createHTTPRequest failed: %v", err)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Expected Content-Type 'application/zip', got '%s'", ct)
+	}
+	if cl := req.Header.Get("Content-Length"); cl != "4" {
+		t.Errorf("Expected Content-Length '4', got '%s'", cl)
+	}
+}
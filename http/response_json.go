@@ -36,6 +36,7 @@ import (
 type httpJSONResponse struct {
 	resp        *http.Response
 	rawResponse []byte
+	endpoint    string
 	bodyObject  map[string]*json.RawMessage
 	bodyArray   []map[string]*json.RawMessage
 }
@@ -47,9 +48,7 @@ func (r *httpJSONResponse) StatusCode() int {
 
 // Endpoint returns the endpoint that handled the request.
 func (r *httpJSONResponse) Endpoint() string {
-	u := *r.resp.Request.URL
-	u.Path = ""
-	return u.String()
+	return r.endpoint
 }
 
 // CheckStatus checks if the status of the response equals to one of the given status codes.
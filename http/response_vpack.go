@@ -34,6 +34,7 @@ import (
 type httpVPackResponse struct {
 	resp        *http.Response
 	rawResponse []byte
+	endpoint    string
 	slice       velocypack.Slice
 	bodyArray   []driver.Response
 }
@@ -45,9 +46,7 @@ func (r *httpVPackResponse) StatusCode() int {
 
 // Endpoint returns the endpoint that handled the request.
 func (r *httpVPackResponse) Endpoint() string {
-	u := *r.resp.Request.URL
-	u.Path = ""
-	return u.String()
+	return r.endpoint
 }
 
 // CheckStatus checks if the status of the response equals to one of the given status codes.
@@ -0,0 +1,103 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// newUnixTestServer starts a server with given handler that listens on a unix domain socket.
+// It returns the server, the path of the socket and a function to stop the server.
+func newUnixTestServer(t *testing.T, handler http.Handler) (*httptest.Server, string, func()) {
+	dir, err := ioutil.TempDir("", "go-driver-unix")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	socketPath := filepath.Join(dir, "arangod.sock")
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Listen failed: %s", err)
+	}
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = l
+	srv.Start()
+	return srv, socketPath, func() {
+		srv.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// TestUnixEndpoints checks requests to unix domain socket endpoints.
+func TestUnixEndpoints(t *testing.T) {
+	_, socketPath, cleanup := newUnixTestServer(t, &http2TestHandler{})
+	defer cleanup()
+
+	for _, endpoint := range []string{"unix://" + socketPath, "http+unix://" + socketPath} {
+		conn, err := NewConnection(ConnectionConfig{Endpoints: []string{endpoint}})
+		if err != nil {
+			t.Fatalf("NewConnection failed: %s", err)
+		}
+		if eps := conn.Endpoints(); len(eps) != 1 || eps[0] != endpoint {
+			t.Errorf("Expected endpoints [%s], got %v", endpoint, eps)
+		}
+		req, err := conn.NewRequest("GET", "_api/version")
+		if err != nil {
+			t.Fatalf("NewRequest failed: %s", err)
+		}
+		// Pin the request to the endpoint, to check the endpoint identity
+		resp, err := conn.Do(driver.WithEndpoint(context.Background(), endpoint), req)
+		if err != nil {
+			t.Fatalf("Do failed for %s: %s", endpoint, err)
+		}
+		if resp.StatusCode() != 200 {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode())
+		}
+		if ep := resp.Endpoint(); ep != endpoint {
+			t.Errorf("Expected response endpoint %s, got %s", endpoint, ep)
+		}
+	}
+}
+
+// TestUnixEndpointHTTP2 checks cleartext HTTP/2 over a unix domain socket.
+func TestUnixEndpointHTTP2(t *testing.T) {
+	_, socketPath, cleanup := newUnixTestServer(t, h2c.NewHandler(&http2TestHandler{}, &http2.Server{}))
+	defer cleanup()
+
+	conn, err := newHTTPConnection("unix://"+socketPath, ConnectionConfig{HTTP2: true})
+	if err != nil {
+		t.Fatalf("newHTTPConnection failed: %s", err)
+	}
+	checkHTTP2Proto(t, conn)
+}
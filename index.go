@@ -35,6 +35,7 @@ const (
 	SkipListIndex   = IndexType("skiplist")
 	PersistentIndex = IndexType("persistent")
 	GeoIndex        = IndexType("geo")
+	EdgeIndex       = IndexType("edge")
 )
 
 // Index provides access to a single index in a single collection.
@@ -45,6 +46,27 @@ type Index interface {
 	// Type returns the type of the index
 	Type() IndexType
 
+	// Fields returns the attribute paths covered by the index.
+	Fields() []string
+
+	// Unique returns true if the index has a uniqueness constraint.
+	Unique() bool
+
+	// Sparse returns true if the index excludes documents that do not have the indexed attributes.
+	Sparse() bool
+
+	// Deduplicate returns true if array values are de-duplicated before being added to the index.
+	// Only relevant for hash & skiplist indexes.
+	Deduplicate() bool
+
+	// MinLength returns the minimum character length of words to index.
+	// Only relevant for fulltext indexes.
+	MinLength() int
+
+	// GeoJSON returns true if coordinates are in GeoJSON order (longitude first).
+	// Only relevant for geo indexes.
+	GeoJSON() bool
+
 	// Remove removes the entire index.
 	// If the index does not exist, a NotFoundError is returned.
 	Remove(ctx context.Context) error
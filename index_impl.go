@@ -43,6 +43,8 @@ func indexStringToType(indexTypeString string) (IndexType, error) {
 		return PersistentIndex, nil
 	case string(GeoIndex), "geo1", "geo2":
 		return GeoIndex, nil
+	case string(EdgeIndex):
+		return EdgeIndex, nil
 
 	default:
 		return "", WithStack(InvalidArgumentError{Message: "unknown index type"})
@@ -50,7 +52,8 @@ func indexStringToType(indexTypeString string) (IndexType, error) {
 }
 
 // newIndex creates a new Index implementation.
-func newIndex(id string, indexTypeString string, col *collection) (Index, error) {
+func newIndex(data indexData, col *collection) (Index, error) {
+	id := data.ID
 	if id == "" {
 		return nil, WithStack(InvalidArgumentError{Message: "id is empty"})
 	}
@@ -61,25 +64,37 @@ func newIndex(id string, indexTypeString string, col *collection) (Index, error)
 	if col == nil {
 		return nil, WithStack(InvalidArgumentError{Message: "col is nil"})
 	}
-	indexType, err := indexStringToType(indexTypeString)
+	indexType, err := indexStringToType(data.Type)
 	if err != nil {
 		return nil, WithStack(err)
 	}
 	return &index{
-		id:        id,
-		indexType: indexType,
-		col:       col,
-		db:        col.db,
-		conn:      col.conn,
+		id:          id,
+		indexType:   indexType,
+		fields:      data.Fields,
+		unique:      data.Unique != nil && *data.Unique,
+		sparse:      data.Sparse != nil && *data.Sparse,
+		deduplicate: data.Deduplicate == nil || *data.Deduplicate,
+		minLength:   data.MinLength,
+		geoJSON:     data.GeoJSON != nil && *data.GeoJSON,
+		col:         col,
+		db:          col.db,
+		conn:        col.conn,
 	}, nil
 }
 
 type index struct {
-	id        string
-	indexType IndexType
-	db        *database
-	col       *collection
-	conn      Connection
+	id          string
+	indexType   IndexType
+	fields      []string
+	unique      bool
+	sparse      bool
+	deduplicate bool
+	minLength   int
+	geoJSON     bool
+	db          *database
+	col         *collection
+	conn        Connection
 }
 
 // relPath creates the relative path to this index (`_db/<db-name>/_api/index`)
@@ -98,6 +113,36 @@ func (i *index) Type() IndexType {
 	return i.indexType
 }
 
+// Fields returns the attribute paths covered by the index.
+func (i *index) Fields() []string {
+	return i.fields
+}
+
+// Unique returns true if the index has a uniqueness constraint.
+func (i *index) Unique() bool {
+	return i.unique
+}
+
+// Sparse returns true if the index excludes documents that do not have the indexed attributes.
+func (i *index) Sparse() bool {
+	return i.sparse
+}
+
+// Deduplicate returns true if array values are de-duplicated before being added to the index.
+func (i *index) Deduplicate() bool {
+	return i.deduplicate
+}
+
+// MinLength returns the minimum character length of words to index.
+func (i *index) MinLength() int {
+	return i.minLength
+}
+
+// GeoJSON returns true if coordinates are in GeoJSON order (longitude first).
+func (i *index) GeoJSON() bool {
+	return i.geoJSON
+}
+
 // Remove removes the entire index.
 // If the index does not exist, a NotFoundError is returned.
 func (i *index) Remove(ctx context.Context) error {
@@ -0,0 +1,76 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"strings"
+	"time"
+)
+
+// Metrics is called by connections to collect metrics of requests.
+// Implementations must be safe for concurrent use.
+// See the metrics package for an implementation that can be exposed to Prometheus.
+type Metrics interface {
+	// RequestStarted is called when a request to the given endpoint is started.
+	RequestStarted(endpoint string)
+	// RequestDone is called when a request started with RequestStarted has finished.
+	RequestDone(info RequestMetrics)
+	// ConnectionWaited is called with the time a request had to wait before a connection
+	// to the given endpoint was available (see http.ConnectionConfig.ConnLimit).
+	ConnectionWaited(endpoint string, wait time.Duration)
+	// Failover is called when a cluster connection sends a request to another endpoint,
+	// after sending it to the `from` endpoint failed.
+	Failover(from, to string)
+	// Retry is called when a cluster connection retries a request that failed on the given endpoint.
+	Retry(endpoint string)
+}
+
+// RequestMetrics holds information about a finished request.
+type RequestMetrics struct {
+	// Endpoint of the server the request was sent to
+	Endpoint string
+	// Operation identifies the kind of request (see RequestOperation)
+	Operation string
+	// Duration of the request, including the time waiting for a connection
+	Duration time.Duration
+	// StatusCode of the response (0 if no response was received)
+	StatusCode int
+	// BytesSent is the size of the request body
+	BytesSent int64
+	// BytesReceived is the size of the response body
+	BytesReceived int64
+	// Err is the error returned by the request, if any
+	Err error
+}
+
+// RequestOperation returns a name for the kind of request with given method & path,
+// that is suitable as metric label.
+// It consists of the method and the first 2 segments of the path, excluding the database,
+// e.g. "GET _api/document".
+func RequestOperation(method, path string) string {
+	parts := strings.SplitN(trimRequestPath(path), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return method + " " + strings.Join(parts, "/")
+}
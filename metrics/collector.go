@@ -0,0 +1,311 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultNamespace is the prefix of all metric names, used when CollectorOptions.Namespace is empty.
+	DefaultNamespace = "arangodb_driver"
+	// ContentType is the content type of the Prometheus text exposition format.
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	// DefaultBuckets are the upper bounds (in seconds) of the histogram buckets,
+	// used when CollectorOptions.Buckets is empty.
+	DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+)
+
+// CollectorOptions holds the options of a Collector.
+type CollectorOptions struct {
+	// Namespace is the prefix of all metric names.
+	// If empty, DefaultNamespace is used.
+	Namespace string
+	// Buckets are the (sorted) upper bounds in seconds of the buckets of all histograms.
+	// If empty, DefaultBuckets are used.
+	Buckets []float64
+}
+
+// Collector collects the metrics of requests and writes them in the Prometheus text exposition format.
+// It implements driver.Metrics & http.Handler.
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mutex         sync.Mutex
+	durations     map[labelValues]*histogram // endpoint, operation
+	inFlight      map[labelValues]float64    // endpoint
+	errors        map[labelValues]float64    // endpoint, kind
+	waits         map[labelValues]*histogram // endpoint
+	failovers     map[labelValues]float64    // from, to
+	retries       map[labelValues]float64    // endpoint
+	bytesSent     map[labelValues]float64    // endpoint
+	bytesReceived map[labelValues]float64    // endpoint
+}
+
+// labelValues holds the values of up to 2 labels of a metric.
+type labelValues [2]string
+
+// NewCollector creates a new Collector with given options.
+// If options is nil, default options are used.
+func NewCollector(options *CollectorOptions) *Collector {
+	c := &Collector{
+		namespace:     DefaultNamespace,
+		buckets:       DefaultBuckets,
+		durations:     make(map[labelValues]*histogram),
+		inFlight:      make(map[labelValues]float64),
+		errors:        make(map[labelValues]float64),
+		waits:         make(map[labelValues]*histogram),
+		failovers:     make(map[labelValues]float64),
+		retries:       make(map[labelValues]float64),
+		bytesSent:     make(map[labelValues]float64),
+		bytesReceived: make(map[labelValues]float64),
+	}
+	if options != nil {
+		if options.Namespace != "" {
+			c.namespace = options.Namespace
+		}
+		if len(options.Buckets) > 0 {
+			c.buckets = append([]float64(nil), options.Buckets...)
+			sort.Float64s(c.buckets)
+		}
+	}
+	return c
+}
+
+// RequestStarted is called when a request to the given endpoint is started.
+func (c *Collector) RequestStarted(endpoint string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inFlight[labelValues{endpoint}]++
+}
+
+// RequestDone is called when a request started with RequestStarted has finished.
+func (c *Collector) RequestDone(info driver.RequestMetrics) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	endpoint := labelValues{info.Endpoint}
+	c.inFlight[endpoint]--
+	c.observe(c.durations, labelValues{info.Endpoint, info.Operation}, info.Duration)
+	if info.Err != nil {
+		c.errors[labelValues{info.Endpoint, "transport"}]++
+	} else if info.StatusCode >= 500 {
+		c.errors[labelValues{info.Endpoint, "server"}]++
+	}
+	c.bytesSent[endpoint] += float64(info.BytesSent)
+	c.bytesReceived[endpoint] += float64(info.BytesReceived)
+}
+
+// ConnectionWaited is called with the time a request had to wait before a connection
+// to the given endpoint was available.
+func (c *Collector) ConnectionWaited(endpoint string, wait time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.observe(c.waits, labelValues{endpoint}, wait)
+}
+
+// Failover is called when a request is sent to another endpoint after it failed on the `from` endpoint.
+func (c *Collector) Failover(from, to string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.failovers[labelValues{from, to}]++
+}
+
+// Retry is called when a request that failed on the given endpoint is retried.
+func (c *Collector) Retry(endpoint string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.retries[labelValues{endpoint}]++
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	c.WritePrometheus(w)
+}
+
+// WritePrometheus writes all metrics to the given writer in the Prometheus text exposition format.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bw := bufio.NewWriter(w)
+	c.writeHistograms(bw, "request_duration_seconds", "Latency of requests sent to a server.",
+		[]string{"endpoint", "operation"}, c.durations)
+	c.writeValues(bw, "requests_in_flight", "gauge", "Number of requests in progress.",
+		[]string{"endpoint"}, c.inFlight)
+	c.writeValues(bw, "request_errors_total", "counter", "Number of requests that failed to get a response (transport) or got a 5xx response (server).",
+		[]string{"endpoint", "kind"}, c.errors)
+	c.writeHistograms(bw, "connection_wait_seconds", "Time spent waiting for a connection to a server.",
+		[]string{"endpoint"}, c.waits)
+	c.writeValues(bw, "failovers_total", "counter", "Number of times a request was sent to another server after it failed.",
+		[]string{"from", "to"}, c.failovers)
+	c.writeValues(bw, "retries_total", "counter", "Number of times a failed request was retried.",
+		[]string{"endpoint"}, c.retries)
+	c.writeValues(bw, "sent_bytes_total", "counter", "Number of request body bytes sent.",
+		[]string{"endpoint"}, c.bytesSent)
+	c.writeValues(bw, "received_bytes_total", "counter", "Number of response body bytes received.",
+		[]string{"endpoint"}, c.bytesReceived)
+	if err := bw.Flush(); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// observe adds the given duration to the histogram with given label values.
+// The mutex must be held.
+func (c *Collector) observe(histograms map[labelValues]*histogram, values labelValues, d time.Duration) {
+	h, found := histograms[values]
+	if !found {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		histograms[values] = h
+	}
+	v := d.Seconds()
+	for i, upper := range c.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// writeHeader writes the HELP & TYPE lines of a metric.
+func (c *Collector) writeHeader(w *bufio.Writer, name, metricType, help string) {
+	w.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	w.WriteString("# TYPE " + name + " " + metricType + "\n")
+}
+
+// writeValues writes a counter or gauge metric.
+func (c *Collector) writeValues(w *bufio.Writer, name, metricType, help string, labels []string, values map[labelValues]float64) {
+	if len(values) == 0 {
+		return
+	}
+	name = c.namespace + "_" + name
+	c.writeHeader(w, name, metricType, help)
+	for _, key := range sortedKeys(values) {
+		writeSample(w, name, formatLabels(labels, key, "", ""), values[key])
+	}
+}
+
+// writeHistograms writes a histogram metric.
+func (c *Collector) writeHistograms(w *bufio.Writer, name, help string, labels []string, histograms map[labelValues]*histogram) {
+	if len(histograms) == 0 {
+		return
+	}
+	name = c.namespace + "_" + name
+	c.writeHeader(w, name, "histogram", help)
+	keys := make([]labelValues, 0, len(histograms))
+	for key := range histograms {
+		keys = append(keys, key)
+	}
+	sortLabelValues(keys)
+	for _, key := range keys {
+		h := histograms[key]
+		for i, upper := range c.buckets {
+			writeSample(w, name+"_bucket", formatLabels(labels, key, "le", formatFloat(upper)), float64(h.counts[i]))
+		}
+		writeSample(w, name+"_bucket", formatLabels(labels, key, "le", "+Inf"), float64(h.count))
+		writeSample(w, name+"_sum", formatLabels(labels, key, "", ""), h.sum)
+		writeSample(w, name+"_count", formatLabels(labels, key, "", ""), float64(h.count))
+	}
+}
+
+// histogram holds the cumulative bucket counts, total count & sum of observed values.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// writeSample writes a single sample line.
+func writeSample(w *bufio.Writer, name, labels string, value float64) {
+	w.WriteString(name + labels + " " + formatFloat(value) + "\n")
+}
+
+// formatLabels formats the given label names & values, followed by an optional extra label.
+func formatLabels(names []string, values labelValues, extraName, extraValue string) string {
+	parts := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		parts = append(parts, name+"=\""+escapeLabelValue(values[i])+"\"")
+	}
+	if extraName != "" {
+		parts = append(parts, extraName+"=\""+escapeLabelValue(extraValue)+"\"")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// formatFloat formats a sample value.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+	labelValueEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\"", "\\\"")
+)
+
+// escapeHelp escapes the given help text.
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+// escapeLabelValue escapes the given label value.
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+// sortedKeys returns the keys of the given map, sorted.
+func sortedKeys(values map[labelValues]float64) []labelValues {
+	keys := make([]labelValues, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sortLabelValues(keys)
+	return keys
+}
+
+// sortLabelValues sorts the given label values.
+func sortLabelValues(keys []labelValues) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+}
@@ -0,0 +1,133 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/cluster"
+	driverhttp "github.com/arangodb/go-driver/http"
+	"github.com/arangodb/go-driver/metrics"
+)
+
+// TestWritePrometheus checks the exposition format written by a collector.
+func TestWritePrometheus(t *testing.T) {
+	c := metrics.NewCollector(&metrics.CollectorOptions{Buckets: []float64{0.1, 1}})
+	c.RequestStarted("http://a")
+	c.RequestStarted("http://a")
+	c.RequestDone(driver.RequestMetrics{
+		Endpoint:      "http://a",
+		Operation:     "GET _api/version",
+		Duration:      500 * time.Millisecond,
+		StatusCode:    200,
+		BytesSent:     10,
+		BytesReceived: 20,
+	})
+	c.RequestStarted("http://b")
+	c.RequestDone(driver.RequestMetrics{Endpoint: "http://b", Operation: "GET _api/version", Err: errors.New("boom")})
+	c.Failover("http://b", "http://a")
+
+	var buf bytes.Buffer
+	if err := c.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus failed: %s", err)
+	}
+	output := buf.String()
+	expected := []string{
+		"# TYPE arangodb_driver_request_duration_seconds histogram\n",
+		`arangodb_driver_request_duration_seconds_bucket{endpoint="http://a",operation="GET _api/version",le="0.1"} 0` + "\n",
+		`arangodb_driver_request_duration_seconds_bucket{endpoint="http://a",operation="GET _api/version",le="1"} 1` + "\n",
+		`arangodb_driver_request_duration_seconds_bucket{endpoint="http://a",operation="GET _api/version",le="+Inf"} 1` + "\n",
+		`arangodb_driver_request_duration_seconds_sum{endpoint="http://a",operation="GET _api/version"} 0.5` + "\n",
+		`arangodb_driver_request_duration_seconds_count{endpoint="http://a",operation="GET _api/version"} 1` + "\n",
+		"# TYPE arangodb_driver_requests_in_flight gauge\n",
+		`arangodb_driver_requests_in_flight{endpoint="http://a"} 1` + "\n",
+		`arangodb_driver_requests_in_flight{endpoint="http://b"} 0` + "\n",
+		`arangodb_driver_request_errors_total{endpoint="http://b",kind="transport"} 1` + "\n",
+		`arangodb_driver_failovers_total{from="http://b",to="http://a"} 1` + "\n",
+		`arangodb_driver_sent_bytes_total{endpoint="http://a"} 10` + "\n",
+		`arangodb_driver_received_bytes_total{endpoint="http://a"} 20` + "\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+	if strings.Contains(output, "retries_total") {
+		t.Errorf("Expected no retries metric, got:\n%s", output)
+	}
+}
+
+// TestCollectorFedByConnection checks that connections feed the collector.
+func TestCollectorFedByConnection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"server":"arango","version":"3.4.0"}`))
+	}))
+	defer srv.Close()
+
+	collector := metrics.NewCollector(nil)
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		// The first endpoint refuses connections, so the request fails over to the test server.
+		Endpoints: []string{"http://127.0.0.1:1", srv.URL},
+		ConnectionConfig: cluster.ConnectionConfig{
+			Metrics: collector,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	req, err := conn.NewRequest("GET", "_db/mydb/_api/version/details")
+	if err != nil {
+		t.Fatalf("NewRequest failed: %s", err)
+	}
+	if _, err := conn.Do(context.Background(), req); err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("Expected content type %s, got %s", metrics.ContentType, ct)
+	}
+	output := rec.Body.String()
+	expected := []string{
+		`arangodb_driver_request_duration_seconds_count{endpoint="` + srv.URL + `",operation="GET _api/version"} 1`,
+		`arangodb_driver_request_errors_total{endpoint="http://127.0.0.1:1",kind="transport"} 1`,
+		`arangodb_driver_failovers_total{from="http://127.0.0.1:1",to="` + srv.URL + `"} 1`,
+		`arangodb_driver_connection_wait_seconds_count{endpoint="` + srv.URL + `"} 1`,
+		`arangodb_driver_requests_in_flight{endpoint="` + srv.URL + `"} 0`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+}
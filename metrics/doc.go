@@ -0,0 +1,51 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+/*
+Package metrics provides a driver.Metrics collector that can be exposed
+in the Prometheus text exposition format.
+
+	collector := metrics.NewCollector(nil)
+	conn, err := http.NewConnection(http.ConnectionConfig{
+		Endpoints: []string{"http://localhost:8529"},
+		ConnectionConfig: cluster.ConnectionConfig{
+			Metrics: collector,
+		},
+	})
+	...
+	http.Handle("/metrics", collector)
+
+The collector exposes the following metrics:
+
+	arangodb_driver_request_duration_seconds     Histogram of request latency, by endpoint & operation.
+	arangodb_driver_requests_in_flight           Number of requests in progress, by endpoint.
+	arangodb_driver_request_errors_total         Number of failed requests, by endpoint & kind.
+	arangodb_driver_connection_wait_seconds      Histogram of time spent waiting for a connection, by endpoint.
+	arangodb_driver_failovers_total              Number of failovers, by endpoint failed over from & to.
+	arangodb_driver_retries_total                Number of retries, by endpoint of the failed attempt.
+	arangodb_driver_sent_bytes_total             Number of request body bytes sent, by endpoint.
+	arangodb_driver_received_bytes_total         Number of response body bytes received, by endpoint.
+
+A request error is of kind "transport" when no response was received
+and of kind "server" when the server responded with a 5xx status code.
+*/
+package metrics
@@ -0,0 +1,135 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"time"
+)
+
+// Handler performs a request, returning its response.
+type Handler func(ctx context.Context, req Request) (Response, error)
+
+// Middleware wraps a Handler to add behavior before and/or after a request is performed,
+// such as logging, metrics or adding headers.
+type Middleware func(next Handler) Handler
+
+// WrapConnection creates a Connection that passes all requests through the given middlewares
+// before they are performed by the given connection.
+// The first middleware is the outermost one, so it sees a request first and its response last.
+// The middlewares are preserved when the authentication of the connection is changed.
+func WrapConnection(conn Connection, middlewares ...Middleware) Connection {
+	handler := Handler(conn.Do)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return &middlewareConnection{
+		conn:        conn,
+		middlewares: middlewares,
+		handler:     handler,
+	}
+}
+
+// LoggingMiddleware creates a Middleware that logs every request using the given function
+// (e.g. log.Printf), including the status code (or error) and duration.
+func LoggingMiddleware(logf func(format string, args ...interface{})) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			duration := time.Since(start)
+			if err != nil {
+				logf("%s %s failed after %s: %v", req.Method(), req.Path(), duration, err)
+			} else {
+				logf("%s %s -> %d from %s in %s", req.Method(), req.Path(), resp.StatusCode(), resp.Endpoint(), duration)
+			}
+			return resp, err
+		}
+	}
+}
+
+// HeaderMiddleware creates a Middleware that sets the given headers on every request.
+func HeaderMiddleware(headers map[string]string) Middleware {
+	h := make(map[string]string, len(headers))
+	for k, v := range headers {
+		h[k] = v
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (Response, error) {
+			for k, v := range h {
+				req.SetHeader(k, v)
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// DefaultRequestIDHeader is the header used by RequestIDMiddleware when no header is specified.
+const DefaultRequestIDHeader = "x-request-id"
+
+// RequestIDMiddleware creates a Middleware that sets a header containing a unique ID
+// on every request, so requests can be correlated with server logs.
+// If header is empty, DefaultRequestIDHeader is used.
+// If generate is nil, random 128-bit hexadecimal IDs are used.
+func RequestIDMiddleware(header string, generate func() string) Middleware {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	if generate == nil {
+		generate = newRequestID
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (Response, error) {
+			req.SetHeader(header, generate())
+			return next(ctx, req)
+		}
+	}
+}
+
+// TimeoutMiddleware creates a Middleware that applies a timeout to requests made with a
+// context without a deadline.
+// The timeout is looked up in the given map by the path of the request, after removing a
+// leading slash and the database part (`_db/<name>/`), e.g. `_api/cursor`.
+// The longest matching path prefix wins. If no prefix matches, defaultTimeout is used.
+// A timeout of zero means no timeout.
+func TimeoutMiddleware(defaultTimeout time.Duration, timeouts map[string]time.Duration) Middleware {
+	t := make(map[string]time.Duration, len(timeouts))
+	for k, v := range timeouts {
+		t[trimRequestPath(k)] = v
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (Response, error) {
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+				if timeout := lookupTimeout(t, req.Path(), defaultTimeout); timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, timeout)
+					defer cancel()
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
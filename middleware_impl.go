@@ -0,0 +1,127 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// middlewareConnection implements a Connection that passes requests through middlewares.
+type middlewareConnection struct {
+	conn        Connection
+	middlewares []Middleware
+	handler     Handler
+}
+
+// NewRequest creates a new request with given method and path.
+func (c *middlewareConnection) NewRequest(method, path string) (Request, error) {
+	r, err := c.conn.NewRequest(method, path)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return r, nil
+}
+
+// Do performs a given request, returning its response.
+func (c *middlewareConnection) Do(ctx context.Context, req Request) (Response, error) {
+	resp, err := c.handler(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return resp, nil
+}
+
+// Unmarshal unmarshals the given raw object into the given result interface.
+func (c *middlewareConnection) Unmarshal(data RawObject, result interface{}) error {
+	if err := c.conn.Unmarshal(data, result); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// Endpoints returns the endpoints used by this connection.
+func (c *middlewareConnection) Endpoints() []string {
+	return c.conn.Endpoints()
+}
+
+// UpdateEndpoints reconfigures the connection to use the given endpoints.
+func (c *middlewareConnection) UpdateEndpoints(endpoints []string) error {
+	if err := c.conn.UpdateEndpoints(endpoints); err != nil {
+		return WithStack(err)
+	}
+	return nil
+}
+
+// Configure the authentication used for this connection.
+// The returned connection uses the same middlewares.
+func (c *middlewareConnection) SetAuthentication(auth Authentication) (Connection, error) {
+	result, err := c.conn.SetAuthentication(auth)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	return WrapConnection(result, c.middlewares...), nil
+}
+
+// Protocols returns all protocols used by this connection.
+func (c *middlewareConnection) Protocols() ProtocolSet {
+	return c.conn.Protocols()
+}
+
+// newRequestID returns a random 128-bit hexadecimal ID.
+func newRequestID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// trimRequestPath removes a leading slash and the database part from the given request path.
+func trimRequestPath(p string) string {
+	p = strings.TrimPrefix(p, "/")
+	if strings.HasPrefix(p, "_db/") {
+		if i := strings.Index(p[4:], "/"); i >= 0 {
+			p = p[4+i+1:]
+		} else {
+			p = ""
+		}
+	}
+	return p
+}
+
+// lookupTimeout returns the timeout for the given request path, using the longest
+// matching prefix in the given timeouts.
+func lookupTimeout(timeouts map[string]time.Duration, path string, defaultTimeout time.Duration) time.Duration {
+	path = trimRequestPath(path)
+	result, longest := defaultTimeout, -1
+	for prefix, timeout := range timeouts {
+		if len(prefix) > longest && strings.HasPrefix(path, prefix) {
+			result, longest = timeout, len(prefix)
+		}
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+// headerEchoServer is a test server that records the headers & deadlines of requests.
+type headerEchoServer struct {
+	*httptest.Server
+	headers []http.Header
+}
+
+func newHeaderEchoServer() *headerEchoServer {
+	s := &headerEchoServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.headers = append(s.headers, r.Header)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version":"3.7.0"}`))
+	}))
+	return s
+}
+
+func TestWrapConnection(t *testing.T) {
+	server := newHeaderEchoServer()
+	defer server.Close()
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{Endpoints: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+
+	var calls []string
+	trace := func(name string) driver.Middleware {
+		return func(next driver.Handler) driver.Handler {
+			return func(ctx context.Context, req driver.Request) (driver.Response, error) {
+				calls = append(calls, name+">")
+				resp, err := next(ctx, req)
+				calls = append(calls, "<"+name)
+				return resp, err
+			}
+		}
+	}
+	var logged []string
+	logf := func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) }
+	wrapped := driver.WrapConnection(conn,
+		trace("a"), trace("b"),
+		driver.HeaderMiddleware(map[string]string{"x-tenant": "acme"}),
+		driver.RequestIDMiddleware("", func() string { return "req-1" }),
+		driver.LoggingMiddleware(logf))
+
+	// Middlewares must survive SetAuthentication
+	wrapped, err = wrapped.SetAuthentication(driver.BasicAuthentication("root", ""))
+	if err != nil {
+		t.Fatalf("SetAuthentication failed: %s", err)
+	}
+	req, _ := wrapped.NewRequest("GET", "/_api/version")
+	resp, err := wrapped.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 200 {
+		t.Errorf("Expected 200, got %d", resp.StatusCode())
+	}
+	if strings.Join(calls, " ") != "a> b> <b <a" {
+		t.Errorf("Unexpected middleware order: %v", calls)
+	}
+	if len(server.headers) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(server.headers))
+	}
+	h := server.headers[0]
+	if h.Get("x-tenant") != "acme" || h.Get(driver.DefaultRequestIDHeader) != "req-1" || !strings.HasPrefix(h.Get("Authorization"), "Basic ") {
+		t.Errorf("Unexpected headers: %v", h)
+	}
+	if len(logged) != 1 || !strings.HasPrefix(logged[0], "GET /_api/version -> 200") {
+		t.Errorf("Unexpected log output: %v", logged)
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	var deadlines []time.Duration
+	record := func(next driver.Handler) driver.Handler {
+		return func(ctx context.Context, req driver.Request) (driver.Response, error) {
+			if deadline, ok := ctx.Deadline(); ok {
+				deadlines = append(deadlines, time.Until(deadline).Round(time.Second))
+			} else {
+				deadlines = append(deadlines, 0)
+			}
+			return next(ctx, req)
+		}
+	}
+	server := newHeaderEchoServer()
+	defer server.Close()
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{Endpoints: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	wrapped := driver.WrapConnection(conn, driver.TimeoutMiddleware(time.Second*10, map[string]time.Duration{
+		"_api/cursor":   time.Minute,
+		"/_api/version": 0,
+	}), record)
+
+	do := func(ctx context.Context, path string) {
+		req, _ := wrapped.NewRequest("GET", path)
+		if _, err := wrapped.Do(ctx, req); err != nil {
+			t.Fatalf("Do failed: %s", err)
+		}
+	}
+	do(context.Background(), "_db/mydb/_api/cursor/123")
+	do(context.Background(), "/_api/version")
+	do(context.Background(), "_db/mydb/_api/document/col/key")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	do(ctx, "_db/mydb/_api/cursor")
+
+	expected := []time.Duration{time.Minute, 0, time.Second * 10, time.Second * 3}
+	if fmt.Sprint(deadlines) != fmt.Sprint(expected) {
+		t.Errorf("Expected timeouts %v, got %v", expected, deadlines)
+	}
+}
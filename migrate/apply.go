@@ -0,0 +1,245 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// PlanConflictError is returned when trying to apply a plan that contains conflicts.
+type PlanConflictError struct {
+	Conflicts []string
+}
+
+// Error implements the error interface.
+func (e PlanConflictError) Error() string {
+	return fmt.Sprintf("plan contains conflicts: %s", strings.Join(e.Conflicts, "; "))
+}
+
+// IsPlanConflict returns true if the given error is caused by a PlanConflictError.
+func IsPlanConflict(err error) bool {
+	_, ok := driver.Cause(err).(PlanConflictError)
+	return ok
+}
+
+// Apply executes all steps of the given plan on the given database.
+// Steps that turn out to be done already (e.g. because another process created
+// the same collection in the mean time) are silently skipped.
+// If the plan contains conflicts, a PlanConflictError is returned and nothing is changed.
+func Apply(ctx context.Context, db driver.Database, plan Plan) error {
+	if len(plan.Conflicts) > 0 {
+		return driver.WithStack(PlanConflictError{Conflicts: plan.Conflicts})
+	}
+	for _, s := range plan.Steps {
+		if err := applyStep(ctx, db, s); err != nil {
+			return driver.WithStack(fmt.Errorf("failed to %s: %v", s, err))
+		}
+	}
+	return nil
+}
+
+// Sync brings the given database in line with the given schema.
+// It returns the plan that has been applied.
+func Sync(ctx context.Context, db driver.Database, schema Schema) (Plan, error) {
+	plan, err := BuildPlan(ctx, db, schema)
+	if err != nil {
+		return Plan{}, driver.WithStack(err)
+	}
+	if err := Apply(ctx, db, plan); err != nil {
+		return plan, driver.WithStack(err)
+	}
+	return plan, nil
+}
+
+// applyStep executes a single step of a plan.
+func applyStep(ctx context.Context, db driver.Database, s Step) error {
+	switch s.Kind {
+	case StepCreateCollection:
+		c := s.createCollection
+		options := &driver.CreateCollectionOptions{
+			NumberOfShards:    c.NumberOfShards,
+			ShardKeys:         c.ShardKeys,
+			ReplicationFactor: c.ReplicationFactor,
+			IsSystem:          c.IsSystem,
+		}
+		if c.WaitForSync != nil {
+			options.WaitForSync = *c.WaitForSync
+		}
+		if c.collectionType() == CollectionTypeEdge {
+			options.Type = driver.CollectionTypeEdge
+		}
+		if ko := c.KeyOptions; ko != nil {
+			options.KeyOptions = &driver.CollectionKeyOptions{
+				Type:          ko.Type,
+				AllowUserKeys: ko.AllowUserKeys,
+				Increment:     ko.Increment,
+				Offset:        ko.Offset,
+			}
+		}
+		if _, err := db.CreateCollection(ctx, s.Collection, options); err != nil && !driver.IsConflict(err) {
+			return driver.WithStack(err)
+		}
+	case StepUpdateCollection:
+		col, err := db.Collection(ctx, s.Collection)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if err := col.SetProperties(ctx, *s.properties); err != nil {
+			return driver.WithStack(err)
+		}
+	case StepEnsureIndex:
+		col, err := db.Collection(ctx, s.Collection)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if err := ensureIndex(ctx, col, *s.index); err != nil {
+			return driver.WithStack(err)
+		}
+	case StepCreateGraph:
+		g := s.createGraph
+		options := &driver.CreateGraphOptions{
+			EdgeDefinitions:         g.EdgeDefinitions,
+			OrphanVertexCollections: g.OrphanCollections,
+			IsSmart:                 g.IsSmart,
+			SmartGraphAttribute:     g.SmartGraphAttribute,
+			NumberOfShards:          g.NumberOfShards,
+		}
+		if _, err := db.CreateGraph(ctx, s.Graph, options); err != nil && !driver.IsConflict(err) {
+			return driver.WithStack(err)
+		}
+	case StepCreateEdgeDefinition:
+		g, err := db.Graph(ctx, s.Graph)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		constraints := driver.VertexConstraints{From: s.edgeDefinition.From, To: s.edgeDefinition.To}
+		if _, err := g.CreateEdgeCollection(ctx, s.Collection, constraints); err != nil {
+			return driver.WithStack(err)
+		}
+	case StepSetVertexConstraints:
+		g, err := db.Graph(ctx, s.Graph)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		constraints := driver.VertexConstraints{From: s.edgeDefinition.From, To: s.edgeDefinition.To}
+		if err := g.SetVertexConstraints(ctx, s.Collection, constraints); err != nil {
+			return driver.WithStack(err)
+		}
+	case StepCreateVertexCollection:
+		g, err := db.Graph(ctx, s.Graph)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if _, err := g.CreateVertexCollection(ctx, s.Collection); err != nil && !driver.IsConflict(err) {
+			return driver.WithStack(err)
+		}
+	case StepCreateView:
+		options := &driver.ArangoSearchViewProperties{Links: viewLinks(s.viewLinks)}
+		if _, err := db.CreateArangoSearchView(ctx, s.View, options); err != nil && !driver.IsConflict(err) {
+			return driver.WithStack(err)
+		}
+	case StepUpdateViewLinks:
+		v, err := db.View(ctx, s.View)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		asv, err := v.ArangoSearchView()
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		// SetProperties replaces all properties, so start from the current ones
+		// to keep the settings & links that are not in the schema.
+		props, err := asv.Properties(ctx)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if props.Links == nil {
+			props.Links = make(driver.ArangoSearchLinks)
+		}
+		for name, l := range viewLinks(s.viewLinks) {
+			props.Links[name] = l
+		}
+		// The primary sort cannot be changed
+		props.PrimarySort = nil
+		if err := asv.SetProperties(ctx, props); err != nil {
+			return driver.WithStack(err)
+		}
+	default:
+		return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("unknown step kind '%s'", s.Kind)})
+	}
+	return nil
+}
+
+// ensureIndex creates the index described by the given specification in the given collection.
+func ensureIndex(ctx context.Context, col driver.Collection, spec IndexSchema) error {
+	var err error
+	switch spec.Type {
+	case driver.HashIndex:
+		_, _, err = col.EnsureHashIndex(ctx, spec.Fields, &driver.EnsureHashIndexOptions{
+			Unique:        spec.Unique,
+			Sparse:        spec.Sparse,
+			NoDeduplicate: spec.NoDeduplicate,
+		})
+	case driver.SkipListIndex:
+		_, _, err = col.EnsureSkipListIndex(ctx, spec.Fields, &driver.EnsureSkipListIndexOptions{
+			Unique:        spec.Unique,
+			Sparse:        spec.Sparse,
+			NoDeduplicate: spec.NoDeduplicate,
+		})
+	case driver.PersistentIndex:
+		_, _, err = col.EnsurePersistentIndex(ctx, spec.Fields, &driver.EnsurePersistentIndexOptions{
+			Unique: spec.Unique,
+			Sparse: spec.Sparse,
+		})
+	case driver.GeoIndex:
+		_, _, err = col.EnsureGeoIndex(ctx, spec.Fields, &driver.EnsureGeoIndexOptions{
+			GeoJSON: spec.GeoJSON,
+		})
+	case driver.FullTextIndex:
+		_, _, err = col.EnsureFullTextIndex(ctx, spec.Fields, &driver.EnsureFullTextIndexOptions{
+			MinLength: spec.MinLength,
+		})
+	default:
+		return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("unsupported index type '%s'", spec.Type)})
+	}
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// viewLinks converts the given links of a view schema into ArangoSearch links.
+func viewLinks(links map[string]ViewLinkSchema) driver.ArangoSearchLinks {
+	if len(links) == 0 {
+		return nil
+	}
+	result := make(driver.ArangoSearchLinks, len(links))
+	for name, l := range links {
+		result[name] = l.properties()
+	}
+	return result
+}
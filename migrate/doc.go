@@ -0,0 +1,45 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+/*
+Package migrate provides declarative schema management for an ArangoDB database.
+
+A desired schema (collections, indexes, named graphs & ArangoSearch views) is declared using Go structures,
+or loaded from a JSON or YAML file. The schema is compared with the live database,
+resulting in a Plan that describes the changes needed to bring the database in line
+with the schema. Applying a plan is idempotent.
+
+	schema, err := migrate.LoadFile("schema.yaml")
+	...
+	plan, err := migrate.BuildPlan(ctx, db, schema)
+	fmt.Println(plan)
+	err = migrate.Apply(ctx, db, plan)
+
+Applications that need to run versioned data migrations next to the schema
+can use a Migrator. It records applied migration versions in a collection
+(`_migrations` by default) and guards the whole process with a lock stored in
+that same collection, so multiple replicas of a service can be started at the
+same time without racing each other.
+
+Note that this package never removes collections, indexes, graphs, views or links of views.
+*/
+package migrate
@@ -0,0 +1,217 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	lockKey    = "lock"
+	minLockTTL = time.Second * 5
+)
+
+var (
+	// AlreadyLockedError indicates that the migration lock is held by another process.
+	AlreadyLockedError = errors.New("migrations are locked by another process")
+	// NotLockedError indicates that the migration lock is not held by this process.
+	NotLockedError = errors.New("migrations are not locked by this process")
+)
+
+// lockDocument is the document used to store the migration lock.
+type lockDocument struct {
+	Key     string    `json:"_key"`
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// lock is an exclusive lock, backed by a document in a collection.
+// The lock expires when it is not renewed within its TTL, so a crashed
+// process cannot block migrations forever.
+// Note that expiration is checked against the local clock of the processes
+// that try to acquire the lock.
+type lock struct {
+	mutex         sync.Mutex
+	col           driver.Collection
+	owner         string
+	ttl           time.Duration
+	rev           string
+	locked        bool
+	cancelRenewal func()
+}
+
+// newLock creates a new lock stored in the given collection.
+func newLock(col driver.Collection, owner string, ttl time.Duration) *lock {
+	if ttl < minLockTTL {
+		ttl = minLockTTL
+	}
+	return &lock{
+		col:   col,
+		owner: owner,
+		ttl:   ttl,
+	}
+}
+
+// Lock tries to lock the lock.
+// If the lock is held by another process, AlreadyLockedError is returned.
+func (l *lock) Lock(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.locked {
+		return driver.WithStack(AlreadyLockedError)
+	}
+
+	doc := lockDocument{
+		Key:     lockKey,
+		Owner:   l.owner,
+		Expires: time.Now().Add(l.ttl),
+	}
+	meta, err := l.col.CreateDocument(ctx, doc)
+	if driver.IsConflict(err) {
+		// Lock document exists, see if it has expired
+		var current lockDocument
+		currentMeta, err := l.col.ReadDocument(ctx, lockKey, &current)
+		if driver.IsNotFound(err) {
+			// Lock has just been released, caller should try again
+			return driver.WithStack(AlreadyLockedError)
+		} else if err != nil {
+			return driver.WithStack(err)
+		}
+		if current.Owner != l.owner && time.Now().Before(current.Expires) {
+			return driver.WithStack(AlreadyLockedError)
+		}
+		// Take over the expired lock
+		meta, err = l.col.ReplaceDocument(driver.WithRevision(ctx, currentMeta.Rev), lockKey, doc)
+		if driver.IsPreconditionFailed(err) {
+			// Someone else was quicker
+			return driver.WithStack(AlreadyLockedError)
+		} else if err != nil {
+			return driver.WithStack(err)
+		}
+	} else if err != nil {
+		return driver.WithStack(err)
+	}
+
+	// Success
+	l.locked = true
+	l.rev = meta.Rev
+
+	// Keep renewing
+	renewCtx, renewCancel := context.WithCancel(context.Background())
+	go l.renewLock(renewCtx)
+	l.cancelRenewal = renewCancel
+
+	return nil
+}
+
+// Unlock releases the lock.
+// If the lock is not held by this process, NotLockedError is returned.
+func (l *lock) Unlock(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.locked {
+		return driver.WithStack(NotLockedError)
+	}
+
+	// Stop renewing
+	if l.cancelRenewal != nil {
+		l.cancelRenewal()
+		l.cancelRenewal = nil
+	}
+	l.locked = false
+
+	// Release the lock
+	if _, err := l.col.RemoveDocument(driver.WithRevision(ctx, l.rev), lockKey); err != nil {
+		if driver.IsPreconditionFailed(err) || driver.IsNotFound(err) {
+			// We lost the lock in the mean time
+			return driver.WithStack(NotLockedError)
+		}
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// IsLocked return true if the lock is held by this process.
+func (l *lock) IsLocked() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.locked
+}
+
+// renewLock keeps renewing the lock until the given context is canceled.
+func (l *lock) renewLock(ctx context.Context) {
+	// op performs a renewal once.
+	// returns stop, error
+	op := func() (bool, error) {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		if !l.locked {
+			return true, driver.WithStack(NotLockedError)
+		}
+		opCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+		defer cancel()
+		doc := lockDocument{
+			Key:     lockKey,
+			Owner:   l.owner,
+			Expires: time.Now().Add(l.ttl),
+		}
+		meta, err := l.col.ReplaceDocument(driver.WithRevision(opCtx, l.rev), lockKey, doc)
+		if err != nil {
+			if driver.IsPreconditionFailed(err) || driver.IsNotFound(err) {
+				// Someone took over our lock
+				l.locked = false
+				l.cancelRenewal = nil
+				return true, driver.WithStack(err)
+			}
+			return false, driver.WithStack(err)
+		}
+		l.rev = meta.Rev
+		return false, nil
+	}
+	for {
+		delay := l.ttl / 2
+		stop, err := op()
+		if stop || driver.Cause(err) == context.Canceled {
+			return
+		}
+		if err != nil {
+			delay = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			// we're done
+			return
+		case <-time.After(delay):
+			// Continue
+		}
+	}
+}
@@ -0,0 +1,286 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// DefaultCollection is the default name of the collection used to record applied migrations.
+	DefaultCollection = "_migrations"
+	// DefaultLockTTL is the default time-to-live of the migration lock.
+	DefaultLockTTL = time.Second * 30
+	// DefaultLockRetryInterval is the default time to wait before trying to acquire the migration lock again.
+	DefaultLockRetryInterval = time.Second
+)
+
+// Migration is a single versioned change of a database.
+type Migration struct {
+	// Version of the migration. Migrations are applied in ascending version order.
+	// Every version is applied only once. Versions must be greater than 0.
+	Version int
+	// Description of the migration.
+	Description string
+	// Up applies the migration.
+	Up func(ctx context.Context, db driver.Database) error
+}
+
+// AppliedMigration is the record of a migration that has been applied.
+type AppliedMigration struct {
+	// Version of the migration.
+	Version int `json:"version"`
+	// Description of the migration.
+	Description string `json:"description,omitempty"`
+	// AppliedAt holds the time the migration was applied.
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// Result holds the outcome of Migrator.Run.
+type Result struct {
+	// Plan that was applied to bring the database in line with the schema.
+	Plan Plan
+	// Applied holds the versions of the migrations that were applied.
+	Applied []int
+}
+
+// Config holds the configuration of a Migrator.
+type Config struct {
+	// Collection is the name of the collection used to record applied migrations
+	// and to hold the migration lock. Defaults to DefaultCollection.
+	Collection string
+	// Owner identifies this process in the migration lock.
+	// Defaults to a random identifier.
+	Owner string
+	// LockTTL is the time after which the lock expires when it is not renewed.
+	// Defaults to DefaultLockTTL.
+	LockTTL time.Duration
+	// LockRetryInterval is the time to wait before trying to acquire the lock again
+	// when it is held by another process. Defaults to DefaultLockRetryInterval.
+	LockRetryInterval time.Duration
+}
+
+// Migrator brings a database in line with a schema and applies versioned migrations.
+type Migrator interface {
+	// Run acquires the migration lock, syncs the database with the given schema (if not nil)
+	// and then applies all given migrations that have not been applied before.
+	// If the lock is held by another process, Run waits until it is released or the context is done.
+	Run(ctx context.Context, schema *Schema, migrations ...Migration) (Result, error)
+
+	// AppliedMigrations returns all migrations that have been applied, ordered by version.
+	AppliedMigrations(ctx context.Context) ([]AppliedMigration, error)
+}
+
+// NewMigrator creates a new Migrator for the given database.
+func NewMigrator(db driver.Database, config Config) (Migrator, error) {
+	if db == nil {
+		return nil, driver.WithStack(driver.InvalidArgumentError{Message: "db is nil"})
+	}
+	if config.Collection == "" {
+		config.Collection = DefaultCollection
+	}
+	if config.Owner == "" {
+		randBytes := make([]byte, 16)
+		rand.Read(randBytes)
+		config.Owner = hex.EncodeToString(randBytes)
+	}
+	if config.LockTTL == 0 {
+		config.LockTTL = DefaultLockTTL
+	}
+	if config.LockRetryInterval == 0 {
+		config.LockRetryInterval = DefaultLockRetryInterval
+	}
+	return &migrator{
+		db:     db,
+		config: config,
+	}, nil
+}
+
+type migrator struct {
+	db     driver.Database
+	config Config
+}
+
+// Run acquires the migration lock, syncs the database with the given schema (if not nil)
+// and then applies all given migrations that have not been applied before.
+func (m *migrator) Run(ctx context.Context, schema *Schema, migrations ...Migration) (Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	pending := append([]Migration(nil), migrations...)
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+	for i, x := range pending {
+		if x.Version <= 0 {
+			return Result{}, driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("invalid migration version %d", x.Version)})
+		}
+		if i > 0 && pending[i-1].Version == x.Version {
+			return Result{}, driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("duplicate migration version %d", x.Version)})
+		}
+		if x.Up == nil {
+			return Result{}, driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("migration %d has no Up function", x.Version)})
+		}
+	}
+
+	col, err := m.ensureCollection(ctx)
+	if err != nil {
+		return Result{}, driver.WithStack(err)
+	}
+	l := newLock(col, m.config.Owner, m.config.LockTTL)
+	if err := m.acquire(ctx, l); err != nil {
+		return Result{}, driver.WithStack(err)
+	}
+	defer l.Unlock(context.Background())
+
+	var result Result
+	if schema != nil {
+		plan, err := Sync(ctx, m.db, *schema)
+		result.Plan = plan
+		if err != nil {
+			return result, driver.WithStack(err)
+		}
+	}
+
+	applied, err := m.appliedMigrations(ctx, col)
+	if err != nil {
+		return result, driver.WithStack(err)
+	}
+	done := make(map[int]struct{}, len(applied))
+	for _, x := range applied {
+		done[x.Version] = struct{}{}
+	}
+	for _, x := range pending {
+		if _, found := done[x.Version]; found {
+			continue
+		}
+		if !l.IsLocked() {
+			return result, driver.WithStack(NotLockedError)
+		}
+		if err := x.Up(ctx, m.db); err != nil {
+			return result, driver.WithStack(fmt.Errorf("migration %d failed: %v", x.Version, err))
+		}
+		record := struct {
+			Key string `json:"_key"`
+			AppliedMigration
+		}{
+			Key: strconv.Itoa(x.Version),
+			AppliedMigration: AppliedMigration{
+				Version:     x.Version,
+				Description: x.Description,
+				AppliedAt:   time.Now().UTC(),
+			},
+		}
+		if _, err := col.CreateDocument(ctx, record); err != nil {
+			return result, driver.WithStack(err)
+		}
+		result.Applied = append(result.Applied, x.Version)
+	}
+	return result, nil
+}
+
+// AppliedMigrations returns all migrations that have been applied, ordered by version.
+func (m *migrator) AppliedMigrations(ctx context.Context) ([]AppliedMigration, error) {
+	col, err := m.db.Collection(ctx, m.config.Collection)
+	if driver.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	result, err := m.appliedMigrations(ctx, col)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	return result, nil
+}
+
+// appliedMigrations returns all migrations recorded in the given collection, ordered by version.
+func (m *migrator) appliedMigrations(ctx context.Context, col driver.Collection) ([]AppliedMigration, error) {
+	query := "FOR m IN @@col FILTER m._key != @lock SORT m.version RETURN m"
+	bindVars := map[string]interface{}{
+		"@col": col.Name(),
+		"lock": lockKey,
+	}
+	cursor, err := m.db.Query(ctx, query, bindVars)
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	defer cursor.Close()
+	var result []AppliedMigration
+	for cursor.HasMore() {
+		var x AppliedMigration
+		if _, err := cursor.ReadDocument(ctx, &x); err != nil {
+			return nil, driver.WithStack(err)
+		}
+		result = append(result, x)
+	}
+	return result, nil
+}
+
+// ensureCollection opens the migrations collection, creating it when needed.
+func (m *migrator) ensureCollection(ctx context.Context) (driver.Collection, error) {
+	col, err := m.db.Collection(ctx, m.config.Collection)
+	if err == nil {
+		return col, nil
+	} else if !driver.IsNotFound(err) {
+		return nil, driver.WithStack(err)
+	}
+	options := &driver.CreateCollectionOptions{
+		IsSystem: strings.HasPrefix(m.config.Collection, "_"),
+	}
+	col, err = m.db.CreateCollection(ctx, m.config.Collection, options)
+	if driver.IsConflict(err) {
+		// Created by another process in the mean time
+		col, err = m.db.Collection(ctx, m.config.Collection)
+	}
+	if err != nil {
+		return nil, driver.WithStack(err)
+	}
+	return col, nil
+}
+
+// acquire keeps trying to lock the given lock until it succeeds or the context is done.
+func (m *migrator) acquire(ctx context.Context, l *lock) error {
+	for {
+		err := l.Lock(ctx)
+		if err == nil {
+			return nil
+		}
+		if driver.Cause(err) != AlreadyLockedError {
+			return driver.WithStack(err)
+		}
+		select {
+		case <-ctx.Done():
+			return driver.WithStack(ctx.Err())
+		case <-time.After(m.config.LockRetryInterval):
+			// Try again
+		}
+	}
+}
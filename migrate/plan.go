@@ -0,0 +1,422 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// StepKind identifies the kind of change made by a plan step.
+type StepKind string
+
+const (
+	// StepCreateCollection creates a collection.
+	StepCreateCollection StepKind = "create-collection"
+	// StepUpdateCollection changes the properties of an existing collection.
+	StepUpdateCollection StepKind = "update-collection"
+	// StepEnsureIndex creates an index in a collection.
+	StepEnsureIndex StepKind = "ensure-index"
+	// StepCreateGraph creates a named graph.
+	StepCreateGraph StepKind = "create-graph"
+	// StepCreateEdgeDefinition adds an edge definition to an existing graph.
+	StepCreateEdgeDefinition StepKind = "create-edge-definition"
+	// StepSetVertexConstraints changes the from/to collections of an existing edge definition.
+	StepSetVertexConstraints StepKind = "set-vertex-constraints"
+	// StepCreateVertexCollection adds an orphan vertex collection to an existing graph.
+	StepCreateVertexCollection StepKind = "create-vertex-collection"
+	// StepCreateView creates an ArangoSearch view.
+	StepCreateView StepKind = "create-view"
+	// StepUpdateViewLinks adds or changes links of an existing ArangoSearch view.
+	StepUpdateViewLinks StepKind = "update-view-links"
+)
+
+// Step is a single change of a Plan.
+type Step struct {
+	// Kind of change.
+	Kind StepKind
+	// Collection affected by this step (if any).
+	Collection string
+	// Graph affected by this step (if any).
+	Graph string
+	// View affected by this step (if any).
+	View string
+
+	createCollection *CollectionSchema
+	properties       *driver.SetCollectionPropertiesOptions
+	index            *IndexSchema
+	createGraph      *GraphSchema
+	edgeDefinition   *driver.EdgeDefinition
+	viewLinks        map[string]ViewLinkSchema
+}
+
+// String returns a human readable description of the step.
+func (s Step) String() string {
+	switch s.Kind {
+	case StepCreateCollection:
+		return fmt.Sprintf("create %s collection '%s'", s.createCollection.collectionType(), s.Collection)
+	case StepUpdateCollection:
+		var changes []string
+		if s.properties.WaitForSync != nil {
+			changes = append(changes, fmt.Sprintf("waitForSync=%t", *s.properties.WaitForSync))
+		}
+		if s.properties.ReplicationFactor != 0 {
+			changes = append(changes, fmt.Sprintf("replicationFactor=%d", s.properties.ReplicationFactor))
+		}
+		return fmt.Sprintf("update collection '%s' (%s)", s.Collection, strings.Join(changes, ", "))
+	case StepEnsureIndex:
+		return fmt.Sprintf("create %s in collection '%s'", s.index, s.Collection)
+	case StepCreateGraph:
+		return fmt.Sprintf("create graph '%s'", s.Graph)
+	case StepCreateEdgeDefinition:
+		return fmt.Sprintf("add edge definition '%s' (%s -> %s) to graph '%s'", s.Collection,
+			strings.Join(s.edgeDefinition.From, ", "), strings.Join(s.edgeDefinition.To, ", "), s.Graph)
+	case StepSetVertexConstraints:
+		return fmt.Sprintf("change edge definition '%s' of graph '%s' to (%s -> %s)", s.Collection, s.Graph,
+			strings.Join(s.edgeDefinition.From, ", "), strings.Join(s.edgeDefinition.To, ", "))
+	case StepCreateVertexCollection:
+		return fmt.Sprintf("add vertex collection '%s' to graph '%s'", s.Collection, s.Graph)
+	case StepCreateView:
+		return fmt.Sprintf("create view '%s'", s.View)
+	case StepUpdateViewLinks:
+		return fmt.Sprintf("update links of view '%s' (%s)", s.View, strings.Join(sortedLinkNames(s.viewLinks), ", "))
+	default:
+		return string(s.Kind)
+	}
+}
+
+// Plan holds the changes needed to bring a database in line with a schema.
+type Plan struct {
+	// Steps to execute (in order).
+	Steps []Step
+	// Conflicts holds differences between the schema and the database that cannot be
+	// resolved automatically (e.g. a change in the number of shards).
+	// A plan with conflicts cannot be applied.
+	Conflicts []string
+}
+
+// IsEmpty returns true if the plan contains no steps and no conflicts.
+func (p Plan) IsEmpty() bool {
+	return len(p.Steps) == 0 && len(p.Conflicts) == 0
+}
+
+// String returns a human readable representation of the plan.
+func (p Plan) String() string {
+	if p.IsEmpty() {
+		return "no changes"
+	}
+	var buf bytes.Buffer
+	for i, s := range p.Steps {
+		fmt.Fprintf(&buf, "%d. %s\n", i+1, s)
+	}
+	for _, c := range p.Conflicts {
+		fmt.Fprintf(&buf, "! %s\n", c)
+	}
+	return buf.String()
+}
+
+// dbState holds the parts of a live database relevant for a schema.
+type dbState struct {
+	collections map[string]collectionState
+	graphs      map[string]graphState
+	views       map[string]viewState
+}
+
+type collectionState struct {
+	properties driver.CollectionProperties
+	indexes    []driver.Index
+}
+
+type graphState struct {
+	edgeDefinitions   map[string]driver.VertexConstraints
+	vertexCollections map[string]struct{}
+}
+
+type viewState struct {
+	viewType driver.ViewType
+	links    driver.ArangoSearchLinks
+}
+
+// BuildPlan compares the given schema with the given database and returns
+// the changes needed to bring the database in line with the schema.
+func BuildPlan(ctx context.Context, db driver.Database, schema Schema) (Plan, error) {
+	if err := schema.Validate(); err != nil {
+		return Plan{}, driver.WithStack(err)
+	}
+	state, err := readState(ctx, db, schema)
+	if err != nil {
+		return Plan{}, driver.WithStack(err)
+	}
+	return diff(schema, state), nil
+}
+
+// readState fetches the state of all collections & graphs in the database that are relevant for the given schema.
+func readState(ctx context.Context, db driver.Database, schema Schema) (dbState, error) {
+	state := dbState{
+		collections: make(map[string]collectionState),
+		graphs:      make(map[string]graphState),
+		views:       make(map[string]viewState),
+	}
+	wanted := make(map[string]struct{})
+	for _, c := range schema.Collections {
+		wanted[c.Name] = struct{}{}
+	}
+	cols, err := db.Collections(ctx)
+	if err != nil {
+		return dbState{}, driver.WithStack(err)
+	}
+	for _, col := range cols {
+		if _, found := wanted[col.Name()]; !found {
+			continue
+		}
+		props, err := col.Properties(ctx)
+		if err != nil {
+			return dbState{}, driver.WithStack(err)
+		}
+		indexes, err := col.Indexes(ctx)
+		if err != nil {
+			return dbState{}, driver.WithStack(err)
+		}
+		state.collections[col.Name()] = collectionState{
+			properties: props,
+			indexes:    indexes,
+		}
+	}
+
+	wanted = make(map[string]struct{})
+	for _, g := range schema.Graphs {
+		wanted[g.Name] = struct{}{}
+	}
+	graphs, err := db.Graphs(ctx)
+	if err != nil {
+		return dbState{}, driver.WithStack(err)
+	}
+	for _, g := range graphs {
+		if _, found := wanted[g.Name()]; !found {
+			continue
+		}
+		ecs, constraints, err := g.EdgeCollections(ctx)
+		if err != nil {
+			return dbState{}, driver.WithStack(err)
+		}
+		vcs, err := g.VertexCollections(ctx)
+		if err != nil {
+			return dbState{}, driver.WithStack(err)
+		}
+		gs := graphState{
+			edgeDefinitions:   make(map[string]driver.VertexConstraints),
+			vertexCollections: make(map[string]struct{}),
+		}
+		for i, ec := range ecs {
+			gs.edgeDefinitions[ec.Name()] = constraints[i]
+		}
+		for _, vc := range vcs {
+			gs.vertexCollections[vc.Name()] = struct{}{}
+		}
+		state.graphs[g.Name()] = gs
+	}
+
+	if len(schema.Views) == 0 {
+		// Do not require a server that supports views
+		return state, nil
+	}
+	wanted = make(map[string]struct{})
+	for _, v := range schema.Views {
+		wanted[v.Name] = struct{}{}
+	}
+	views, err := db.Views(ctx)
+	if err != nil {
+		return dbState{}, driver.WithStack(err)
+	}
+	for _, v := range views {
+		if _, found := wanted[v.Name()]; !found {
+			continue
+		}
+		vs := viewState{viewType: v.Type()}
+		if vs.viewType == driver.ViewTypeArangoSearch {
+			asv, err := v.ArangoSearchView()
+			if err != nil {
+				return dbState{}, driver.WithStack(err)
+			}
+			props, err := asv.Properties(ctx)
+			if err != nil {
+				return dbState{}, driver.WithStack(err)
+			}
+			vs.links = props.Links
+		}
+		state.views[v.Name()] = vs
+	}
+	return state, nil
+}
+
+// diff compares the schema with the given state and returns the resulting plan.
+func diff(schema Schema, state dbState) Plan {
+	var plan Plan
+	for i := range schema.Collections {
+		c := &schema.Collections[i]
+		cs, found := state.collections[c.Name]
+		if !found {
+			plan.Steps = append(plan.Steps, Step{Kind: StepCreateCollection, Collection: c.Name, createCollection: c})
+			for j := range c.Indexes {
+				plan.Steps = append(plan.Steps, Step{Kind: StepEnsureIndex, Collection: c.Name, index: &c.Indexes[j]})
+			}
+			continue
+		}
+		props := cs.properties
+		wantedType := driver.CollectionTypeDocument
+		if c.collectionType() == CollectionTypeEdge {
+			wantedType = driver.CollectionTypeEdge
+		}
+		if props.Type != 0 && props.Type != wantedType {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("collection '%s' exists but is not a %s collection", c.Name, c.collectionType()))
+		}
+		if c.NumberOfShards != 0 && props.NumberOfShards != 0 && c.NumberOfShards != props.NumberOfShards {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("collection '%s' has %d shards, schema requires %d", c.Name, props.NumberOfShards, c.NumberOfShards))
+		}
+		if len(c.ShardKeys) != 0 && len(props.ShardKeys) != 0 && !equalStrings(c.ShardKeys, props.ShardKeys) {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("collection '%s' has shard keys [%s], schema requires [%s]", c.Name,
+				strings.Join(props.ShardKeys, ", "), strings.Join(c.ShardKeys, ", ")))
+		}
+		var update driver.SetCollectionPropertiesOptions
+		needsUpdate := false
+		if c.WaitForSync != nil && *c.WaitForSync != props.WaitForSync {
+			update.WaitForSync = c.WaitForSync
+			needsUpdate = true
+		}
+		if c.ReplicationFactor != 0 && props.ReplicationFactor != 0 && c.ReplicationFactor != props.ReplicationFactor {
+			update.ReplicationFactor = c.ReplicationFactor
+			needsUpdate = true
+		}
+		if needsUpdate {
+			plan.Steps = append(plan.Steps, Step{Kind: StepUpdateCollection, Collection: c.Name, properties: &update})
+		}
+		for j := range c.Indexes {
+			idx := &c.Indexes[j]
+			if !hasIndex(cs.indexes, *idx) {
+				plan.Steps = append(plan.Steps, Step{Kind: StepEnsureIndex, Collection: c.Name, index: idx})
+			}
+		}
+	}
+
+	for i := range schema.Graphs {
+		g := &schema.Graphs[i]
+		gs, found := state.graphs[g.Name]
+		if !found {
+			plan.Steps = append(plan.Steps, Step{Kind: StepCreateGraph, Graph: g.Name, createGraph: g})
+			continue
+		}
+		for j := range g.EdgeDefinitions {
+			ed := &g.EdgeDefinitions[j]
+			constraints, found := gs.edgeDefinitions[ed.Collection]
+			if !found {
+				plan.Steps = append(plan.Steps, Step{Kind: StepCreateEdgeDefinition, Graph: g.Name, Collection: ed.Collection, edgeDefinition: ed})
+			} else if !equalStringSets(ed.From, constraints.From) || !equalStringSets(ed.To, constraints.To) {
+				plan.Steps = append(plan.Steps, Step{Kind: StepSetVertexConstraints, Graph: g.Name, Collection: ed.Collection, edgeDefinition: ed})
+			}
+		}
+		for _, name := range g.OrphanCollections {
+			if _, found := gs.vertexCollections[name]; !found {
+				plan.Steps = append(plan.Steps, Step{Kind: StepCreateVertexCollection, Graph: g.Name, Collection: name})
+			}
+		}
+	}
+
+	for _, v := range schema.Views {
+		vs, found := state.views[v.Name]
+		if !found {
+			plan.Steps = append(plan.Steps, Step{Kind: StepCreateView, View: v.Name, viewLinks: v.Links})
+			continue
+		}
+		if vs.viewType != v.viewType() {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("view '%s' exists but is not a %s view", v.Name, v.viewType()))
+			continue
+		}
+		changed := make(map[string]ViewLinkSchema)
+		for name, l := range v.Links {
+			if current, found := vs.links[name]; !found || !l.matches(current) {
+				changed[name] = l
+			}
+		}
+		if len(changed) > 0 {
+			plan.Steps = append(plan.Steps, Step{Kind: StepUpdateViewLinks, View: v.Name, viewLinks: changed})
+		}
+	}
+	return plan
+}
+
+// hasIndex returns true if the given list of indexes contains an index that matches the given specification.
+func hasIndex(indexes []driver.Index, spec IndexSchema) bool {
+	for _, idx := range indexes {
+		if idx.Type() != spec.Type || !equalStrings(idx.Fields(), spec.Fields) {
+			continue
+		}
+		switch spec.Type {
+		case driver.HashIndex, driver.SkipListIndex, driver.PersistentIndex:
+			if idx.Unique() != spec.Unique || idx.Sparse() != spec.Sparse {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// equalStrings returns true if both slices contain the same elements in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// equalStringSets returns true if both slices contain the same elements, ignoring the order.
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string(nil), a...)
+	y := append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	return equalStrings(x, y)
+}
+
+// sortedLinkNames returns the names of the collections of the given links in sorted order.
+func sortedLinkNames(links map[string]ViewLinkSchema) []string {
+	names := make([]string, 0, len(links))
+	for name := range links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,353 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	driverhttp "github.com/arangodb/go-driver/http"
+)
+
+// testIndex implements driver.Index for use in diff tests.
+type testIndex struct {
+	indexType driver.IndexType
+	fields    []string
+	unique    bool
+	sparse    bool
+}
+
+func (i testIndex) Name() string                     { return "1" }
+func (i testIndex) Type() driver.IndexType           { return i.indexType }
+func (i testIndex) Fields() []string                 { return i.fields }
+func (i testIndex) Unique() bool                     { return i.unique }
+func (i testIndex) Sparse() bool                     { return i.sparse }
+func (i testIndex) Deduplicate() bool                { return true }
+func (i testIndex) MinLength() int                   { return 0 }
+func (i testIndex) GeoJSON() bool                    { return false }
+func (i testIndex) Remove(ctx context.Context) error { return nil }
+
+func stepKinds(p Plan) []string {
+	var result []string
+	for _, s := range p.Steps {
+		result = append(result, string(s.Kind))
+	}
+	return result
+}
+
+// TestDiffEmptyDatabase checks the plan for a database that contains nothing yet.
+func TestDiffEmptyDatabase(t *testing.T) {
+	schema, err := ParseYAML([]byte(testSchemaYAML))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %s", err)
+	}
+	plan := diff(schema, dbState{})
+	expected := []string{
+		string(StepCreateCollection), string(StepEnsureIndex),
+		string(StepCreateCollection),
+		string(StepCreateGraph),
+	}
+	if got := stepKinds(plan); !equalStrings(got, expected) {
+		t.Errorf("Unexpected steps. Expected %v, got %v", expected, got)
+	}
+	if len(plan.Conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %v", plan.Conflicts)
+	}
+	if s := plan.String(); !strings.Contains(s, "create edge collection 'follows'") {
+		t.Errorf("Expected plan description to mention edge collection, got:\n%s", s)
+	}
+}
+
+// TestDiffUpToDate checks that a database that matches the schema results in an empty plan.
+func TestDiffUpToDate(t *testing.T) {
+	schema, err := ParseYAML([]byte(testSchemaYAML))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %s", err)
+	}
+	state := dbState{
+		collections: map[string]collectionState{
+			"users": {
+				properties: driver.CollectionProperties{
+					CollectionInfo: driver.CollectionInfo{Type: driver.CollectionTypeDocument},
+					WaitForSync:    true,
+				},
+				indexes: []driver.Index{
+					testIndex{indexType: driver.PrimaryIndex, fields: []string{"_key"}, unique: true},
+					testIndex{indexType: driver.HashIndex, fields: []string{"email"}, unique: true},
+				},
+			},
+			"follows": {
+				properties: driver.CollectionProperties{
+					CollectionInfo: driver.CollectionInfo{Type: driver.CollectionTypeEdge},
+				},
+			},
+		},
+		graphs: map[string]graphState{
+			"social": {
+				edgeDefinitions: map[string]driver.VertexConstraints{
+					"follows": {From: []string{"users"}, To: []string{"users"}},
+				},
+				vertexCollections: map[string]struct{}{"users": {}, "groups": {}},
+			},
+		},
+	}
+	plan := diff(schema, state)
+	if !plan.IsEmpty() {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+}
+
+// TestDiffChanges checks the plan for a database that partially matches the schema.
+func TestDiffChanges(t *testing.T) {
+	schema, err := ParseYAML([]byte(testSchemaYAML))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %s", err)
+	}
+	schema.Collections[0].NumberOfShards = 3
+	state := dbState{
+		collections: map[string]collectionState{
+			"users": {
+				properties: driver.CollectionProperties{
+					CollectionInfo: driver.CollectionInfo{Type: driver.CollectionTypeDocument},
+					NumberOfShards: 1,
+				},
+				indexes: []driver.Index{
+					// Same fields, but not unique
+					testIndex{indexType: driver.HashIndex, fields: []string{"email"}},
+				},
+			},
+			"follows": {
+				properties: driver.CollectionProperties{
+					CollectionInfo: driver.CollectionInfo{Type: driver.CollectionTypeEdge},
+				},
+			},
+		},
+		graphs: map[string]graphState{
+			"social": {
+				edgeDefinitions: map[string]driver.VertexConstraints{
+					"follows": {From: []string{"users"}, To: []string{"groups"}},
+				},
+				vertexCollections: map[string]struct{}{"users": {}, "groups": {}},
+			},
+		},
+	}
+	plan := diff(schema, state)
+	expected := []string{
+		string(StepUpdateCollection),
+		string(StepEnsureIndex),
+		string(StepSetVertexConstraints),
+	}
+	if got := stepKinds(plan); !equalStrings(got, expected) {
+		t.Errorf("Unexpected steps. Expected %v, got %v", expected, got)
+	}
+	if len(plan.Conflicts) != 1 {
+		t.Errorf("Expected 1 conflict, got %v", plan.Conflicts)
+	}
+	if err := Apply(nil, nil, plan); !IsPlanConflict(err) {
+		t.Errorf("Expected PlanConflictError, got %v", err)
+	}
+}
+
+// fakeServer is a fake server holding a database named `fake`.
+// It answers requests for the paths (relative to the database) in responses with the given JSON bodies
+// and requests for other paths with 404. The bodies of all requests are recorded.
+type fakeServer struct {
+	*httptest.Server
+	responses map[string]string
+
+	mutex  sync.Mutex
+	bodies map[string][]byte
+}
+
+func newFakeServer(responses map[string]string) *fakeServer {
+	s := &fakeServer{responses: responses, bodies: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/_db/fake")
+		body, _ := ioutil.ReadAll(r.Body)
+		s.mutex.Lock()
+		s.bodies[key] = body
+		s.mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if key == "GET /_api/database/current" {
+			w.Write([]byte(`{"result":{"name":"fake","id":"1","path":"","isSystem":false}}`))
+		} else if response, found := s.responses[key]; found {
+			w.Write([]byte(response))
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":true,"code":404,"errorNum":1203,"errorMessage":"not found"}`))
+		}
+	}))
+	return s
+}
+
+// database returns the `fake` database of the server.
+func (s *fakeServer) database(t *testing.T) driver.Database {
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{Endpoints: []string{s.URL}})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	c, err := driver.NewClient(driver.ClientConfig{Connection: conn})
+	if err != nil {
+		t.Fatalf("NewClient failed: %s", err)
+	}
+	db, err := c.Database(nil, "fake")
+	if err != nil {
+		t.Fatalf("Database failed: %s", err)
+	}
+	return db
+}
+
+// body returns the body of the last request with given method & path.
+func (s *fakeServer) body(key string) []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.bodies[key]
+}
+
+// TestBuildPlanExistingEdgeCollection checks that the indexes of an existing edge collection are read.
+func TestBuildPlanExistingEdgeCollection(t *testing.T) {
+	srv := newFakeServer(map[string]string{
+		"GET /_api/collection":                    `{"result":[{"id":"2","name":"follows","type":3}]}`,
+		"GET /_api/collection/follows/properties": `{"id":"2","name":"follows","type":3}`,
+		"GET /_api/index": `{"indexes":[
+			{"id":"follows/0","type":"primary","fields":["_key"],"unique":true},
+			{"id":"follows/1","type":"edge","fields":["_from","_to"]},
+			{"id":"follows/2","type":"skiplist","fields":["since"]}
+		]}`,
+		"GET /_api/gharial": `{"graphs":[]}`,
+	})
+	defer srv.Close()
+	db := srv.database(t)
+	schema := Schema{Collections: []CollectionSchema{{
+		Name:    "follows",
+		Type:    CollectionTypeEdge,
+		Indexes: []IndexSchema{{Type: driver.SkipListIndex, Fields: []string{"since"}}},
+	}}}
+	plan, err := BuildPlan(nil, db, schema)
+	if err != nil {
+		t.Fatalf("BuildPlan failed: %s", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+}
+
+// TestDiffViews checks the plan for views that are missing, outdated or of the wrong type.
+func TestDiffViews(t *testing.T) {
+	yes, no := true, false
+	schema := Schema{Views: []ViewSchema{
+		{Name: "search", Links: map[string]ViewLinkSchema{
+			"users": {Fields: map[string]ViewLinkSchema{"name": {Analyzers: []string{"text_en"}}}},
+			"posts": {IncludeAllFields: &yes},
+		}},
+		{Name: "missing", Links: map[string]ViewLinkSchema{"users": {}}},
+		{Name: "other"},
+		{Name: "uptodate", Links: map[string]ViewLinkSchema{"users": {IncludeAllFields: &no}}},
+	}}
+	if err := schema.Validate(); err != nil {
+		t.Fatalf("Validate failed: %s", err)
+	}
+	state := dbState{
+		views: map[string]viewState{
+			"search": {
+				viewType: driver.ViewTypeArangoSearch,
+				links: driver.ArangoSearchLinks{
+					// Custom analyzers are prefixed with the database name by the server
+					"users": {Analyzers: []string{"identity"}, Fields: driver.ArangoSearchFields{"name": {Analyzers: []string{"db::text_en"}}}},
+					"posts": {IncludeAllFields: &no},
+					"other": {},
+				},
+			},
+			"other": {viewType: "custom"},
+			"uptodate": {
+				viewType: driver.ViewTypeArangoSearch,
+				links:    driver.ArangoSearchLinks{"users": {Analyzers: []string{"identity"}}},
+			},
+		},
+	}
+	plan := diff(schema, state)
+	expected := []string{string(StepUpdateViewLinks), string(StepCreateView)}
+	if got := stepKinds(plan); !equalStrings(got, expected) {
+		t.Fatalf("Unexpected steps. Expected %v, got %v", expected, got)
+	}
+	if links := sortedLinkNames(plan.Steps[0].viewLinks); !equalStrings(links, []string{"posts"}) {
+		t.Errorf("Expected only link 'posts' to be updated, got %v", links)
+	}
+	if plan.Steps[1].View != "missing" {
+		t.Errorf("Expected view 'missing' to be created, got '%s'", plan.Steps[1].View)
+	}
+	if len(plan.Conflicts) != 1 || !strings.Contains(plan.Conflicts[0], "'other'") {
+		t.Errorf("Expected a conflict for view 'other', got %v", plan.Conflicts)
+	}
+	if s := plan.String(); !strings.Contains(s, "update links of view 'search' (posts)") {
+		t.Errorf("Expected plan description to mention the updated links, got:\n%s", s)
+	}
+}
+
+// TestSyncViewLinks checks that updating the links of a view keeps the links & settings that are not in the schema.
+func TestSyncViewLinks(t *testing.T) {
+	properties := `{"name":"search","type":"arangosearch","commitIntervalMsec":500,
+		"primarySort":[{"field":"name","asc":true}],
+		"links":{"other":{"analyzers":["identity"],"includeAllFields":true}}}`
+	srv := newFakeServer(map[string]string{
+		"GET /_api/collection":             `{"result":[]}`,
+		"GET /_api/gharial":                `{"graphs":[]}`,
+		"GET /_api/view":                   `{"result":[{"name":"search","type":"arangosearch"}]}`,
+		"GET /_api/view/search":            `{"name":"search","type":"arangosearch"}`,
+		"GET /_api/view/search/properties": properties,
+		"PUT /_api/view/search/properties": properties,
+	})
+	defer srv.Close()
+	db := srv.database(t)
+	yes := true
+	schema := Schema{Views: []ViewSchema{{Name: "search", Links: map[string]ViewLinkSchema{"users": {IncludeAllFields: &yes}}}}}
+	plan, err := Sync(nil, db, schema)
+	if err != nil {
+		t.Fatalf("Sync failed: %s", err)
+	}
+	if got := stepKinds(plan); !equalStrings(got, []string{string(StepUpdateViewLinks)}) {
+		t.Fatalf("Unexpected steps %v", got)
+	}
+	var updated map[string]interface{}
+	if err := json.Unmarshal(srv.body("PUT /_api/view/search/properties"), &updated); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	expected := map[string]interface{}{
+		"commitIntervalMsec": float64(500),
+		"links": map[string]interface{}{
+			"other": map[string]interface{}{"analyzers": []interface{}{"identity"}, "includeAllFields": true},
+			"users": map[string]interface{}{"includeAllFields": true},
+		},
+	}
+	if !reflect.DeepEqual(expected, updated) {
+		t.Errorf("Expected properties %v, got %v", expected, updated)
+	}
+}
@@ -0,0 +1,365 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Schema describes the desired layout of a database.
+type Schema struct {
+	// Collections holds the document & edge collections that must exist.
+	Collections []CollectionSchema `json:"collections,omitempty" yaml:"collections,omitempty"`
+	// Graphs holds the named graphs that must exist.
+	// Collections used in the edge definitions of a graph that are not listed in Collections
+	// are created by the server when the graph is created.
+	Graphs []GraphSchema `json:"graphs,omitempty" yaml:"graphs,omitempty"`
+	// Views holds the ArangoSearch views that must exist.
+	// Views are only available in ArangoDB 3.4 and higher.
+	Views []ViewSchema `json:"views,omitempty" yaml:"views,omitempty"`
+}
+
+// CollectionType is the type of a collection in a schema.
+type CollectionType string
+
+const (
+	// CollectionTypeDocument specifies a document collection (default)
+	CollectionTypeDocument CollectionType = "document"
+	// CollectionTypeEdge specifies an edge collection
+	CollectionTypeEdge CollectionType = "edge"
+)
+
+// CollectionSchema describes a single collection and its indexes.
+type CollectionSchema struct {
+	// Name of the collection.
+	Name string `json:"name" yaml:"name"`
+	// Type of the collection. Defaults to CollectionTypeDocument.
+	Type CollectionType `json:"type,omitempty" yaml:"type,omitempty"`
+	// If set, the waitForSync property of the collection is kept at this value.
+	WaitForSync *bool `json:"waitForSync,omitempty" yaml:"waitForSync,omitempty"`
+	// NumberOfShards of the collection. Cannot be changed once the collection exists.
+	// Only relevant in a cluster setup.
+	NumberOfShards int `json:"numberOfShards,omitempty" yaml:"numberOfShards,omitempty"`
+	// ShardKeys of the collection. Cannot be changed once the collection exists.
+	// Only relevant in a cluster setup.
+	ShardKeys []string `json:"shardKeys,omitempty" yaml:"shardKeys,omitempty"`
+	// ReplicationFactor of the collection.
+	// Only relevant in a cluster setup.
+	ReplicationFactor int `json:"replicationFactor,omitempty" yaml:"replicationFactor,omitempty"`
+	// If true, the collection is created as a system collection.
+	IsSystem bool `json:"isSystem,omitempty" yaml:"isSystem,omitempty"`
+	// KeyOptions specifies how keys in the collection are created.
+	// Only used when creating the collection.
+	KeyOptions *KeyOptions `json:"keyOptions,omitempty" yaml:"keyOptions,omitempty"`
+	// Indexes that must exist on the collection.
+	// The primary and edge indexes always exist and must not be listed.
+	Indexes []IndexSchema `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+}
+
+// KeyOptions specifies ways for creating keys of a collection.
+type KeyOptions struct {
+	// Type of key generator.
+	Type driver.KeyGeneratorType `json:"type,omitempty" yaml:"type,omitempty"`
+	// If set to true, then it is allowed to supply own key values in the _key attribute of a document.
+	AllowUserKeys bool `json:"allowUserKeys,omitempty" yaml:"allowUserKeys,omitempty"`
+	// Increment value for autoincrement key generator.
+	Increment int `json:"increment,omitempty" yaml:"increment,omitempty"`
+	// Initial offset value for autoincrement key generator.
+	Offset int `json:"offset,omitempty" yaml:"offset,omitempty"`
+}
+
+// IndexSchema describes a single index of a collection.
+type IndexSchema struct {
+	// Type of the index (hash, skiplist, persistent, geo or fulltext).
+	Type driver.IndexType `json:"type" yaml:"type"`
+	// Fields is a slice of attribute paths.
+	Fields []string `json:"fields" yaml:"fields"`
+	// If true, then create a unique index.
+	// Only used for hash, skiplist & persistent indexes.
+	Unique bool `json:"unique,omitempty" yaml:"unique,omitempty"`
+	// If true, then create a sparse index.
+	// Only used for hash, skiplist & persistent indexes.
+	Sparse bool `json:"sparse,omitempty" yaml:"sparse,omitempty"`
+	// If true, de-duplication of array-values, before being added to the index, will be turned off.
+	// Only used for hash & skiplist indexes.
+	NoDeduplicate bool `json:"noDeduplicate,omitempty" yaml:"noDeduplicate,omitempty"`
+	// If true, coordinates are in GeoJSON order (longitude first).
+	// Only used for geo indexes.
+	GeoJSON bool `json:"geoJson,omitempty" yaml:"geoJson,omitempty"`
+	// MinLength is the minimum character length of words to index.
+	// Only used for fulltext indexes.
+	MinLength int `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+}
+
+// GraphSchema describes a single named graph.
+type GraphSchema struct {
+	// Name of the graph.
+	Name string `json:"name" yaml:"name"`
+	// EdgeDefinitions of the graph.
+	EdgeDefinitions []driver.EdgeDefinition `json:"edgeDefinitions,omitempty" yaml:"edgeDefinitions,omitempty"`
+	// OrphanCollections are vertex collections of the graph that are not used in any edge definition.
+	OrphanCollections []string `json:"orphanCollections,omitempty" yaml:"orphanCollections,omitempty"`
+	// IsSmart defines if the graph should be smart.
+	// Only used when creating the graph. This only has effect in Enterprise Edition.
+	IsSmart bool `json:"isSmart,omitempty" yaml:"isSmart,omitempty"`
+	// SmartGraphAttribute is the attribute name that is used to smartly shard the vertices of a graph.
+	// Only used when creating the graph.
+	SmartGraphAttribute string `json:"smartGraphAttribute,omitempty" yaml:"smartGraphAttribute,omitempty"`
+	// NumberOfShards is the number of shards that is used for every collection within this graph.
+	// Only used when creating the graph.
+	NumberOfShards int `json:"numberOfShards,omitempty" yaml:"numberOfShards,omitempty"`
+}
+
+// ViewSchema describes a single view.
+type ViewSchema struct {
+	// Name of the view.
+	Name string `json:"name" yaml:"name"`
+	// Type of the view. Defaults to driver.ViewTypeArangoSearch, the only supported type.
+	Type driver.ViewType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Links specifies how collections are indexed in the view.
+	// The keys of the map are collection names.
+	// Links of the view that are not listed are left as they are.
+	Links map[string]ViewLinkSchema `json:"links,omitempty" yaml:"links,omitempty"`
+}
+
+// ViewLinkSchema describes how a collection (or a field of it) is indexed in a view.
+// Settings that are not specified are not compared with the live view and
+// inherit their value from a lower level when the link is created.
+type ViewLinkSchema struct {
+	// Analyzers contains the names of the analyzers used to process this element.
+	Analyzers []string `json:"analyzers,omitempty" yaml:"analyzers,omitempty"`
+	// Fields contains the settings for individual fields of the element.
+	Fields map[string]ViewLinkSchema `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// IncludeAllFields specifies whether or not to include all fields of the element.
+	IncludeAllFields *bool `json:"includeAllFields,omitempty" yaml:"includeAllFields,omitempty"`
+	// TrackListPositions, if set, causes the position of values in array values to be tracked.
+	TrackListPositions *bool `json:"trackListPositions,omitempty" yaml:"trackListPositions,omitempty"`
+	// StoreValues specifies how the view should track values.
+	StoreValues driver.ArangoSearchStoreValues `json:"storeValues,omitempty" yaml:"storeValues,omitempty"`
+}
+
+// ParseJSON parses a schema from the given JSON encoded data.
+func ParseJSON(data []byte) (Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Schema{}, driver.WithStack(err)
+	}
+	if err := s.Validate(); err != nil {
+		return Schema{}, driver.WithStack(err)
+	}
+	return s, nil
+}
+
+// ParseYAML parses a schema from the given YAML encoded data.
+func ParseYAML(data []byte) (Schema, error) {
+	var s Schema
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return Schema{}, driver.WithStack(err)
+	}
+	if err := s.Validate(); err != nil {
+		return Schema{}, driver.WithStack(err)
+	}
+	return s, nil
+}
+
+// LoadFile loads a schema from the file with given name.
+// Files with a `.yaml` or `.yml` extension are parsed as YAML, all other files as JSON.
+func LoadFile(filename string) (Schema, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Schema{}, driver.WithStack(err)
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return ParseJSON(data)
+	}
+}
+
+// Validate checks the schema for errors.
+func (s Schema) Validate() error {
+	collections := make(map[string]struct{})
+	for _, c := range s.Collections {
+		if c.Name == "" {
+			return driver.WithStack(driver.InvalidArgumentError{Message: "collection name is empty"})
+		}
+		if _, found := collections[c.Name]; found {
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("collection '%s' is specified more than once", c.Name)})
+		}
+		collections[c.Name] = struct{}{}
+		switch c.Type {
+		case "", CollectionTypeDocument, CollectionTypeEdge:
+			// Ok
+		default:
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("collection '%s' has unknown type '%s'", c.Name, c.Type)})
+		}
+		for _, idx := range c.Indexes {
+			if err := idx.validate(); err != nil {
+				return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("collection '%s': %s", c.Name, err)})
+			}
+		}
+	}
+	graphs := make(map[string]struct{})
+	for _, g := range s.Graphs {
+		if g.Name == "" {
+			return driver.WithStack(driver.InvalidArgumentError{Message: "graph name is empty"})
+		}
+		if _, found := graphs[g.Name]; found {
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("graph '%s' is specified more than once", g.Name)})
+		}
+		graphs[g.Name] = struct{}{}
+		for _, ed := range g.EdgeDefinitions {
+			if ed.Collection == "" {
+				return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("graph '%s' has an edge definition without collection", g.Name)})
+			}
+			if len(ed.From) == 0 || len(ed.To) == 0 {
+				return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("graph '%s': edge definition '%s' needs from & to collections", g.Name, ed.Collection)})
+			}
+		}
+	}
+	views := make(map[string]struct{})
+	for _, v := range s.Views {
+		if v.Name == "" {
+			return driver.WithStack(driver.InvalidArgumentError{Message: "view name is empty"})
+		}
+		if _, found := views[v.Name]; found {
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("view '%s' is specified more than once", v.Name)})
+		}
+		views[v.Name] = struct{}{}
+		if v.viewType() != driver.ViewTypeArangoSearch {
+			return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("view '%s' has unsupported type '%s'", v.Name, v.Type)})
+		}
+		for name := range v.Links {
+			if name == "" {
+				return driver.WithStack(driver.InvalidArgumentError{Message: fmt.Sprintf("view '%s' has a link without collection", v.Name)})
+			}
+		}
+	}
+	return nil
+}
+
+// collectionType returns the type of the collection, taking the default into account.
+func (c CollectionSchema) collectionType() CollectionType {
+	if c.Type == "" {
+		return CollectionTypeDocument
+	}
+	return c.Type
+}
+
+// validate returns an error if the index specification is invalid.
+func (i IndexSchema) validate() error {
+	if len(i.Fields) == 0 {
+		return fmt.Errorf("%s index has no fields", i.Type)
+	}
+	switch i.Type {
+	case driver.HashIndex, driver.SkipListIndex, driver.PersistentIndex, driver.GeoIndex, driver.FullTextIndex:
+		return nil
+	default:
+		return fmt.Errorf("unsupported index type '%s'", i.Type)
+	}
+}
+
+// String returns a human readable representation of the index.
+func (i IndexSchema) String() string {
+	var flags []string
+	if i.Unique {
+		flags = append(flags, "unique")
+	}
+	if i.Sparse {
+		flags = append(flags, "sparse")
+	}
+	result := fmt.Sprintf("%s index on [%s]", i.Type, strings.Join(i.Fields, ", "))
+	if len(flags) > 0 {
+		result += " (" + strings.Join(flags, ", ") + ")"
+	}
+	return result
+}
+
+// viewType returns the type of the view, taking the default into account.
+func (v ViewSchema) viewType() driver.ViewType {
+	if v.Type == "" {
+		return driver.ViewTypeArangoSearch
+	}
+	return v.Type
+}
+
+// properties returns the link as ArangoSearch element properties.
+func (l ViewLinkSchema) properties() driver.ArangoSearchElementProperties {
+	p := driver.ArangoSearchElementProperties{
+		Analyzers:          l.Analyzers,
+		IncludeAllFields:   l.IncludeAllFields,
+		TrackListPositions: l.TrackListPositions,
+		StoreValues:        l.StoreValues,
+	}
+	if len(l.Fields) > 0 {
+		p.Fields = make(driver.ArangoSearchFields, len(l.Fields))
+		for name, f := range l.Fields {
+			p.Fields[name] = f.properties()
+		}
+	}
+	return p
+}
+
+// matches returns true if all settings specified in the link have the same value in the given properties.
+func (l ViewLinkSchema) matches(p driver.ArangoSearchElementProperties) bool {
+	if len(l.Analyzers) > 0 && !equalStringSets(analyzerNames(l.Analyzers), analyzerNames(p.Analyzers)) {
+		return false
+	}
+	if l.IncludeAllFields != nil && *l.IncludeAllFields != (p.IncludeAllFields != nil && *p.IncludeAllFields) {
+		return false
+	}
+	if l.TrackListPositions != nil && *l.TrackListPositions != (p.TrackListPositions != nil && *p.TrackListPositions) {
+		return false
+	}
+	if l.StoreValues != "" && l.StoreValues != p.StoreValues {
+		return false
+	}
+	for name, f := range l.Fields {
+		current, found := p.Fields[name]
+		if !found || !f.matches(current) {
+			return false
+		}
+	}
+	return true
+}
+
+// analyzerNames returns the given analyzer names without the database prefix
+// the server adds to the names of custom analyzers.
+func analyzerNames(names []string) []string {
+	result := make([]string, len(names))
+	for i, name := range names {
+		if idx := strings.LastIndex(name, "::"); idx >= 0 {
+			name = name[idx+2:]
+		}
+		result[i] = name
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package migrate
+
+import (
+	"reflect"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const testSchemaYAML = `
+collections:
+- name: users
+  waitForSync: true
+  indexes:
+  - type: hash
+    fields: [email]
+    unique: true
+- name: follows
+  type: edge
+graphs:
+- name: social
+  edgeDefinitions:
+  - collection: follows
+    from: [users]
+    to: [users]
+  orphanCollections: [groups]
+`
+
+const testSchemaJSON = `{
+	"collections": [
+		{"name": "users", "waitForSync": true, "indexes": [{"type": "hash", "fields": ["email"], "unique": true}]},
+		{"name": "follows", "type": "edge"}
+	],
+	"graphs": [
+		{"name": "social", "edgeDefinitions": [{"collection": "follows", "from": ["users"], "to": ["users"]}], "orphanCollections": ["groups"]}
+	]
+}`
+
+// TestParseSchema checks that YAML & JSON schemas are parsed into the same structure.
+func TestParseSchema(t *testing.T) {
+	fromYAML, err := ParseYAML([]byte(testSchemaYAML))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %s", err)
+	}
+	fromJSON, err := ParseJSON([]byte(testSchemaJSON))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+	for name, s := range map[string]Schema{"yaml": fromYAML, "json": fromJSON} {
+		if len(s.Collections) != 2 {
+			t.Fatalf("%s: Expected 2 collections, got %d", name, len(s.Collections))
+		}
+		users := s.Collections[0]
+		if users.Name != "users" || users.collectionType() != CollectionTypeDocument {
+			t.Errorf("%s: Unexpected first collection %+v", name, users)
+		}
+		if users.WaitForSync == nil || !*users.WaitForSync {
+			t.Errorf("%s: Expected waitForSync to be set", name)
+		}
+		if len(users.Indexes) != 1 || users.Indexes[0].Type != driver.HashIndex || !users.Indexes[0].Unique {
+			t.Errorf("%s: Unexpected indexes %+v", name, users.Indexes)
+		}
+		if s.Collections[1].collectionType() != CollectionTypeEdge {
+			t.Errorf("%s: Expected edge collection, got %s", name, s.Collections[1].Type)
+		}
+		if len(s.Graphs) != 1 || len(s.Graphs[0].EdgeDefinitions) != 1 {
+			t.Fatalf("%s: Unexpected graphs %+v", name, s.Graphs)
+		}
+		ed := s.Graphs[0].EdgeDefinitions[0]
+		if ed.Collection != "follows" || !equalStrings(ed.From, []string{"users"}) || !equalStrings(ed.To, []string{"users"}) {
+			t.Errorf("%s: Unexpected edge definition %+v", name, ed)
+		}
+		if !equalStrings(s.Graphs[0].OrphanCollections, []string{"groups"}) {
+			t.Errorf("%s: Unexpected orphan collections %v", name, s.Graphs[0].OrphanCollections)
+		}
+	}
+}
+
+const testViewSchemaYAML = `
+views:
+- name: search
+  links:
+    users:
+      includeAllFields: true
+      fields:
+        name:
+          analyzers: [text_en]
+`
+
+const testViewSchemaJSON = `{
+	"views": [
+		{"name": "search", "links": {"users": {"includeAllFields": true, "fields": {"name": {"analyzers": ["text_en"]}}}}}
+	]
+}`
+
+// TestParseViewSchema checks that views in YAML & JSON schemas are parsed into the same structure.
+func TestParseViewSchema(t *testing.T) {
+	fromYAML, err := ParseYAML([]byte(testViewSchemaYAML))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %s", err)
+	}
+	fromJSON, err := ParseJSON([]byte(testViewSchemaJSON))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %s", err)
+	}
+	includeAllFields := true
+	expected := []ViewSchema{{
+		Name: "search",
+		Links: map[string]ViewLinkSchema{
+			"users": {
+				IncludeAllFields: &includeAllFields,
+				Fields:           map[string]ViewLinkSchema{"name": {Analyzers: []string{"text_en"}}},
+			},
+		},
+	}}
+	for name, s := range map[string]Schema{"yaml": fromYAML, "json": fromJSON} {
+		if !reflect.DeepEqual(expected, s.Views) {
+			t.Errorf("%s: Expected views %+v, got %+v", name, expected, s.Views)
+		}
+	}
+}
+
+// TestValidateSchema checks that invalid schemas are rejected.
+func TestValidateSchema(t *testing.T) {
+	tests := map[string]Schema{
+		"empty collection name": {Collections: []CollectionSchema{{}}},
+		"duplicate collection":  {Collections: []CollectionSchema{{Name: "a"}, {Name: "a"}}},
+		"unknown type":          {Collections: []CollectionSchema{{Name: "a", Type: "view"}}},
+		"index without fields":  {Collections: []CollectionSchema{{Name: "a", Indexes: []IndexSchema{{Type: driver.HashIndex}}}}},
+		"primary index":         {Collections: []CollectionSchema{{Name: "a", Indexes: []IndexSchema{{Type: driver.PrimaryIndex, Fields: []string{"_key"}}}}}},
+		"duplicate graph":       {Graphs: []GraphSchema{{Name: "g"}, {Name: "g"}}},
+		"edge without vertices": {Graphs: []GraphSchema{{Name: "g", EdgeDefinitions: []driver.EdgeDefinition{{Collection: "e"}}}}},
+		"empty view name":       {Views: []ViewSchema{{}}},
+		"duplicate view":        {Views: []ViewSchema{{Name: "v"}, {Name: "v"}}},
+		"unknown view type":     {Views: []ViewSchema{{Name: "v", Type: "other"}}},
+		"link without name":     {Views: []ViewSchema{{Name: "v", Links: map[string]ViewLinkSchema{"": {}}}}},
+	}
+	for name, s := range tests {
+		if err := s.Validate(); !driver.IsInvalidArgument(err) {
+			t.Errorf("%s: Expected InvalidArgumentError, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	velocypack "github.com/arangodb/go-velocypack"
+)
+
+// PregelAlgorithm is the name of a Pregel algorithm.
+type PregelAlgorithm string
+
+const (
+	// PregelAlgorithmPageRank computes the PageRank of all vertices.
+	PregelAlgorithmPageRank = PregelAlgorithm("pagerank")
+	// PregelAlgorithmSingleSourceShortestPath computes the distance of all vertices to a source vertex.
+	PregelAlgorithmSingleSourceShortestPath = PregelAlgorithm("sssp")
+	// PregelAlgorithmConnectedComponents computes the weakly connected components of the graph.
+	PregelAlgorithmConnectedComponents = PregelAlgorithm("connectedcomponents")
+	// PregelAlgorithmWeaklyConnectedComponents computes the weakly connected components of the graph.
+	PregelAlgorithmWeaklyConnectedComponents = PregelAlgorithm("wcc")
+	// PregelAlgorithmStronglyConnectedComponents computes the strongly connected components of the graph.
+	PregelAlgorithmStronglyConnectedComponents = PregelAlgorithm("scc")
+	// PregelAlgorithmHyperlinkInducedTopicSearch computes the hub and authority scores of all vertices.
+	PregelAlgorithmHyperlinkInducedTopicSearch = PregelAlgorithm("hits")
+	// PregelAlgorithmEffectiveCloseness computes the closeness of all vertices.
+	PregelAlgorithmEffectiveCloseness = PregelAlgorithm("effectivecloseness")
+	// PregelAlgorithmLineRank computes the LineRank of all vertices.
+	PregelAlgorithmLineRank = PregelAlgorithm("linerank")
+	// PregelAlgorithmLabelPropagation detects communities using label propagation.
+	PregelAlgorithmLabelPropagation = PregelAlgorithm("labelpropagation")
+	// PregelAlgorithmSpeakerListenerLabelPropagation detects overlapping communities.
+	PregelAlgorithmSpeakerListenerLabelPropagation = PregelAlgorithm("slpa")
+)
+
+// PregelJobOptions contains the options for starting a Pregel job.
+// Either GraphName or both VertexCollections and EdgeCollections must be set.
+type PregelJobOptions struct {
+	// Algorithm to run.
+	Algorithm PregelAlgorithm `json:"algorithm"`
+	// GraphName is the name of the graph to run the algorithm on.
+	GraphName string `json:"graphName,omitempty"`
+	// VertexCollections contains the names of the vertex collections to run the algorithm on.
+	VertexCollections []string `json:"vertexCollections,omitempty"`
+	// EdgeCollections contains the names of the edge collections to run the algorithm on.
+	EdgeCollections []string `json:"edgeCollections,omitempty"`
+	// Params contains algorithm specific parameters, e.g. `resultField`, `maxGSS` or `store`.
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// PregelJobID is the identifier of a Pregel job.
+type PregelJobID string
+
+// String returns a string representation of the job ID.
+func (id PregelJobID) String() string {
+	return string(id)
+}
+
+// UnmarshalJSON decodes a job ID that is encoded as either a string or a number.
+func (id *PregelJobID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = PregelJobID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return WithStack(err)
+	}
+	*id = PregelJobID(n.String())
+	return nil
+}
+
+// UnmarshalVPack decodes a job ID that is encoded as either a string or a number.
+func (id *PregelJobID) UnmarshalVPack(slice velocypack.Slice) error {
+	switch {
+	case slice.IsString():
+		s, err := slice.GetString()
+		if err != nil {
+			return WithStack(err)
+		}
+		*id = PregelJobID(s)
+	case slice.IsUInt():
+		n, err := slice.GetUInt()
+		if err != nil {
+			return WithStack(err)
+		}
+		*id = PregelJobID(strconv.FormatUint(n, 10))
+	case slice.IsInteger():
+		n, err := slice.GetInt()
+		if err != nil {
+			return WithStack(err)
+		}
+		*id = PregelJobID(strconv.FormatInt(n, 10))
+	default:
+		return WithStack(fmt.Errorf("Expected string or integer for pregel job ID, got %s", slice.Type()))
+	}
+	return nil
+}
+
+var _ json.Unmarshaler = (*PregelJobID)(nil)
+var _ velocypack.Unmarshaler = (*PregelJobID)(nil)
+
+// PregelJobState is the state of a Pregel job.
+type PregelJobState string
+
+const (
+	// PregelJobStateNone means the job has not started yet.
+	PregelJobStateNone = PregelJobState("none")
+	// PregelJobStateLoading means the graph is being loaded.
+	PregelJobStateLoading = PregelJobState("loading")
+	// PregelJobStateRunning means the algorithm is executing.
+	PregelJobStateRunning = PregelJobState("running")
+	// PregelJobStateStoring means the results are being written back into the collections.
+	PregelJobStateStoring = PregelJobState("storing")
+	// PregelJobStateDone means the job has finished successfully.
+	PregelJobStateDone = PregelJobState("done")
+	// PregelJobStateCanceled means the job was canceled.
+	PregelJobStateCanceled = PregelJobState("canceled")
+	// PregelJobStateFatalError means the job has failed and cannot recover.
+	PregelJobStateFatalError = PregelJobState("fatal error")
+	// PregelJobStateInError means the job is in an error state, from which it may recover.
+	PregelJobStateInError = PregelJobState("in error")
+	// PregelJobStateRecovering means the job is recovering from an error.
+	PregelJobStateRecovering = PregelJobState("recovering")
+)
+
+// IsTerminal returns true if a job in this state will not change state anymore.
+func (s PregelJobState) IsTerminal() bool {
+	switch s {
+	case PregelJobStateDone, PregelJobStateCanceled, PregelJobStateFatalError:
+		return true
+	default:
+		return false
+	}
+}
+
+// PregelJob contains the status of a Pregel job.
+type PregelJob struct {
+	// ID of the job. Only returned by ArangoDB 3.7 and higher.
+	ID PregelJobID `json:"id,omitempty"`
+	// Algorithm of the job. Only returned by ArangoDB 3.7 and higher.
+	Algorithm PregelAlgorithm `json:"algorithm,omitempty"`
+	// State of the job.
+	State PregelJobState `json:"state,omitempty"`
+	// GSS is the number of global supersteps executed.
+	GSS uint64 `json:"gss,omitempty"`
+	// TotalRuntime is the total runtime of the job in seconds.
+	TotalRuntime float64 `json:"totalRuntime,omitempty"`
+	// StartupTime is the time spent loading the graph in seconds.
+	StartupTime float64 `json:"startupTime,omitempty"`
+	// ComputationTime is the time spent running the algorithm in seconds.
+	ComputationTime float64 `json:"computationTime,omitempty"`
+	// StorageTime is the time spent storing the results in seconds.
+	StorageTime float64 `json:"storageTime,omitempty"`
+	// Aggregators contains the values of the aggregators of the algorithm.
+	Aggregators map[string]interface{} `json:"aggregators,omitempty"`
+	// SendCount is the number of messages sent.
+	SendCount uint64 `json:"sendCount,omitempty"`
+	// ReceivedCount is the number of messages received.
+	ReceivedCount uint64 `json:"receivedCount,omitempty"`
+	// VertexCount is the number of vertices in the graph.
+	VertexCount uint64 `json:"vertexCount,omitempty"`
+	// EdgeCount is the number of edges in the graph.
+	EdgeCount uint64 `json:"edgeCount,omitempty"`
+	// Reports contains reports (e.g. warnings) produced by the algorithm.
+	Reports []map[string]interface{} `json:"reports,omitempty"`
+}
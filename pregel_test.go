@@ -0,0 +1,79 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"encoding/json"
+	"testing"
+
+	velocypack "github.com/arangodb/go-velocypack"
+)
+
+func TestPregelJobIDUnmarshal(t *testing.T) {
+	tests := map[string]interface{}{ // Expected-Output : Input
+		"123":  "123",
+		"456":  456,
+		"789":  uint64(789),
+		"abcd": "abcd",
+	}
+	for expected, input := range tests {
+		encoded, err := json.Marshal(input)
+		if err != nil {
+			t.Fatalf("json.Marshal failed: %s", err)
+		}
+		var id PregelJobID
+		if err := json.Unmarshal(encoded, &id); err != nil {
+			t.Errorf("json.Unmarshal of '%s' failed: %s", string(encoded), err)
+		} else if id.String() != expected {
+			t.Errorf("json.Unmarshal: Expected '%s', got '%s'", expected, id)
+		}
+		slice, err := velocypack.Marshal(input)
+		if err != nil {
+			t.Fatalf("velocypack.Marshal failed: %s", err)
+		}
+		id = ""
+		if err := velocypack.Unmarshal(slice, &id); err != nil {
+			t.Errorf("velocypack.Unmarshal of '%v' failed: %s", input, err)
+		} else if id.String() != expected {
+			t.Errorf("velocypack.Unmarshal: Expected '%s', got '%s'", expected, id)
+		}
+	}
+}
+
+func TestPregelJobStateIsTerminal(t *testing.T) {
+	tests := map[PregelJobState]bool{
+		PregelJobStateLoading:    false,
+		PregelJobStateRunning:    false,
+		PregelJobStateStoring:    false,
+		PregelJobStateInError:    false,
+		PregelJobStateRecovering: false,
+		PregelJobStateDone:       true,
+		PregelJobStateCanceled:   true,
+		PregelJobStateFatalError: true,
+	}
+	for state, expected := range tests {
+		if state.IsTerminal() != expected {
+			t.Errorf("IsTerminal of '%s': Expected %v, got %v", state, expected, !expected)
+		}
+	}
+}
@@ -41,6 +41,7 @@ type batchMetadata struct {
 	cl       *client
 	serverID int64
 	database string
+	endpoint string // Endpoint of the server that created the batch
 	closed   int32
 }
 
@@ -72,6 +73,7 @@ func (c *client) CreateBatch(ctx context.Context, db Database, serverID int64, t
 	batch.cl = c
 	batch.serverID = serverID
 	batch.database = db.Name()
+	batch.endpoint = resp.Endpoint()
 	return &batch, nil
 }
 
@@ -126,7 +128,7 @@ func (b batchMetadata) Extend(ctx context.Context, ttl time.Duration) error {
 	if err != nil {
 		return WithStack(err)
 	}
-	resp, err := b.cl.conn.Do(ctx, req)
+	resp, err := b.cl.conn.Do(b.pinnedContext(ctx), req)
 	if err != nil {
 		return WithStack(err)
 	}
@@ -136,6 +138,14 @@ func (b batchMetadata) Extend(ctx context.Context, ttl time.Duration) error {
 	return nil
 }
 
+// pinnedContext returns a context that sends requests to the server that created the batch.
+func (b batchMetadata) pinnedContext(ctx context.Context) context.Context {
+	if b.endpoint == "" {
+		return ctx
+	}
+	return WithEndpoint(ctx, b.endpoint)
+}
+
 // Delete an existing dump batch
 func (b *batchMetadata) Delete(ctx context.Context) error {
 	if !atomic.CompareAndSwapInt32(&b.closed, 0, 1) {
@@ -146,7 +156,7 @@ func (b *batchMetadata) Delete(ctx context.Context) error {
 	if err != nil {
 		return WithStack(err)
 	}
-	resp, err := b.cl.conn.Do(ctx, req)
+	resp, err := b.cl.conn.Do(b.pinnedContext(ctx), req)
 	if err != nil {
 		return WithStack(err)
 	}
@@ -0,0 +1,98 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the default maximum number of attempts of a RetryPolicy.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialBackoff is the default delay before the first retry of a RetryPolicy.
+	DefaultRetryInitialBackoff = time.Millisecond * 100
+	// DefaultRetryMaxBackoff is the default maximum delay between retries of a RetryPolicy.
+	DefaultRetryMaxBackoff = time.Second * 5
+	// DefaultRetryBackoffMultiplier is the default factor by which the delay between retries of a RetryPolicy grows.
+	DefaultRetryBackoffMultiplier = 2.0
+	// DefaultRetryJitter is the default fraction of the delay between retries of a RetryPolicy that is randomized.
+	DefaultRetryJitter = 0.2
+)
+
+var (
+	// DefaultRetryStatusCodes holds the status codes of responses that are retried by a RetryPolicy
+	// that does not specify RetryStatusCodes.
+	DefaultRetryStatusCodes = []int{429, 503}
+)
+
+// RetryPolicy specifies if and how a cluster connection retries failed requests.
+// Retries are sent to the next server, unless the request is bound to a specific endpoint
+// (see WithEndpoint).
+// Zero values of the fields are replaced by their defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including the first time.
+	// Defaults to DefaultRetryMaxAttempts.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	// Defaults to DefaultRetryInitialBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries.
+	// Defaults to DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor by which the delay grows after every retry.
+	// Defaults to DefaultRetryBackoffMultiplier.
+	BackoffMultiplier float64
+	// Jitter is the fraction (0..1) of the delay that is randomized, to avoid clients retrying in lockstep.
+	// Defaults to DefaultRetryJitter. Set to a negative value to disable jitter.
+	Jitter float64
+	// RetryStatusCodes holds the status codes of responses that are retried.
+	// If nil, DefaultRetryStatusCodes is used.
+	RetryStatusCodes []int
+	// RetryErrorNums holds the error numbers of error responses that are retried.
+	RetryErrorNums []int
+	// RetryNonIdempotent allows retrying requests with a non-idempotent method (POST, PATCH)
+	// once they have reached a server.
+	// Requests that have not been written to the network are always retried.
+	RetryNonIdempotent bool
+}
+
+// RetryError is returned by a cluster connection when a request failed after it has been
+// retried using a RetryPolicy.
+type RetryError struct {
+	// Attempts is the number of times the request was sent.
+	Attempts int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// Error returns the error of the last attempt, including the number of attempts.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s (after %d attempts)", e.Err.Error(), e.Attempts)
+}
+
+// IsRetry returns true if the given error is a RetryError.
+func IsRetry(err error) bool {
+	_, ok := Cause(err).(*RetryError)
+	return ok
+}
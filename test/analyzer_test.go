@@ -0,0 +1,189 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// TestEnsureAnalyzers creates analyzers of all types and checks that ensuring them again reports existence.
+func TestEnsureAnalyzers(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.5", t)
+	db := ensureDatabase(nil, c, "analyzer_test", nil, t)
+	definitions := []driver.AnalyzerDefinition{
+		{
+			Name: "my-identity",
+			Type: driver.AnalyzerTypeIdentity,
+		},
+		{
+			Name:       "my-delimiter",
+			Type:       driver.AnalyzerTypeDelimiter,
+			Properties: driver.AnalyzerProperties{Delimiter: ","},
+		},
+		{
+			Name:       "my-stem",
+			Type:       driver.AnalyzerTypeStem,
+			Properties: driver.AnalyzerProperties{Locale: "en.utf-8"},
+		},
+		{
+			Name: "my-norm",
+			Type: driver.AnalyzerTypeNorm,
+			Properties: driver.AnalyzerProperties{
+				Locale: "en.utf-8",
+				Case:   driver.AnalyzerCaseLower,
+				Accent: boolRef(false),
+			},
+		},
+		{
+			Name: "my-ngram",
+			Type: driver.AnalyzerTypeNGram,
+			Properties: driver.AnalyzerProperties{
+				Min:              int64Ref(2),
+				Max:              int64Ref(3),
+				PreserveOriginal: boolRef(true),
+			},
+		},
+		{
+			Name: "my-text",
+			Type: driver.AnalyzerTypeText,
+			Properties: driver.AnalyzerProperties{
+				Locale:    "en.utf-8",
+				Case:      driver.AnalyzerCaseLower,
+				Stemming:  boolRef(false),
+				Stopwords: []string{"the", "a"},
+			},
+			Features: []driver.AnalyzerFeature{driver.AnalyzerFeatureFrequency, driver.AnalyzerFeatureNorm, driver.AnalyzerFeaturePosition},
+		},
+	}
+	for _, def := range definitions {
+		existed, a, err := db.EnsureAnalyzer(nil, def)
+		if err != nil {
+			t.Fatalf("EnsureAnalyzer('%s') failed: %s", def.Name, describe(err))
+		}
+		if existed {
+			// Left over from a previous run, remove it and create again
+			if err := a.Remove(nil, true); err != nil {
+				t.Fatalf("Remove('%s') failed: %s", def.Name, describe(err))
+			}
+			if existed, a, err = db.EnsureAnalyzer(nil, def); err != nil {
+				t.Fatalf("EnsureAnalyzer('%s') failed: %s", def.Name, describe(err))
+			} else if existed {
+				t.Errorf("Expected analyzer '%s' to be created", def.Name)
+			}
+		}
+		if a.Name() != def.Name {
+			t.Errorf("Expected name '%s', got '%s'", def.Name, a.Name())
+		}
+		if a.Type() != def.Type {
+			t.Errorf("Expected type '%s', got '%s'", def.Type, a.Type())
+		}
+		// Ensure again must report existence
+		if existed, _, err := db.EnsureAnalyzer(nil, def); err != nil {
+			t.Errorf("EnsureAnalyzer('%s') failed: %s", def.Name, describe(err))
+		} else if !existed {
+			t.Errorf("Expected analyzer '%s' to exist", def.Name)
+		}
+	}
+	// Check details of the ngram analyzer
+	a, err := db.Analyzer(nil, "my-ngram")
+	if err != nil {
+		t.Fatalf("Analyzer failed: %s", describe(err))
+	}
+	p := a.Definition().Properties
+	if p.Min == nil || *p.Min != 2 || p.Max == nil || *p.Max != 3 {
+		t.Errorf("Unexpected ngram properties %+v", p)
+	}
+	// All analyzers must be listed
+	list, err := db.Analyzers(nil)
+	if err != nil {
+		t.Fatalf("Analyzers failed: %s", describe(err))
+	}
+	for _, def := range definitions {
+		found := false
+		for _, x := range list {
+			if x.Name() == def.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected analyzer '%s' in list", def.Name)
+		}
+	}
+}
+
+// TestEnsureAnalyzerConflict checks that ensuring an analyzer with a different definition fails.
+func TestEnsureAnalyzerConflict(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.5", t)
+	db := ensureDatabase(nil, c, "analyzer_test", nil, t)
+	def := driver.AnalyzerDefinition{
+		Name:       "my-conflict",
+		Type:       driver.AnalyzerTypeDelimiter,
+		Properties: driver.AnalyzerProperties{Delimiter: ","},
+	}
+	if _, _, err := db.EnsureAnalyzer(nil, def); err != nil {
+		t.Fatalf("EnsureAnalyzer failed: %s", describe(err))
+	}
+	def.Properties.Delimiter = ";"
+	if _, _, err := db.EnsureAnalyzer(nil, def); !driver.IsConflict(err) {
+		t.Errorf("Expected ConflictError, got %s", describe(err))
+	}
+}
+
+// TestRemoveAnalyzer creates an analyzer, uses it in a view and then removes it.
+func TestRemoveAnalyzer(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.5", t)
+	db := ensureDatabase(nil, c, "analyzer_test", nil, t)
+	ensureCollection(nil, db, "analyzer_col", nil, t)
+	_, a, err := db.EnsureAnalyzer(nil, driver.AnalyzerDefinition{
+		Name:       "my-removable",
+		Type:       driver.AnalyzerTypeDelimiter,
+		Properties: driver.AnalyzerProperties{Delimiter: "-"},
+	})
+	if err != nil {
+		t.Fatalf("EnsureAnalyzer failed: %s", describe(err))
+	}
+	v := ensureArangoSearchView(nil, db, "analyzer_view", &driver.ArangoSearchViewProperties{
+		Links: driver.ArangoSearchLinks{
+			"analyzer_col": driver.ArangoSearchElementProperties{
+				Analyzers: []string{a.Name()},
+			},
+		},
+	}, t)
+	defer v.Remove(nil)
+	// Analyzer is in use, so removal must fail without force
+	if err := a.Remove(nil, false); !driver.IsConflict(err) {
+		t.Errorf("Expected ConflictError, got %s", describe(err))
+	}
+	if err := a.Remove(nil, true); err != nil {
+		t.Fatalf("Remove failed: %s", describe(err))
+	}
+	if _, err := db.Analyzer(nil, a.Name()); !driver.IsNotFound(err) {
+		t.Errorf("Expected NotFoundError, got %s", describe(err))
+	}
+}
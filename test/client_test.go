@@ -102,6 +102,16 @@ func createAuthenticationFromEnv(t testEnv) driver.Authentication {
 			t.Fatalf("Expected username & password for jwt authentication")
 		}
 		return driver.JWTAuthentication(parts[1], parts[2])
+	case "jwtsecret":
+		if len(parts) < 2 {
+			t.Fatalf("Expected secret for jwtsecret authentication")
+		}
+		return driver.JWTSecretAuthentication(strings.Join(parts[1:], ":"), nil)
+	case "jwtsecretfile":
+		if len(parts) < 2 {
+			t.Fatalf("Expected secret file for jwtsecretfile authentication")
+		}
+		return driver.JWTSecretAuthentication("", &driver.JWTSecretOptions{SecretFile: strings.Join(parts[1:], ":")})
 	default:
 		t.Fatalf("Unknown authentication: '%s'", parts[0])
 		return nil
@@ -0,0 +1,150 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// asEdgeCollection asserts that the given collection is an edge collection.
+func asEdgeCollection(col driver.Collection, t *testing.T) driver.EdgeCollection {
+	ec, ok := col.(driver.EdgeCollection)
+	if !ok {
+		t.Fatalf("Collection '%s' is not an EdgeCollection", col.Name())
+	}
+	return ec
+}
+
+// TestCollectionEdgesOnlyForEdgeCollections checks that document collections do not provide the edge functions.
+func TestCollectionEdgesOnlyForEdgeCollections(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "collection_edges_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+	col := assertCollection(nil, db, "traversal_cities", t)
+	if _, ok := col.(driver.EdgeCollection); ok {
+		t.Error("Document collection must not be an EdgeCollection")
+	}
+}
+
+// TestCollectionEdges looks up the edges of vertices.
+func TestCollectionEdges(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "collection_edges_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+	col := asEdgeCollection(assertCollection(nil, db, "traversal_routes", t), t)
+
+	// Outbound edges of A
+	var routes []RouteEdge
+	edges, err := col.Edges(nil, "traversal_cities/A", driver.EdgeDirectionOut, &routes)
+	if err != nil {
+		t.Fatalf("Edges failed: %s", describe(err))
+	}
+	if len(edges) != 2 || len(routes) != 2 {
+		t.Fatalf("Expected 2 edges, got %d, %d", len(edges), len(routes))
+	}
+	for i, e := range edges {
+		if e.From != "traversal_cities/A" {
+			t.Errorf("Expected _from 'traversal_cities/A', got '%s'", e.From)
+		}
+		if e.Key == "" || e.ID.IsEmpty() || e.Rev == "" {
+			t.Errorf("Expected meta data, got %+v", e.DocumentMeta)
+		}
+		if string(e.To) != routes[i].To {
+			t.Errorf("Expected results in same order as edges, got '%s' and '%s'", e.To, routes[i].To)
+		}
+	}
+
+	// Inbound edges of C
+	if count, err := col.CountEdges(nil, "traversal_cities/C", driver.EdgeDirectionIn); err != nil {
+		t.Errorf("CountEdges failed: %s", describe(err))
+	} else if count != 2 {
+		t.Errorf("Expected 2 edges, got %d", count)
+	}
+
+	// Any edges of C
+	if edges, err := col.Edges(nil, "traversal_cities/C", driver.EdgeDirectionAny, nil); err != nil {
+		t.Errorf("Edges failed: %s", describe(err))
+	} else if len(edges) != 3 {
+		t.Errorf("Expected 3 edges, got %d", len(edges))
+	}
+
+	// Invalid vertex
+	if _, err := col.Edges(nil, "invalid", driver.EdgeDirectionAny, nil); !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %s", describe(err))
+	}
+}
+
+// TestCollectionEdgesOfVertices looks up the edges of multiple vertices at once.
+func TestCollectionEdgesOfVertices(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "collection_edges_test", nil, t)
+	g := ensureRouteGraph(nil, db, t)
+	ec, _, err := g.EdgeCollection(nil, "traversal_routes")
+	if err != nil {
+		t.Fatalf("EdgeCollection failed: %s", describe(err))
+	}
+	col := asEdgeCollection(ec, t)
+
+	var routes []RouteEdge
+	edges, err := col.EdgesOfVertices(nil, []driver.DocumentID{"traversal_cities/A", "traversal_cities/B"}, driver.EdgeDirectionOut, &routes)
+	if err != nil {
+		t.Fatalf("EdgesOfVertices failed: %s", describe(err))
+	}
+	if len(edges) != 3 || len(routes) != 3 {
+		t.Errorf("Expected 3 edges, got %d, %d", len(edges), len(routes))
+	}
+
+	if edges, err := col.EdgesOfVertices(nil, nil, driver.EdgeDirectionOut, nil); err != nil {
+		t.Errorf("EdgesOfVertices failed: %s", describe(err))
+	} else if len(edges) != 0 {
+		t.Errorf("Expected 0 edges, got %d", len(edges))
+	}
+}
+
+// TestCollectionEdgesVertexCollections filters edges on the collection of the other vertex.
+func TestCollectionEdgesVertexCollections(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "collection_edges_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+	col := asEdgeCollection(assertCollection(nil, db, "traversal_routes", t), t)
+
+	ctx := driver.WithEdgesVertexCollections(nil, "traversal_cities")
+	if edges, err := col.Edges(ctx, "traversal_cities/A", driver.EdgeDirectionOut, nil); err != nil {
+		t.Errorf("Edges failed: %s", describe(err))
+	} else if len(edges) != 2 {
+		t.Errorf("Expected 2 edges, got %d", len(edges))
+	}
+	if count, err := col.CountEdges(ctx, "traversal_cities/A", driver.EdgeDirectionOut); err != nil {
+		t.Errorf("CountEdges failed: %s", describe(err))
+	} else if count != 2 {
+		t.Errorf("Expected 2 edges, got %d", count)
+	}
+	ctx = driver.WithEdgesVertexCollections(nil, "other_collection")
+	if count, err := col.CountEdges(ctx, "traversal_cities/A", driver.EdgeDirectionOut); err != nil {
+		t.Errorf("CountEdges failed: %s", describe(err))
+	} else if count != 0 {
+		t.Errorf("Expected 0 edges, got %d", count)
+	}
+}
@@ -136,3 +136,42 @@ func TestDatabaseInfo(t *testing.T) {
 		t.Fatalf("Failed to remove database: %s", describe(err))
 	}
 }
+
+// TestCreateDatabaseWithOptions tests CreateDatabase with default collection options.
+func TestCreateDatabaseWithOptions(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.6", t)
+	if _, err := c.Cluster(ctx); driver.IsPreconditionFailed(err) {
+		t.Skip("Not a cluster")
+	}
+
+	name := "create_options_test"
+	d, err := c.CreateDatabase(ctx, name, &driver.CreateDatabaseOptions{
+		Options: driver.CreateDatabaseDefaultOptions{
+			ReplicationFactor: 2,
+			WriteConcern:      2,
+			Sharding:          driver.DatabaseShardingSingle,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create database '%s': %s", name, describe(err))
+	}
+	info, err := d.Info(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get %s database info: %s", name, describe(err))
+	}
+	if info.ReplicationFactor != 2 {
+		t.Errorf("Invalid ReplicationFactor. Got %d, expected 2", info.ReplicationFactor)
+	}
+	if info.WriteConcern != 2 {
+		t.Errorf("Invalid WriteConcern. Got %d, expected 2", info.WriteConcern)
+	}
+	if info.Sharding != driver.DatabaseShardingSingle {
+		t.Errorf("Invalid Sharding. Got '%s', expected '%s'", info.Sharding, driver.DatabaseShardingSingle)
+	}
+	// Cleanup
+	if err := d.Remove(ctx); err != nil {
+		t.Errorf("Failed to remove database '%s': %s", name, describe(err))
+	}
+}
@@ -0,0 +1,152 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/dump"
+)
+
+// TestDumpRestore dumps a database and restores it into another database.
+func TestDumpRestore(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	src := ensureDatabase(ctx, c, "dump_test_source", nil, t)
+	dst := ensureDatabase(ctx, c, "dump_test_target", nil, t)
+	ensureRouteGraph(ctx, src, t)
+	users := ensureCollection(ctx, src, "dump_users", nil, t)
+	if _, _, err := users.EnsureHashIndex(ctx, []string{"name"}, &driver.EnsureHashIndexOptions{Unique: true}); err != nil {
+		t.Fatalf("EnsureHashIndex failed: %s", describe(err))
+	}
+	docs := []UserDocWithKey{
+		{Key: "jan", Name: "Jan"},
+		{Key: "piet", Name: "Piet"},
+		{Key: "klaas", Name: "Klaas"},
+	}
+	if _, err := users.ImportDocuments(ctx, docs, &driver.ImportDocumentOptions{Overwrite: true, Complete: true}); err != nil {
+		t.Fatalf("ImportDocuments failed: %s", describe(err))
+	}
+
+	for _, compress := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "dump-test")
+		if err != nil {
+			t.Fatalf("TempDir failed: %s", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := dump.Dump(ctx, src, dir, &dump.DumpOptions{Compress: compress, BatchSize: 2}); err != nil {
+			t.Fatalf("Dump failed: %s", describe(err))
+		}
+		var mutex sync.Mutex
+		restored := make(map[string]int64)
+		if err := dump.Restore(ctx, dst, dir, &dump.RestoreOptions{
+			Overwrite: true,
+			BatchSize: 2,
+			Progress: func(p dump.Progress) {
+				if p.Done {
+					mutex.Lock()
+					restored[p.Collection] = p.Documents
+					mutex.Unlock()
+				}
+			},
+		}); err != nil {
+			t.Fatalf("Restore failed: %s", describe(err))
+		}
+		if restored["dump_users"] != 3 {
+			t.Errorf("Expected 3 restored users, got %d", restored["dump_users"])
+		}
+
+		col, err := dst.Collection(ctx, "dump_users")
+		if err != nil {
+			t.Fatalf("Collection failed: %s", describe(err))
+		}
+		var doc UserDocWithKey
+		if _, err := col.ReadDocument(ctx, "piet", &doc); err != nil {
+			t.Errorf("ReadDocument failed: %s", describe(err))
+		} else if doc.Name != "Piet" {
+			t.Errorf("Expected name 'Piet', got '%s'", doc.Name)
+		}
+		indexes, err := col.Indexes(ctx)
+		if err != nil {
+			t.Fatalf("Indexes failed: %s", describe(err))
+		}
+		found := false
+		for _, idx := range indexes {
+			if idx.Type() == driver.HashIndex && idx.Unique() && len(idx.Fields()) == 1 && idx.Fields()[0] == "name" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Unique hash index on 'name' not restored")
+		}
+		routes, err := dst.Collection(ctx, "traversal_routes")
+		if err != nil {
+			t.Fatalf("Collection failed: %s", describe(err))
+		}
+		if count, err := routes.Count(ctx); err != nil {
+			t.Errorf("Count failed: %s", describe(err))
+		} else if count != 4 {
+			t.Errorf("Expected 4 routes, got %d", count)
+		}
+		if found, err := dst.GraphExists(ctx, "traversal_test"); err != nil {
+			t.Errorf("GraphExists failed: %s", describe(err))
+		} else if !found {
+			t.Errorf("Graph 'traversal_test' not restored")
+		}
+	}
+}
+
+// TestDumpFilter dumps a subset of the collections of a database.
+func TestDumpFilter(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(ctx, c, "dump_test_source", nil, t)
+	ensureCollection(ctx, db, "dump_included", nil, t)
+	ensureCollection(ctx, db, "dump_excluded", nil, t)
+
+	dir, err := ioutil.TempDir("", "dump-test")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := dump.Dump(ctx, db, dir, &dump.DumpOptions{
+		Filter:     dump.Filter{Include: []string{"dump_included", "dump_excluded"}, Exclude: []string{"dump_excluded"}},
+		SkipGraphs: true,
+	}); err != nil {
+		t.Fatalf("Dump failed: %s", describe(err))
+	}
+	if _, err := os.Stat(dir + "/dump_included.structure.json"); err != nil {
+		t.Errorf("Expected structure file of included collection: %s", err)
+	}
+	for _, name := range []string{"dump_excluded.structure.json", "_graphs.data.json"} {
+		if _, err := os.Stat(dir + "/" + name); !os.IsNotExist(err) {
+			t.Errorf("Expected no %s, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// createFoxxBundle creates a zip bundle of a minimal Foxx service.
+func createFoxxBundle(t *testing.T) *bytes.Reader {
+	files := map[string]string{
+		"manifest.json": `{
+			"name": "foxx-test",
+			"version": "1.0.0",
+			"main": "index.js",
+			"engines": {"arangodb": "^3.0.0"},
+			"configuration": {"greeting": {"type": "string", "default": "hello"}},
+			"scripts": {"greet": {"title": "Greet", "filename": "greet.js"}},
+			"tests": "test.js"
+		}`,
+		"index.js": `'use strict';
+const createRouter = require('@arangodb/foxx/router');
+const router = createRouter();
+module.context.use(router);
+router.get('/hello', (req, res) => { res.send({greeting: module.context.configuration.greeting}); });
+`,
+		"greet.js": `'use strict';
+module.exports = {greeting: module.context.configuration.greeting + ' ' + module.context.argv[0]};
+`,
+		"test.js": `'use strict';
+const expect = require('chai').expect;
+describe('greeting', () => {
+	it('is configured', () => { expect(module.context.configuration.greeting).to.be.a('string'); });
+});
+`,
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %s", err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write zip entry: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %s", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+// TestFoxxService tests the lifecycle of a Foxx service.
+func TestFoxxService(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(ctx, c, "foxx_test", nil, t)
+	foxx := db.Foxx()
+	mount := "/foxx-test"
+
+	// Cleanup from previous runs
+	if err := foxx.Uninstall(ctx, mount, nil); err != nil && !driver.IsNotFound(err) {
+		t.Fatalf("Uninstall failed: %s", describe(err))
+	}
+
+	svc, err := foxx.Install(ctx, mount, createFoxxBundle(t), nil)
+	if err != nil {
+		t.Fatalf("Install failed: %s", describe(err))
+	}
+	if svc.Mount != mount {
+		t.Errorf("Expected mount '%s', got '%s'", mount, svc.Mount)
+	}
+	if _, err := foxx.Install(ctx, mount, createFoxxBundle(t), nil); !driver.IsConflict(err) {
+		t.Errorf("Expected ConflictError, got %s", describe(err))
+	}
+
+	services, err := foxx.Services(ctx, true)
+	if err != nil {
+		t.Fatalf("Services failed: %s", describe(err))
+	}
+	found := false
+	for _, s := range services {
+		if s.Mount == mount {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Service '%s' not found in %+v", mount, services)
+	}
+
+	svc, err = foxx.Service(ctx, mount)
+	if err != nil {
+		t.Fatalf("Service failed: %s", describe(err))
+	}
+	if svc.Name != "foxx-test" || svc.Version != "1.0.0" {
+		t.Errorf("Unexpected service %+v", svc)
+	}
+
+	if err := foxx.SetConfiguration(ctx, mount, map[string]interface{}{"greeting": "hi"}, false); err != nil {
+		t.Fatalf("SetConfiguration failed: %s", describe(err))
+	}
+	config, err := foxx.Configuration(ctx, mount)
+	if err != nil {
+		t.Fatalf("Configuration failed: %s", describe(err))
+	}
+	if current := config["greeting"].Current; current != "hi" {
+		t.Errorf("Expected greeting 'hi', got %v", current)
+	}
+	if deps, err := foxx.Dependencies(ctx, mount); err != nil {
+		t.Errorf("Dependencies failed: %s", describe(err))
+	} else if len(deps) != 0 {
+		t.Errorf("Expected no dependencies, got %+v", deps)
+	}
+
+	scripts, err := foxx.Scripts(ctx, mount)
+	if err != nil {
+		t.Fatalf("Scripts failed: %s", describe(err))
+	}
+	if scripts["greet"] != "Greet" {
+		t.Errorf("Unexpected scripts %+v", scripts)
+	}
+	var greeting struct {
+		Greeting string `json:"greeting"`
+	}
+	if err := foxx.RunScript(ctx, mount, "greet", []string{"world"}, &greeting); err != nil {
+		t.Fatalf("RunScript failed: %s", describe(err))
+	}
+	if greeting.Greeting != "hi world" {
+		t.Errorf("Expected 'hi world', got '%s'", greeting.Greeting)
+	}
+
+	var report driver.FoxxTestReport
+	if err := foxx.RunTests(ctx, mount, nil, &report); err != nil {
+		t.Fatalf("RunTests failed: %s", describe(err))
+	}
+	if report.Stats.Tests != 1 || report.Stats.Failures != 0 {
+		t.Errorf("Unexpected test report %+v", report.Stats)
+	}
+
+	if svc, err := foxx.EnableDevelopment(ctx, mount); err != nil {
+		t.Errorf("EnableDevelopment failed: %s", describe(err))
+	} else if !svc.Development {
+		t.Errorf("Expected development mode")
+	}
+	if svc, err := foxx.DisableDevelopment(ctx, mount); err != nil {
+		t.Errorf("DisableDevelopment failed: %s", describe(err))
+	} else if svc.Development {
+		t.Errorf("Expected production mode")
+	}
+
+	// Upgrade keeps the configuration, replace resets it.
+	if _, err := foxx.Upgrade(ctx, mount, createFoxxBundle(t), nil); err != nil {
+		t.Fatalf("Upgrade failed: %s", describe(err))
+	}
+	if config, err := foxx.Configuration(ctx, mount); err != nil {
+		t.Errorf("Configuration failed: %s", describe(err))
+	} else if current := config["greeting"].Current; current != "hi" {
+		t.Errorf("Expected greeting 'hi' after upgrade, got %v", current)
+	}
+	if _, err := foxx.Replace(ctx, mount, createFoxxBundle(t), &driver.FoxxInstallOptions{
+		Configuration: map[string]interface{}{"greeting": "hey"},
+	}); err != nil {
+		t.Fatalf("Replace failed: %s", describe(err))
+	}
+	if config, err := foxx.Configuration(ctx, mount); err != nil {
+		t.Errorf("Configuration failed: %s", describe(err))
+	} else if current := config["greeting"].Current; current != "hey" {
+		t.Errorf("Expected greeting 'hey' after replace, got %v", current)
+	}
+
+	if err := foxx.Uninstall(ctx, mount, nil); err != nil {
+		t.Fatalf("Uninstall failed: %s", describe(err))
+	}
+	if _, err := foxx.Service(ctx, mount); !driver.IsNotFound(err) {
+		t.Errorf("Expected NotFoundError, got %s", describe(err))
+	}
+}
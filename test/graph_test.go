@@ -107,3 +107,135 @@ func TestRemoveGraph(t *testing.T) {
 		t.Errorf("GraphExists('%s') return true, expected false", name)
 	}
 }
+
+// TestGraphInfo creates a graph and then checks its info.
+func TestGraphInfo(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "graph_test", nil, t)
+	name := "test_graph_info"
+	options := &driver.CreateGraphOptions{
+		EdgeDefinitions: []driver.EdgeDefinition{
+			{Collection: "info_edges", From: []string{"info_from"}, To: []string{"info_to"}},
+		},
+		OrphanVertexCollections: []string{"info_orphan"},
+	}
+	g, err := db.CreateGraph(nil, name, options)
+	if err != nil {
+		t.Fatalf("Failed to create graph '%s': %s", name, describe(err))
+	}
+	defer g.RemoveWithOptions(nil, &driver.RemoveGraphOptions{DropCollections: true})
+	info, err := g.Info(nil)
+	if err != nil {
+		t.Fatalf("Info failed: %s", describe(err))
+	}
+	if info.Name != name {
+		t.Errorf("Expected name '%s', got '%s'", name, info.Name)
+	}
+	if len(info.EdgeDefinitions) != 1 {
+		t.Fatalf("Expected 1 edge definition, got %d", len(info.EdgeDefinitions))
+	}
+	if def := info.EdgeDefinitions[0]; def.Collection != "info_edges" || len(def.From) != 1 || def.From[0] != "info_from" || len(def.To) != 1 || def.To[0] != "info_to" {
+		t.Errorf("Unexpected edge definition %+v", def)
+	}
+	if len(info.OrphanCollections) != 1 || info.OrphanCollections[0] != "info_orphan" {
+		t.Errorf("Unexpected orphan collections %v", info.OrphanCollections)
+	}
+}
+
+// TestGraphEdgeDefinitions replaces and removes edge definitions and vertex collections of a graph.
+func TestGraphEdgeDefinitions(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "graph_test", nil, t)
+	name := "test_graph_edge_definitions"
+	options := &driver.CreateGraphOptions{
+		EdgeDefinitions: []driver.EdgeDefinition{
+			{Collection: "def_edges", From: []string{"def_a"}, To: []string{"def_b"}},
+		},
+		OrphanVertexCollections: []string{"def_orphan"},
+	}
+	g, err := db.CreateGraph(nil, name, options)
+	if err != nil {
+		t.Fatalf("Failed to create graph '%s': %s", name, describe(err))
+	}
+	defer g.RemoveWithOptions(nil, &driver.RemoveGraphOptions{DropCollections: true})
+
+	// Replace edge definition
+	if err := g.ReplaceEdgeDefinition(nil, driver.EdgeDefinition{Collection: "def_edges", From: []string{"def_a"}, To: []string{"def_a"}}); err != nil {
+		t.Fatalf("ReplaceEdgeDefinition failed: %s", describe(err))
+	}
+	if _, constraints, err := g.EdgeCollection(nil, "def_edges"); err != nil {
+		t.Errorf("EdgeCollection failed: %s", describe(err))
+	} else if len(constraints.To) != 1 || constraints.To[0] != "def_a" {
+		t.Errorf("Unexpected constraints %+v", constraints)
+	}
+
+	// Remove orphan vertex collection and drop it
+	if err := g.RemoveVertexCollection(nil, "def_orphan", true); err != nil {
+		t.Fatalf("RemoveVertexCollection failed: %s", describe(err))
+	}
+	if found, err := g.VertexCollectionExists(nil, "def_orphan"); err != nil {
+		t.Errorf("VertexCollectionExists failed: %s", describe(err))
+	} else if found {
+		t.Errorf("Expected vertex collection 'def_orphan' to be removed from graph")
+	}
+	if found, err := db.CollectionExists(nil, "def_orphan"); err != nil {
+		t.Errorf("CollectionExists failed: %s", describe(err))
+	} else if found {
+		t.Errorf("Expected collection 'def_orphan' to be dropped")
+	}
+
+	// Remove edge definition, but keep the collection
+	if err := g.RemoveEdgeDefinition(nil, "def_edges", false); err != nil {
+		t.Fatalf("RemoveEdgeDefinition failed: %s", describe(err))
+	}
+	if found, err := g.EdgeCollectionExists(nil, "def_edges"); err != nil {
+		t.Errorf("EdgeCollectionExists failed: %s", describe(err))
+	} else if found {
+		t.Errorf("Expected edge collection 'def_edges' to be removed from graph")
+	}
+	if found, err := db.CollectionExists(nil, "def_edges"); err != nil {
+		t.Errorf("CollectionExists failed: %s", describe(err))
+	} else if !found {
+		t.Errorf("Expected collection 'def_edges' to be kept")
+	}
+	// Former vertex collections become orphans
+	info, err := g.Info(nil)
+	if err != nil {
+		t.Fatalf("Info failed: %s", describe(err))
+	}
+	if len(info.EdgeDefinitions) != 0 {
+		t.Errorf("Expected no edge definitions, got %d", len(info.EdgeDefinitions))
+	}
+	if len(info.OrphanCollections) != 2 {
+		t.Errorf("Expected 2 orphan collections, got %v", info.OrphanCollections)
+	}
+	if col, err := db.Collection(nil, "def_edges"); err == nil {
+		col.Remove(nil)
+	}
+}
+
+// TestRemoveGraphWithCollections creates a graph and then removes it, including its collections.
+func TestRemoveGraphWithCollections(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "graph_test", nil, t)
+	name := "test_remove_graph_with_collections"
+	options := &driver.CreateGraphOptions{
+		EdgeDefinitions: []driver.EdgeDefinition{
+			{Collection: "rm_edges", From: []string{"rm_vertices"}, To: []string{"rm_vertices"}},
+		},
+	}
+	g, err := db.CreateGraph(nil, name, options)
+	if err != nil {
+		t.Fatalf("Failed to create graph '%s': %s", name, describe(err))
+	}
+	if err := g.RemoveWithOptions(nil, &driver.RemoveGraphOptions{DropCollections: true}); err != nil {
+		t.Fatalf("Failed to remove graph '%s': %s", name, describe(err))
+	}
+	for _, colName := range []string{"rm_edges", "rm_vertices"} {
+		if found, err := db.CollectionExists(nil, colName); err != nil {
+			t.Errorf("CollectionExists('%s') failed: %s", colName, describe(err))
+		} else if found {
+			t.Errorf("CollectionExists('%s') return true, expected false", colName)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// ensureRouteGraph creates a small graph of cities connected by routes:
+// A->B (1), B->C (1), A->C (5), C->D (1).
+func ensureRouteGraph(ctx context.Context, db driver.Database, t *testing.T) driver.Graph {
+	g := ensureGraph(ctx, db, "traversal_test", &driver.CreateGraphOptions{
+		EdgeDefinitions: []driver.EdgeDefinition{
+			{Collection: "traversal_routes", From: []string{"traversal_cities"}, To: []string{"traversal_cities"}},
+		},
+	}, t)
+	cities := ensureCollection(ctx, db, "traversal_cities", nil, t)
+	routes := ensureCollection(ctx, db, "traversal_routes", &driver.CreateCollectionOptions{Type: driver.CollectionTypeEdge}, t)
+	docs := []UserDocWithKey{
+		{Key: "A", Name: "A"},
+		{Key: "B", Name: "B"},
+		{Key: "C", Name: "C"},
+		{Key: "D", Name: "D"},
+	}
+	if _, err := cities.ImportDocuments(ctx, docs, &driver.ImportDocumentOptions{Overwrite: true, Complete: true}); err != nil {
+		t.Fatalf("Failed to import cities: %s", describe(err))
+	}
+	edges := []RouteEdge{
+		{From: "traversal_cities/A", To: "traversal_cities/B", Distance: 1},
+		{From: "traversal_cities/B", To: "traversal_cities/C", Distance: 1},
+		{From: "traversal_cities/A", To: "traversal_cities/C", Distance: 5},
+		{From: "traversal_cities/C", To: "traversal_cities/D", Distance: 1},
+	}
+	if _, err := routes.ImportDocuments(ctx, edges, &driver.ImportDocumentOptions{Overwrite: true, Complete: true}); err != nil {
+		t.Fatalf("Failed to import routes: %s", describe(err))
+	}
+	return g
+}
+
+// readAllPaths reads all paths from the given cursor and returns their vertex IDs.
+func readAllPaths(ctx context.Context, cursor driver.PathCursor, t *testing.T) [][]driver.DocumentID {
+	defer cursor.Close()
+	var result [][]driver.DocumentID
+	for cursor.HasMore() {
+		p, err := cursor.ReadPath(ctx)
+		if err != nil {
+			t.Fatalf("ReadPath failed: %s", describe(err))
+		}
+		ids, err := p.VertexIDs()
+		if err != nil {
+			t.Fatalf("VertexIDs failed: %s", describe(err))
+		}
+		if len(ids) != p.Length()+1 {
+			t.Errorf("Expected %d vertices, got %d", p.Length()+1, len(ids))
+		}
+		result = append(result, ids)
+	}
+	return result
+}
+
+// TestGraphTraverse performs traversals on a named graph.
+func TestGraphTraverse(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "graph_traversal_test", nil, t)
+	g := ensureRouteGraph(nil, db, t)
+
+	cursor, err := g.Traverse(nil, "traversal_cities/A", driver.TraversalOptions{MinDepth: 1, MaxDepth: 3})
+	if err != nil {
+		t.Fatalf("Traverse failed: %s", describe(err))
+	}
+	if paths := readAllPaths(nil, cursor, t); len(paths) != 5 {
+		t.Errorf("Expected 5 paths, got %d (%v)", len(paths), paths)
+	}
+
+	// Only follow short routes
+	cursor, err = g.Traverse(nil, "traversal_cities/A", driver.TraversalOptions{
+		MinDepth:    1,
+		MaxDepth:    3,
+		Order:       driver.TraversalOrderBFS,
+		UniqueEdges: driver.TraversalUniquenessPath,
+		EdgeFilters: []driver.TraversalEdgeFilter{{Attribute: "distance", Value: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Traverse failed: %s", describe(err))
+	}
+	paths := readAllPaths(nil, cursor, t)
+	if len(paths) != 3 {
+		t.Fatalf("Expected 3 paths, got %d (%v)", len(paths), paths)
+	}
+	// BFS returns the shortest paths first
+	if len(paths[0]) != 2 || paths[0][1] != "traversal_cities/B" {
+		t.Errorf("Unexpected first path %v", paths[0])
+	}
+
+	// Inbound from D with global vertex uniqueness visits every city once
+	cursor, err = g.Traverse(nil, "traversal_cities/D", driver.TraversalOptions{
+		Direction:      driver.TraversalDirectionInbound,
+		MaxDepth:       3,
+		Order:          driver.TraversalOrderBFS,
+		UniqueVertices: driver.TraversalUniquenessGlobal,
+	})
+	if err != nil {
+		t.Fatalf("Traverse failed: %s", describe(err))
+	}
+	if paths := readAllPaths(nil, cursor, t); len(paths) != 4 {
+		t.Errorf("Expected 4 paths, got %d (%v)", len(paths), paths)
+	}
+
+	// Invalid arguments
+	if _, err := g.Traverse(nil, "invalid", driver.TraversalOptions{}); !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %s", describe(err))
+	}
+	if _, err := g.Traverse(nil, "traversal_cities/A", driver.TraversalOptions{UniqueEdges: driver.TraversalUniquenessGlobal}); !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %s", describe(err))
+	}
+}
+
+// TestGraphShortestPath finds shortest paths in a named graph.
+func TestGraphShortestPath(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "graph_traversal_test", nil, t)
+	g := ensureRouteGraph(nil, db, t)
+
+	p, err := g.ShortestPath(nil, "traversal_cities/A", "traversal_cities/D", nil)
+	if err != nil {
+		t.Fatalf("ShortestPath failed: %s", describe(err))
+	}
+	if p.Length() != 2 {
+		t.Errorf("Expected path of length 2, got %d", p.Length())
+	}
+	var city UserDocWithKey
+	if _, err := p.ReadVertex(1, &city); err != nil {
+		t.Errorf("ReadVertex failed: %s", describe(err))
+	} else if city.Name != "C" {
+		t.Errorf("Expected city C, got '%s'", city.Name)
+	}
+	var route RouteEdge
+	if _, err := p.ReadEdge(0, &route); err != nil {
+		t.Errorf("ReadEdge failed: %s", describe(err))
+	} else if route.Distance != 5 {
+		t.Errorf("Expected distance 5, got %d", route.Distance)
+	}
+
+	// Weighted
+	p, err = g.ShortestPath(nil, "traversal_cities/A", "traversal_cities/D", &driver.ShortestPathOptions{WeightAttribute: "distance"})
+	if err != nil {
+		t.Fatalf("ShortestPath failed: %s", describe(err))
+	}
+	if p.Length() != 3 {
+		t.Errorf("Expected path of length 3, got %d", p.Length())
+	}
+
+	// No path
+	if _, err := g.ShortestPath(nil, "traversal_cities/D", "traversal_cities/A", nil); !driver.IsNotFound(err) {
+		t.Errorf("Expected NotFoundError, got %s", describe(err))
+	}
+}
+
+// TestGraphKShortestPaths finds the k shortest paths in a named graph.
+func TestGraphKShortestPaths(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.5", t)
+	db := ensureDatabase(nil, c, "graph_traversal_test", nil, t)
+	g := ensureRouteGraph(nil, db, t)
+
+	cursor, err := g.KShortestPaths(nil, "traversal_cities/A", "traversal_cities/D", 5, nil)
+	if err != nil {
+		t.Fatalf("KShortestPaths failed: %s", describe(err))
+	}
+	paths := readAllPaths(nil, cursor, t)
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 paths, got %d (%v)", len(paths), paths)
+	}
+	if len(paths[0]) != 3 || len(paths[1]) != 4 {
+		t.Errorf("Unexpected paths %v", paths)
+	}
+}
+
+// TestAnonymousGraphTraversal performs traversals on a list of edge collections.
+func TestAnonymousGraphTraversal(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "graph_traversal_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+
+	ag, err := db.AnonymousGraph("traversal_routes")
+	if err != nil {
+		t.Fatalf("AnonymousGraph failed: %s", describe(err))
+	}
+	cursor, err := ag.Traverse(nil, "traversal_cities/A", driver.TraversalOptions{MinDepth: 1, MaxDepth: 3})
+	if err != nil {
+		t.Fatalf("Traverse failed: %s", describe(err))
+	}
+	if paths := readAllPaths(nil, cursor, t); len(paths) != 5 {
+		t.Errorf("Expected 5 paths, got %d (%v)", len(paths), paths)
+	}
+	p, err := ag.ShortestPath(nil, "traversal_cities/A", "traversal_cities/D", &driver.ShortestPathOptions{WeightAttribute: "distance"})
+	if err != nil {
+		t.Fatalf("ShortestPath failed: %s", describe(err))
+	}
+	if p.Length() != 3 {
+		t.Errorf("Expected path of length 3, got %d", p.Length())
+	}
+	if _, err := db.AnonymousGraph(); !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %s", describe(err))
+	}
+}
@@ -0,0 +1,96 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/graphio"
+)
+
+// TestGraphExportImport exports a graph and imports it again under a different name.
+func TestGraphExportImport(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(ctx, c, "graph_test", nil, t)
+	ensureRouteGraph(ctx, db, t)
+
+	for _, format := range []graphio.Format{graphio.FormatJSON, graphio.FormatGraphML} {
+		var buf bytes.Buffer
+		if err := graphio.Export(ctx, db, "traversal_test", &buf, format); err != nil {
+			t.Fatalf("Export (%s) failed: %s", format, describe(err))
+		}
+		suffix := "_" + string(format)
+		g, err := graphio.Import(ctx, db, &buf, format, &graphio.ImportOptions{
+			GraphName: "traversal_copy" + suffix,
+			CollectionNames: map[string]string{
+				"traversal_cities": "copy_cities" + suffix,
+				"traversal_routes": "copy_routes" + suffix,
+			},
+			UseExistingGraph: true,
+			Overwrite:        true,
+		})
+		if err != nil {
+			t.Fatalf("Import (%s) failed: %s", format, describe(err))
+		}
+		info, err := g.Info(ctx)
+		if err != nil {
+			t.Fatalf("Info failed: %s", describe(err))
+		}
+		if len(info.EdgeDefinitions) != 1 || info.EdgeDefinitions[0].Collection != "copy_routes"+suffix {
+			t.Errorf("Unexpected edge definitions %+v", info.EdgeDefinitions)
+		}
+		cities := ensureCollection(ctx, db, "copy_cities"+suffix, nil, t)
+		if count, err := cities.Count(ctx); err != nil {
+			t.Errorf("Count failed: %s", describe(err))
+		} else if count != 4 {
+			t.Errorf("Expected 4 cities, got %d", count)
+		}
+		routes := ensureCollection(ctx, db, "copy_routes"+suffix, nil, t)
+		var edge RouteEdge
+		if _, err := routes.ReadDocument(ctx, findRouteKey(ctx, db, "copy_routes"+suffix, t), &edge); err != nil {
+			t.Fatalf("ReadDocument failed: %s", describe(err))
+		}
+		if edge.From != "copy_cities"+suffix+"/C" || edge.To != "copy_cities"+suffix+"/D" {
+			t.Errorf("Edge not re-targeted: %+v", edge)
+		}
+	}
+}
+
+// findRouteKey returns the key of the C->D route in the given collection.
+func findRouteKey(ctx context.Context, db driver.Database, collection string, t *testing.T) string {
+	query := "FOR e IN @@col FILTER e.distance == 1 && e._to LIKE '%/D' RETURN e._key"
+	cur, err := db.Query(ctx, query, map[string]interface{}{"@col": collection})
+	if err != nil {
+		t.Fatalf("Query failed: %s", describe(err))
+	}
+	defer cur.Close()
+	var key string
+	if _, err := cur.ReadDocument(ctx, &key); err != nil {
+		t.Fatalf("ReadDocument failed: %s", describe(err))
+	}
+	return key
+}
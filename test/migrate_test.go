@@ -0,0 +1,171 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/migrate"
+)
+
+// TestMigrateSync creates a schema in an empty database and checks that a second sync is a no-op.
+func TestMigrateSync(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "migrate_test", nil, t)
+	schema := migrate.Schema{
+		Collections: []migrate.CollectionSchema{
+			{
+				Name: "migrate_persons",
+				Indexes: []migrate.IndexSchema{
+					{Type: driver.HashIndex, Fields: []string{"email"}, Unique: true},
+					{Type: driver.SkipListIndex, Fields: []string{"age"}},
+				},
+			},
+			{Name: "migrate_knows", Type: migrate.CollectionTypeEdge},
+		},
+		Graphs: []migrate.GraphSchema{
+			{
+				Name: "migrate_social",
+				EdgeDefinitions: []driver.EdgeDefinition{
+					{Collection: "migrate_knows", From: []string{"migrate_persons"}, To: []string{"migrate_persons"}},
+				},
+			},
+		},
+	}
+	plan, err := migrate.Sync(nil, db, schema)
+	if err != nil {
+		t.Fatalf("Sync failed: %s", describe(err))
+	}
+	if plan.IsEmpty() {
+		t.Error("Expected first plan to contain steps")
+	}
+	// All indexes must exist now
+	col := assertCollection(nil, db, "migrate_persons", t)
+	if idxs, err := col.Indexes(nil); err != nil {
+		t.Errorf("Indexes failed: %s", describe(err))
+	} else if len(idxs) != 3 {
+		t.Errorf("Expected 3 indexes, got %d", len(idxs))
+	}
+	// Graph must exist now
+	if found, err := db.GraphExists(nil, "migrate_social"); err != nil {
+		t.Errorf("GraphExists failed: %s", describe(err))
+	} else if !found {
+		t.Error("Expected graph to exist")
+	}
+	// A second plan must be empty
+	plan, err = migrate.BuildPlan(nil, db, schema)
+	if err != nil {
+		t.Fatalf("BuildPlan failed: %s", describe(err))
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+}
+
+// TestMigrateViews creates a view with a schema and checks that links are added to it later.
+func TestMigrateViews(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "migrate_test", nil, t)
+	yes := true
+	schema := migrate.Schema{
+		Collections: []migrate.CollectionSchema{{Name: "migrate_articles"}, {Name: "migrate_comments"}},
+		Views: []migrate.ViewSchema{
+			{
+				Name: "migrate_search",
+				Links: map[string]migrate.ViewLinkSchema{
+					"migrate_articles": {IncludeAllFields: &yes},
+				},
+			},
+		},
+	}
+	if _, err := migrate.Sync(nil, db, schema); err != nil {
+		t.Fatalf("Sync failed: %s", describe(err))
+	}
+	// Add a link
+	schema.Views[0].Links["migrate_comments"] = migrate.ViewLinkSchema{
+		Fields: map[string]migrate.ViewLinkSchema{"text": {Analyzers: []string{"text_en"}}},
+	}
+	plan, err := migrate.Sync(nil, db, schema)
+	if err != nil {
+		t.Fatalf("Sync failed: %s", describe(err))
+	}
+	if len(plan.Steps) != 1 || plan.Steps[0].Kind != migrate.StepUpdateViewLinks {
+		t.Errorf("Expected a single update-view-links step, got:\n%s", plan)
+	}
+	v, err := db.View(nil, "migrate_search")
+	if err != nil {
+		t.Fatalf("View failed: %s", describe(err))
+	}
+	asv, err := v.ArangoSearchView()
+	if err != nil {
+		t.Fatalf("ArangoSearchView failed: %s", describe(err))
+	}
+	props, err := asv.Properties(nil)
+	if err != nil {
+		t.Fatalf("Properties failed: %s", describe(err))
+	}
+	if len(props.Links) != 2 {
+		t.Errorf("Expected 2 links, got %+v", props.Links)
+	}
+	// A second plan must be empty
+	plan, err = migrate.BuildPlan(nil, db, schema)
+	if err != nil {
+		t.Fatalf("BuildPlan failed: %s", describe(err))
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Expected empty plan, got:\n%s", plan)
+	}
+}
+
+// TestMigrateMigrations runs versioned migrations twice and checks they are applied only once.
+func TestMigrateMigrations(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	db := ensureDatabase(nil, c, "migrate_test", nil, t)
+	counts := make(map[int]int)
+	migrations := []migrate.Migration{
+		{Version: 2, Description: "second", Up: func(ctx context.Context, db driver.Database) error { counts[2]++; return nil }},
+		{Version: 1, Description: "first", Up: func(ctx context.Context, db driver.Database) error { counts[1]++; return nil }},
+	}
+	m, err := migrate.NewMigrator(db, migrate.Config{Collection: "_migrate_test_migrations"})
+	if err != nil {
+		t.Fatalf("NewMigrator failed: %s", describe(err))
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := m.Run(nil, nil, migrations...); err != nil {
+			t.Fatalf("Run failed: %s", describe(err))
+		}
+	}
+	if counts[1] != 1 || counts[2] != 1 {
+		t.Errorf("Expected every migration to run once, got %v", counts)
+	}
+	applied, err := m.AppliedMigrations(nil)
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %s", describe(err))
+	}
+	if len(applied) != 2 || applied[0].Version != 1 || applied[1].Version != 2 {
+		t.Errorf("Unexpected applied migrations %+v", applied)
+	}
+}
@@ -0,0 +1,133 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// TestPregelJob runs a PageRank job on a named graph and waits for it to finish.
+func TestPregelJob(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "pregel_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+
+	id, err := db.StartPregelJob(nil, driver.PregelJobOptions{
+		Algorithm: driver.PregelAlgorithmPageRank,
+		GraphName: "traversal_test",
+		Params: map[string]interface{}{
+			"maxGSS":      10,
+			"resultField": "rank",
+		},
+	})
+	if err != nil {
+		t.Fatalf("StartPregelJob failed: %s", describe(err))
+	}
+	if id == "" {
+		t.Fatal("Expected non-empty job ID")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	job, err := db.WaitForPregelJob(ctx, id, time.Millisecond*250)
+	if err != nil {
+		t.Fatalf("WaitForPregelJob failed: %s", describe(err))
+	}
+	if job.State != driver.PregelJobStateDone {
+		t.Errorf("Expected state '%s', got '%s'", driver.PregelJobStateDone, job.State)
+	}
+	if job.VertexCount != 4 {
+		t.Errorf("Expected 4 vertices, got %d", job.VertexCount)
+	}
+	if job.EdgeCount != 4 {
+		t.Errorf("Expected 4 edges, got %d", job.EdgeCount)
+	}
+	if job.GSS == 0 {
+		t.Error("Expected at least 1 global superstep")
+	}
+}
+
+// TestPregelJobCollections runs a connected components job on collections and cancels it.
+func TestPregelJobCollections(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "pregel_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+
+	id, err := db.StartPregelJob(nil, driver.PregelJobOptions{
+		Algorithm:         driver.PregelAlgorithmConnectedComponents,
+		VertexCollections: []string{"traversal_cities"},
+		EdgeCollections:   []string{"traversal_routes"},
+		Params: map[string]interface{}{
+			"store": false,
+		},
+	})
+	if err != nil {
+		t.Fatalf("StartPregelJob failed: %s", describe(err))
+	}
+	if _, err := db.PregelJob(nil, id); err != nil {
+		t.Errorf("PregelJob failed: %s", describe(err))
+	}
+	if err := db.CancelPregelJob(nil, id); err != nil && !driver.IsNotFound(err) {
+		t.Errorf("CancelPregelJob failed: %s", describe(err))
+	}
+
+	// Invalid options
+	if _, err := db.StartPregelJob(nil, driver.PregelJobOptions{Algorithm: driver.PregelAlgorithmPageRank}); !driver.IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %s", describe(err))
+	}
+}
+
+// TestPregelJobs lists all Pregel jobs.
+func TestPregelJobs(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.7", t)
+	db := ensureDatabase(nil, c, "pregel_test", nil, t)
+	ensureRouteGraph(nil, db, t)
+
+	id, err := db.StartPregelJob(nil, driver.PregelJobOptions{
+		Algorithm: driver.PregelAlgorithmPageRank,
+		GraphName: "traversal_test",
+	})
+	if err != nil {
+		t.Fatalf("StartPregelJob failed: %s", describe(err))
+	}
+	jobs, err := db.PregelJobs(nil)
+	if err != nil {
+		t.Fatalf("PregelJobs failed: %s", describe(err))
+	}
+	found := false
+	for _, job := range jobs {
+		if job.ID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("Expected job '%s' in list", id)
+	}
+}
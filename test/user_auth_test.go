@@ -602,3 +602,76 @@ func TestUserAccessibleDatabases(t *testing.T) {
 		t.Logf("Last part of test fails on version < 3.2 (got version %s)", version.Version)
 	}
 }
+
+// TestUserPermissions tests User.Permissions and wildcard access targets.
+func TestUserPermissions(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.2", t)
+	u := ensureUser(ctx, c, "permissions_user", &driver.UserOptions{Password: "foo"}, t)
+	db := ensureDatabase(ctx, c, "permissions_test", nil, t)
+	col := ensureCollection(ctx, db, "permissions_col", nil, t)
+
+	if err := u.SetDatabaseAccess(ctx, nil, driver.GrantReadOnly); err != nil {
+		t.Fatalf("SetDatabaseAccess failed: %s", describe(err))
+	}
+	if err := u.SetCollectionAccess(ctx, driver.NewAccessTarget(driver.AccessWildcard, driver.AccessWildcard), driver.GrantReadOnly); err != nil {
+		t.Fatalf("SetCollectionAccess failed: %s", describe(err))
+	}
+	if err := u.SetDatabaseAccess(ctx, db, driver.GrantReadWrite); err != nil {
+		t.Fatalf("SetDatabaseAccess failed: %s", describe(err))
+	}
+	if err := u.SetCollectionAccess(ctx, driver.NewAccessTarget(db.Name(), driver.AccessWildcard), driver.GrantReadWrite); err != nil {
+		t.Fatalf("SetCollectionAccess failed: %s", describe(err))
+	}
+	if err := u.SetCollectionAccess(ctx, col, driver.GrantNone); err != nil {
+		t.Fatalf("SetCollectionAccess failed: %s", describe(err))
+	}
+
+	permissions, err := u.Permissions(ctx)
+	if err != nil {
+		t.Fatalf("Permissions failed: %s", describe(err))
+	}
+	if p, found := permissions[db.Name()]; !found {
+		t.Errorf("Expected permissions for '%s', got %+v", db.Name(), permissions)
+	} else if p.Permission != driver.GrantReadWrite {
+		t.Errorf("Expected 'rw' database access, got '%s'", p.Permission)
+	} else if g := p.Collections[col.Name()]; g != driver.GrantNone {
+		t.Errorf("Expected 'none' collection access, got '%s'", g)
+	}
+
+	tests := []struct {
+		Database   string
+		Collection string
+		Expected   driver.Grant
+	}{
+		{db.Name(), "", driver.GrantReadWrite},
+		{db.Name(), col.Name(), driver.GrantNone},
+		{db.Name(), "other_col", driver.GrantReadWrite},
+		{"_system", "", driver.GrantReadOnly},
+	}
+	for _, test := range tests {
+		if g := driver.ResolvedAccess(permissions, test.Database, test.Collection); g != test.Expected {
+			t.Errorf("Expected '%s' for %s/%s, got '%s'", test.Expected, test.Database, test.Collection, g)
+		}
+		// Compare with the effective grant of the server
+		var serverGrant driver.Grant
+		var err error
+		if test.Collection == "" {
+			d, _ := c.Database(ctx, test.Database)
+			serverGrant, err = u.GetDatabaseAccess(ctx, d)
+		} else {
+			serverGrant, err = u.GetCollectionAccess(ctx, driver.NewAccessTarget(test.Database, test.Collection))
+		}
+		if err != nil {
+			t.Errorf("Get access failed: %s", describe(err))
+		} else if serverGrant != test.Expected {
+			t.Errorf("Expected server grant '%s' for %s/%s, got '%s'", test.Expected, test.Database, test.Collection, serverGrant)
+		}
+	}
+
+	// Cleanup
+	if err := u.Remove(ctx); err != nil {
+		t.Errorf("Remove failed: %s", describe(err))
+	}
+}
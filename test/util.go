@@ -48,6 +48,11 @@ func boolRef(v bool) *bool {
 	return &v
 }
 
+// int64Ref returns a reference to a given int64
+func int64Ref(v int64) *int64 {
+	return &v
+}
+
 // assertOK fails the test if the given error is not nil.
 func assertOK(err error, t *testing.T) {
 	if err != nil {
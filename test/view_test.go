@@ -0,0 +1,355 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// ensureArangoSearchView is a helper to check if an arangosearch view exists and create it if needed.
+// It will fail the test when an error occurs.
+func ensureArangoSearchView(ctx context.Context, db driver.Database, name string, options *driver.ArangoSearchViewProperties, t testEnv) driver.ArangoSearchView {
+	v, err := db.View(ctx, name)
+	if driver.IsNotFound(err) {
+		v, err = db.CreateArangoSearchView(ctx, name, options)
+		if err != nil {
+			t.Fatalf("Failed to create view '%s': %s", name, describe(err))
+		}
+	} else if err != nil {
+		t.Fatalf("Failed to open view '%s': %s", name, describe(err))
+	}
+	result, err := v.ArangoSearchView()
+	if err != nil {
+		t.Fatalf("Failed to open view '%s' as arangosearch view: %s", name, describe(err))
+	}
+	return result
+}
+
+// TestCreateArangoSearchView creates an arangosearch view and then checks that it exists.
+func TestCreateArangoSearchView(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	ensureCollection(nil, db, "someCol", nil, t)
+	name := "test_create_asview"
+	opts := &driver.ArangoSearchViewProperties{
+		Links: driver.ArangoSearchLinks{
+			"someCol": driver.ArangoSearchElementProperties{},
+		},
+	}
+	v, err := db.CreateArangoSearchView(nil, name, opts)
+	if err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name, describe(err))
+	}
+	// View must exist now
+	if found, err := db.ViewExists(nil, name); err != nil {
+		t.Errorf("ViewExists('%s') failed: %s", name, describe(err))
+	} else if !found {
+		t.Errorf("ViewExists('%s') return false, expected true", name)
+	}
+	// Check v.Name
+	if actualName := v.Name(); actualName != name {
+		t.Errorf("Name() failed. Got '%s', expected '%s'", actualName, name)
+	}
+	// Check v properties
+	p, err := v.Properties(nil)
+	if err != nil {
+		t.Fatalf("Properties failed: %s", describe(err))
+	}
+	if len(p.Links) != 1 {
+		t.Errorf("Expected 1 link, got %d", len(p.Links))
+	} else if _, found := p.Links["someCol"]; !found {
+		t.Errorf("Expected link for 'someCol' not found")
+	}
+}
+
+// TestCreateArangoSearchViewThenRemoveCollection creates an arangosearch view
+// with a link to an existing collection and the removes that collection.
+func TestCreateArangoSearchViewThenRemoveCollection(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	col := ensureCollection(nil, db, "someViewTmpCol", nil, t)
+	name := "test_create_asview_then_rm_col"
+	opts := &driver.ArangoSearchViewProperties{
+		Links: driver.ArangoSearchLinks{
+			"someViewTmpCol": driver.ArangoSearchElementProperties{},
+		},
+	}
+	v, err := db.CreateArangoSearchView(nil, name, opts)
+	if err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name, describe(err))
+	}
+	// Remove collection
+	if err := col.Remove(nil); err != nil {
+		t.Fatalf("Failed to remove collection '%s': %s", col.Name(), describe(err))
+	}
+	// Check v properties
+	p, err := v.Properties(nil)
+	if err != nil {
+		t.Fatalf("Properties failed: %s", describe(err))
+	}
+	if len(p.Links) != 0 {
+		t.Errorf("Expected 0 links, got %d", len(p.Links))
+	}
+}
+
+// TestGetArangoSearchView creates an arangosearch view and then gets it again.
+func TestGetArangoSearchView(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	ensureCollection(nil, db, "someCol", nil, t)
+	name := "test_get_asview"
+	if _, err := db.CreateArangoSearchView(nil, name, nil); err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name, describe(err))
+	}
+	// Get view
+	v, err := db.View(nil, name)
+	if err != nil {
+		t.Fatalf("View('%s') failed: %s", name, describe(err))
+	}
+	if _, err := v.ArangoSearchView(); err != nil {
+		t.Fatalf("ArangoSearchView() failed: %s", describe(err))
+	}
+	// Check v.Name
+	if actualName := v.Name(); actualName != name {
+		t.Errorf("Name() failed. Got '%s', expected '%s'", actualName, name)
+	}
+	// Check v.Type
+	if tp := v.Type(); tp != driver.ViewTypeArangoSearch {
+		t.Errorf("Type() failed. Got '%s', expected '%s'", tp, driver.ViewTypeArangoSearch)
+	}
+}
+
+// TestGetArangoSearchViews creates several arangosearch views and then gets all of them.
+func TestGetArangoSearchViews(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	ensureCollection(nil, db, "someCol", nil, t)
+	name1 := "test_get_asview_1"
+	name2 := "test_get_asview_2"
+	if _, err := db.CreateArangoSearchView(nil, name1, nil); err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name1, describe(err))
+	}
+	if _, err := db.CreateArangoSearchView(nil, name2, nil); err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name2, describe(err))
+	}
+	// Get views
+	views, err := db.Views(nil)
+	if err != nil {
+		t.Fatalf("Views failed: %s", describe(err))
+	}
+	assertNamesFound := func(expected []string) {
+		for _, n := range expected {
+			found := false
+			for _, v := range views {
+				if v.Name() == n {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("Expected view '%s' is not found", n)
+			}
+		}
+	}
+	assertNamesFound([]string{name1, name2})
+}
+
+// TestRenameAndRemoveArangoSearchView creates an arangosearch view, renames it and then removes it.
+func TestRenameAndRemoveArangoSearchView(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	if _, err := c.Cluster(nil); err == nil {
+		t.Skip("Renaming views is not supported in a cluster")
+	}
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	name := "test_rename_asview"
+	newName := "test_rename_asview_renamed"
+	v, err := db.CreateArangoSearchView(nil, name, nil)
+	if err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name, describe(err))
+	}
+	if err := v.Rename(nil, newName); err != nil {
+		t.Fatalf("Rename failed: %s", describe(err))
+	}
+	if v.Name() != newName {
+		t.Errorf("Name() failed. Got '%s', expected '%s'", v.Name(), newName)
+	}
+	if found, err := db.ViewExists(nil, name); err != nil {
+		t.Errorf("ViewExists('%s') failed: %s", name, describe(err))
+	} else if found {
+		t.Errorf("ViewExists('%s') return true, expected false", name)
+	}
+	// Now remove it
+	if err := v.Remove(nil); err != nil {
+		t.Fatalf("Failed to remove view '%s': %s", newName, describe(err))
+	}
+	if found, err := db.ViewExists(nil, newName); err != nil {
+		t.Errorf("ViewExists('%s') failed: %s", newName, describe(err))
+	} else if found {
+		t.Errorf("ViewExists('%s') return true, expected false", newName)
+	}
+}
+
+// TestUseArangoSearchView tries to create a view and actually use it in
+// an AQL query.
+func TestUseArangoSearchView(t *testing.T) {
+	ctx := context.Background()
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	col := ensureCollection(ctx, db, "some_collection", nil, t)
+
+	ensureArangoSearchView(ctx, db, "some_view", &driver.ArangoSearchViewProperties{
+		Links: driver.ArangoSearchLinks{
+			"some_collection": driver.ArangoSearchElementProperties{
+				Fields: driver.ArangoSearchFields{
+					"name": driver.ArangoSearchElementProperties{},
+				},
+			},
+		},
+	}, t)
+
+	docs := []UserDoc{
+		UserDoc{
+			"John",
+			23,
+		},
+		UserDoc{
+			"Alice",
+			43,
+		},
+		UserDoc{
+			"Helmut",
+			56,
+		},
+	}
+	_, errs, err := col.CreateDocuments(ctx, docs)
+	if err != nil {
+		t.Fatalf("Failed to create new documents: %s", describe(err))
+	} else if err := errs.FirstNonNil(); err != nil {
+		t.Fatalf("Expected no errors, got first: %s", describe(err))
+	}
+
+	// Wait for the documents to become visible in the view
+	cur, err := db.Query(driver.WithQueryCount(ctx), "FOR doc IN some_view SEARCH doc.name == 'John' OPTIONS {waitForSync:true} RETURN doc", nil)
+	if err != nil {
+		t.Fatalf("Failed to query data using arangosearch: %s", describe(err))
+	}
+	defer cur.Close()
+	if cur.Count() != 1 || !cur.HasMore() {
+		t.Fatalf("Wrong number of return values: expected 1, found %d", cur.Count())
+	}
+
+	var doc UserDoc
+	if _, err := cur.ReadDocument(ctx, &doc); err != nil {
+		t.Fatalf("Failed to read doc: %s", describe(err))
+	}
+	if doc.Name != "John" {
+		t.Fatalf("Expected result `John`, found `%s`", doc.Name)
+	}
+}
+
+// TestArangoSearchViewProperties creates an arangosearch view with typed properties
+// and checks that they round-trip through the server.
+func TestArangoSearchViewProperties(t *testing.T) {
+	c := createClientFromEnv(t, true)
+	skipBelowVersion(c, "3.4", t)
+	db := ensureDatabase(nil, c, "view_test", nil, t)
+	ensureCollection(nil, db, "someCol", nil, t)
+	name := "test_asview_properties"
+	commitInterval := int64(2000)
+	threshold := 0.5
+	opts := &driver.ArangoSearchViewProperties{
+		CommitIntervalMsec: &commitInterval,
+		ConsolidationPolicy: &driver.ArangoSearchConsolidationPolicy{
+			Type:      driver.ArangoSearchConsolidationPolicyTypeBytesAccum,
+			Threshold: &threshold,
+		},
+		Links: driver.ArangoSearchLinks{
+			"someCol": driver.ArangoSearchElementProperties{
+				Analyzers:        []string{"identity"},
+				IncludeAllFields: boolRef(true),
+				StoreValues:      driver.ArangoSearchStoreValuesID,
+				Fields: driver.ArangoSearchFields{
+					"name": driver.ArangoSearchElementProperties{
+						TrackListPositions: boolRef(true),
+					},
+				},
+			},
+		},
+	}
+	v, err := db.CreateArangoSearchView(nil, name, opts)
+	if err != nil {
+		t.Fatalf("Failed to create view '%s': %s", name, describe(err))
+	}
+	p, err := v.Properties(nil)
+	if err != nil {
+		t.Fatalf("Properties failed: %s", describe(err))
+	}
+	if p.CommitIntervalMsec == nil || *p.CommitIntervalMsec != commitInterval {
+		t.Errorf("Expected commitIntervalMsec %d, got %v", commitInterval, p.CommitIntervalMsec)
+	}
+	if p.ConsolidationPolicy == nil || p.ConsolidationPolicy.Type != driver.ArangoSearchConsolidationPolicyTypeBytesAccum {
+		t.Errorf("Expected consolidation policy of type bytes_accum, got %v", p.ConsolidationPolicy)
+	}
+	link, found := p.Links["someCol"]
+	if !found {
+		t.Fatalf("Expected link for 'someCol' not found")
+	}
+	if link.IncludeAllFields == nil || !*link.IncludeAllFields {
+		t.Errorf("Expected includeAllFields to be true")
+	}
+	if link.StoreValues != driver.ArangoSearchStoreValuesID {
+		t.Errorf("Expected storeValues '%s', got '%s'", driver.ArangoSearchStoreValuesID, link.StoreValues)
+	}
+	if field, found := link.Fields["name"]; !found {
+		t.Errorf("Expected field 'name' not found")
+	} else if field.TrackListPositions == nil || !*field.TrackListPositions {
+		t.Errorf("Expected trackListPositions of field 'name' to be true")
+	}
+
+	// Now change the properties
+	commitInterval = 3000
+	if err := v.SetProperties(nil, driver.ArangoSearchViewProperties{
+		CommitIntervalMsec: &commitInterval,
+		Links: driver.ArangoSearchLinks{
+			"someCol": driver.ArangoSearchElementProperties{},
+		},
+	}); err != nil {
+		t.Fatalf("SetProperties failed: %s", describe(err))
+	}
+	p, err = v.Properties(nil)
+	if err != nil {
+		t.Fatalf("Properties failed: %s", describe(err))
+	}
+	if p.CommitIntervalMsec == nil || *p.CommitIntervalMsec != commitInterval {
+		t.Errorf("Expected commitIntervalMsec %d, got %v", commitInterval, p.CommitIntervalMsec)
+	}
+}
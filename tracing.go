@@ -0,0 +1,170 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TraceParentHeader is the name of the W3C Trace Context header sent with requests.
+const TraceParentHeader = "traceparent"
+
+// Tracer is called by connections around every request sent to a server,
+// to record the request in a distributed trace.
+// Implementations must be safe for concurrent use.
+type Tracer interface {
+	// StartSpan is called before a request is sent to a server.
+	// The returned context is used for the request.
+	StartSpan(ctx context.Context, info SpanStartInfo) (context.Context, Span)
+}
+
+// Span records a single request to a server.
+type Span interface {
+	// TraceParent returns the W3C traceparent value identifying this span, which is sent
+	// to the server in the traceparent header.
+	// If empty, the value set with WithTraceParent (if any) is sent.
+	TraceParent() string
+	// End is called when the request has finished.
+	End(info SpanEndInfo)
+}
+
+// SpanStartInfo describes a request that is about to be sent.
+type SpanStartInfo struct {
+	// Method of the request (GET, POST, ...)
+	Method string
+	// Path of the request, relative to the endpoint
+	Path string
+	// Endpoint the request is sent to
+	Endpoint string
+}
+
+// SpanEndInfo describes the result of a request.
+type SpanEndInfo struct {
+	// StatusCode of the response (0 if there is no response)
+	StatusCode int
+	// RequestBytes is the size of the request content
+	// (the body for HTTP, the complete message for Velocystream)
+	RequestBytes int64
+	// ResponseBytes is the size of the response content
+	// (the body for HTTP, the complete message for Velocystream)
+	ResponseBytes int64
+	// Err is the error of the request, if any
+	Err error
+}
+
+// WithTraceParent is used to configure a context that sends the given W3C traceparent value
+// with requests, connecting them to the trace of the caller.
+// If a Tracer is configured, its spans are expected to be children of this value.
+func WithTraceParent(parent context.Context, traceParent string) context.Context {
+	return context.WithValue(contextOrBackground(parent), keyTraceParent, traceParent)
+}
+
+// TraceParentFromContext returns the traceparent value set with WithTraceParent,
+// or an empty string if the given context has none.
+func TraceParentFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if v, ok := ctx.Value(keyTraceParent).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// TraceParent holds the fields of a W3C traceparent value.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+type TraceParent struct {
+	// TraceID identifies the whole trace
+	TraceID [16]byte
+	// SpanID identifies the parent span
+	SpanID [8]byte
+	// Flags holds the trace flags (bit 0 = sampled)
+	Flags byte
+}
+
+// NewTraceParent creates a TraceParent with random trace & span IDs.
+func NewTraceParent(sampled bool) TraceParent {
+	var tp TraceParent
+	rand.Read(tp.TraceID[:])
+	rand.Read(tp.SpanID[:])
+	if sampled {
+		tp.Flags = 1
+	}
+	return tp
+}
+
+// ParseTraceParent parses a W3C traceparent value.
+func ParseTraceParent(value string) (TraceParent, error) {
+	var tp TraceParent
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return tp, WithStack(InvalidArgumentError{Message: fmt.Sprintf("Invalid traceparent '%s'", value)})
+	}
+	var flags [1]byte
+	if err := decodeHexInto(parts[1], tp.TraceID[:]); err != nil {
+		return tp, WithStack(err)
+	}
+	if err := decodeHexInto(parts[2], tp.SpanID[:]); err != nil {
+		return tp, WithStack(err)
+	}
+	if err := decodeHexInto(parts[3], flags[:]); err != nil {
+		return tp, WithStack(err)
+	}
+	if tp.TraceID == [16]byte{} || tp.SpanID == [8]byte{} {
+		return tp, WithStack(InvalidArgumentError{Message: fmt.Sprintf("Invalid traceparent '%s'", value)})
+	}
+	tp.Flags = flags[0]
+	return tp, nil
+}
+
+// Child returns a TraceParent in the same trace with a new random span ID.
+func (tp TraceParent) Child() TraceParent {
+	rand.Read(tp.SpanID[:])
+	return tp
+}
+
+// Sampled returns true if the sampled flag is set.
+func (tp TraceParent) Sampled() bool {
+	return tp.Flags&1 != 0
+}
+
+// String returns the traceparent value (version 00).
+func (tp TraceParent) String() string {
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(tp.TraceID[:]), hex.EncodeToString(tp.SpanID[:]), tp.Flags)
+}
+
+// decodeHexInto decodes the given lowercase hexadecimal string into the given buffer,
+// which must have exactly the encoded length.
+func decodeHexInto(s string, buf []byte) error {
+	if len(s) != hex.EncodedLen(len(buf)) || strings.ToLower(s) != s {
+		return WithStack(InvalidArgumentError{Message: fmt.Sprintf("Invalid hex field '%s'", s)})
+	}
+	if _, err := hex.Decode(buf, []byte(s)); err != nil {
+		return WithStack(InvalidArgumentError{Message: fmt.Sprintf("Invalid hex field '%s'", s)})
+	}
+	return nil
+}
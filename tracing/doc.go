@@ -0,0 +1,45 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+/*
+Package tracing provides driver.Tracer implementations.
+
+The Recorder records all requests in memory, which is useful in tests:
+
+	recorder := tracing.NewRecorder()
+	conn, err := http.NewConnection(http.ConnectionConfig{
+		Endpoints: []string{"http://localhost:8529"},
+		Tracer:    recorder,
+	})
+	...
+	for _, span := range recorder.Spans() {
+		fmt.Printf("%s %s -> %d\n", span.Method, span.Path, span.StatusCode)
+	}
+
+NewOTelTracer adapts a tracer with an OpenTelemetry-like API, without this module
+depending on OpenTelemetry. Implement the OTelTracer & OTelSpan interfaces using
+a small wrapper around your OpenTelemetry tracer.
+
+To connect database requests to the trace of a caller without a Tracer,
+use driver.WithTraceParent.
+*/
+package tracing
@@ -0,0 +1,120 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// Attribute is a key-value pair describing a span, like an OpenTelemetry attribute.KeyValue.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// OTelTracer is the subset of an OpenTelemetry tracer used by NewOTelTracer.
+type OTelTracer interface {
+	// Start creates a span and a context containing the span.
+	Start(ctx context.Context, spanName string) (context.Context, OTelSpan)
+}
+
+// OTelSpan is the subset of an OpenTelemetry span used by NewOTelTracer.
+type OTelSpan interface {
+	// SetAttributes sets the given attributes on the span.
+	SetAttributes(attributes ...Attribute)
+	// RecordError records the given error as an event of the span.
+	RecordError(err error)
+	// SetErrorStatus sets the status of the span to error, with the given description.
+	SetErrorStatus(description string)
+	// End completes the span.
+	End()
+	// TraceParent returns the W3C traceparent value of the span context of the span.
+	// Return an empty string if the span context is not valid.
+	TraceParent() string
+}
+
+// Names of the attributes set by NewOTelTracer, following the OpenTelemetry semantic conventions.
+const (
+	AttributeDBSystem            = "db.system"
+	AttributeHTTPMethod          = "http.method"
+	AttributeHTTPTarget          = "http.target"
+	AttributeHTTPURL             = "http.url"
+	AttributeHTTPStatusCode      = "http.status_code"
+	AttributeRequestContentSize  = "http.request_content_length"
+	AttributeResponseContentSize = "http.response_content_length"
+)
+
+// NewOTelTracer creates a driver.Tracer that creates a span using the given tracer for every request.
+// Responses with a status code of 400 or higher and failed requests set the status of the span to error.
+func NewOTelTracer(tracer OTelTracer) driver.Tracer {
+	return &otelTracer{tracer: tracer}
+}
+
+// otelTracer implements driver.Tracer using an OTelTracer.
+type otelTracer struct {
+	tracer OTelTracer
+}
+
+// StartSpan is called before a request is sent to a server.
+func (t *otelTracer) StartSpan(ctx context.Context, info driver.SpanStartInfo) (context.Context, driver.Span) {
+	ctx, span := t.tracer.Start(ctx, "ArangoDB "+info.Method)
+	span.SetAttributes(
+		Attribute{Key: AttributeDBSystem, Value: "arangodb"},
+		Attribute{Key: AttributeHTTPMethod, Value: info.Method},
+		Attribute{Key: AttributeHTTPTarget, Value: info.Path},
+		Attribute{Key: AttributeHTTPURL, Value: info.Endpoint},
+	)
+	return ctx, otelSpan{span: span}
+}
+
+// otelSpan implements driver.Span using an OTelSpan.
+type otelSpan struct {
+	span OTelSpan
+}
+
+// TraceParent returns the traceparent value identifying this span.
+func (s otelSpan) TraceParent() string {
+	return s.span.TraceParent()
+}
+
+// End is called when the request has finished.
+func (s otelSpan) End(info driver.SpanEndInfo) {
+	attributes := []Attribute{
+		{Key: AttributeRequestContentSize, Value: info.RequestBytes},
+		{Key: AttributeResponseContentSize, Value: info.ResponseBytes},
+	}
+	if info.StatusCode != 0 {
+		attributes = append(attributes, Attribute{Key: AttributeHTTPStatusCode, Value: info.StatusCode})
+	}
+	s.span.SetAttributes(attributes...)
+	if info.Err != nil {
+		s.span.RecordError(info.Err)
+		s.span.SetErrorStatus(info.Err.Error())
+	} else if info.StatusCode >= 400 {
+		s.span.SetErrorStatus(fmt.Sprintf("status code %d", info.StatusCode))
+	}
+	s.span.End()
+}
@@ -0,0 +1,121 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+// RecordedSpan holds the information of a request recorded by a Recorder.
+type RecordedSpan struct {
+	driver.SpanStartInfo
+	driver.SpanEndInfo
+	// Parent is the traceparent value of the caller (see driver.WithTraceParent), if any
+	Parent string
+	// TraceParent is the traceparent value sent with the request
+	TraceParent string
+	// StartTime is the time the request started
+	StartTime time.Time
+	// EndTime is the time the request ended (zero if it has not ended)
+	EndTime time.Time
+}
+
+// Ended returns true if the request has ended.
+func (s RecordedSpan) Ended() bool {
+	return !s.EndTime.IsZero()
+}
+
+// Recorder is a driver.Tracer that records all requests in memory.
+// Spans are children of the traceparent in the context (see driver.WithTraceParent),
+// or start a new trace if there is none.
+type Recorder struct {
+	mutex sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewRecorder creates a new, empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// StartSpan is called before a request is sent to a server.
+func (r *Recorder) StartSpan(ctx context.Context, info driver.SpanStartInfo) (context.Context, driver.Span) {
+	parent := driver.TraceParentFromContext(ctx)
+	var tp driver.TraceParent
+	if p, err := driver.ParseTraceParent(parent); err == nil {
+		tp = p.Child()
+	} else {
+		tp = driver.NewTraceParent(true)
+	}
+	span := &RecordedSpan{
+		SpanStartInfo: info,
+		Parent:        parent,
+		TraceParent:   tp.String(),
+		StartTime:     time.Now(),
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = append(r.spans, span)
+	return ctx, &recorderSpan{recorder: r, span: span}
+}
+
+// Spans returns a copy of all recorded spans, in the order they were started.
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	result := make([]RecordedSpan, len(r.spans))
+	for i, s := range r.spans {
+		result[i] = *s
+	}
+	return result
+}
+
+// Reset removes all recorded spans.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = nil
+}
+
+// recorderSpan implements driver.Span for a Recorder.
+type recorderSpan struct {
+	recorder *Recorder
+	span     *RecordedSpan
+}
+
+// TraceParent returns the traceparent value identifying this span.
+func (s *recorderSpan) TraceParent() string {
+	return s.span.TraceParent
+}
+
+// End is called when the request has finished.
+func (s *recorderSpan) End(info driver.SpanEndInfo) {
+	s.recorder.mutex.Lock()
+	defer s.recorder.mutex.Unlock()
+	s.span.SpanEndInfo = info
+	s.span.EndTime = time.Now()
+}
@@ -0,0 +1,94 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	driver "github.com/arangodb/go-driver"
+	driverhttp "github.com/arangodb/go-driver/http"
+	"github.com/arangodb/go-driver/tracing"
+)
+
+// TestRecorder checks that the recorder captures requests and that the
+// traceparent header is propagated to the server.
+func TestRecorder(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(driver.TraceParentHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":true,"code":404,"errorNum":1203}`))
+	}))
+	defer srv.Close()
+
+	recorder := tracing.NewRecorder()
+	conn, err := driverhttp.NewConnection(driverhttp.ConnectionConfig{
+		Endpoints: []string{srv.URL},
+		Tracer:    recorder,
+	})
+	if err != nil {
+		t.Fatalf("NewConnection failed: %s", err)
+	}
+	parent := driver.NewTraceParent(true)
+	ctx := driver.WithTraceParent(context.Background(), parent.String())
+	req, err := conn.NewRequest("GET", "_api/collection/foo")
+	if err != nil {
+		t.Fatalf("NewRequest failed: %s", err)
+	}
+	resp, err := conn.Do(ctx, req)
+	if err != nil {
+		t.Fatalf("Do failed: %s", err)
+	}
+	if resp.StatusCode() != 404 {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode())
+	}
+
+	spans := recorder.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Method != "GET" || span.Path != "_api/collection/foo" {
+		t.Errorf("Unexpected method/path: %s %s", span.Method, span.Path)
+	}
+	if !span.Ended() || span.StatusCode != 404 || span.ResponseBytes == 0 {
+		t.Errorf("Unexpected end info: %+v", span.SpanEndInfo)
+	}
+	if span.Parent != parent.String() {
+		t.Errorf("Expected parent %s, got %s", parent, span.Parent)
+	}
+	if received != span.TraceParent {
+		t.Errorf("Expected server to receive %s, got %s", span.TraceParent, received)
+	}
+	tp, err := driver.ParseTraceParent(received)
+	if err != nil {
+		t.Fatalf("ParseTraceParent failed: %s", err)
+	}
+	if tp.TraceID != parent.TraceID || tp.SpanID == parent.SpanID {
+		t.Errorf("Expected child of %s, got %s", parent, tp)
+	}
+}
@@ -53,8 +53,14 @@ type User interface {
 	// AccessibleDatabases returns a list of all databases that can be accessed (read/write or read-only) by this user.
 	AccessibleDatabases(ctx context.Context) ([]Database, error)
 
+	// Permissions returns the access this user has to all databases and their collections, by database name.
+	// The AccessWildcard entry holds the default access of the user.
+	// Use ResolvedAccess to compute the effective access to a specific database or collection.
+	// This function requires ArangoDB 3.2 and up.
+	Permissions(ctx context.Context) (map[string]DatabasePermissions, error)
+
 	// SetDatabaseAccess sets the access this user has to the given database.
-	// Pass a `nil` database to set the default access this user has to any new database.
+	// Pass a `nil` database to set the default access this user has to any new database (AccessWildcard).
 	// This function requires ArangoDB 3.2 and up for access value `GrantReadOnly`.
 	SetDatabaseAccess(ctx context.Context, db Database, access Grant) error
 
@@ -78,6 +84,7 @@ type User interface {
 	// If you pass a `Collection`, it will set access for that collection.
 	// If you pass a `Database`, it will set the default collection access for that database.
 	// If you pass `nil`, it will set the default collection access for the default database.
+	// Use NewAccessTarget to pass a database and/or collection by name, including AccessWildcard.
 	// This function requires ArangoDB 3.2 and up.
 	SetCollectionAccess(ctx context.Context, col AccessTarget, access Grant) error
 
@@ -184,8 +184,37 @@ func (u *user) AccessibleDatabases(ctx context.Context) ([]Database, error) {
 	return result, nil
 }
 
+type userPermissionsResponse struct {
+	Result map[string]DatabasePermissions `json:"result"`
+}
+
+// Permissions returns the access this user has to all databases and their collections, by database name.
+// The AccessWildcard entry holds the default access of the user.
+// Use ResolvedAccess to compute the effective access to a specific database or collection.
+// This function requires ArangoDB 3.2 and up.
+func (u *user) Permissions(ctx context.Context) (map[string]DatabasePermissions, error) {
+	req, err := u.conn.NewRequest("GET", path.Join(u.relPath(), "database"))
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	req.SetQuery("full", "true")
+	applyContextSettings(ctx, req)
+	resp, err := u.conn.Do(ctx, req)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, WithStack(err)
+	}
+	var data userPermissionsResponse
+	if err := resp.ParseBody("", &data); err != nil {
+		return nil, WithStack(err)
+	}
+	return data.Result, nil
+}
+
 // SetDatabaseAccess sets the access this user has to the given database.
-// Pass a `nil` database to set the default access this user has to any new database.
+// Pass a `nil` database to set the default access this user has to any new database (AccessWildcard).
 // This function requires ArangoDB 3.2 and up for access value `GrantReadOnly`.
 func (u *user) SetDatabaseAccess(ctx context.Context, db Database, access Grant) error {
 	dbName, _, err := getDatabaseAndCollectionName(db)
@@ -278,6 +307,7 @@ func (u *user) RemoveDatabaseAccess(ctx context.Context, db Database) error {
 // If you pass a `Collection`, it will set access for that collection.
 // If you pass a `Database`, it will set the default collection access for that database.
 // If you pass `nil`, it will set the default collection access for the default database.
+// Use NewAccessTarget to pass a database and/or collection by name, including AccessWildcard.
 // This function requires ArangoDB 3.2 and up.
 func (u *user) SetCollectionAccess(ctx context.Context, col AccessTarget, access Grant) error {
 	dbName, colName, err := getDatabaseAndCollectionName(col)
@@ -368,7 +398,16 @@ func (u *user) RemoveCollectionAccess(ctx context.Context, col AccessTarget) err
 // getDatabaseAndCollectionName returns database-name, collection-name from given access target.
 func getDatabaseAndCollectionName(col AccessTarget) (string, string, error) {
 	if col == nil {
-		return "*", "*", nil
+		return AccessWildcard, AccessWildcard, nil
+	}
+	if x, ok := col.(*accessTarget); ok {
+		if x.databaseName == "" {
+			return "", "", WithStack(InvalidArgumentError{"Database name is empty"})
+		}
+		if x.collectionName == "" {
+			return x.databaseName, AccessWildcard, nil
+		}
+		return x.databaseName, x.collectionName, nil
 	}
 	if x, ok := col.(Collection); ok {
 		return x.Database().Name(), x.Name(), nil
@@ -0,0 +1,107 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+// AccessWildcard is the name used by the server for the default access of all databases or all collections.
+const AccessWildcard = "*"
+
+// DatabasePermissions holds the access a user has to a single database and its collections.
+type DatabasePermissions struct {
+	// Permission is the access the user has to the database itself.
+	Permission Grant `json:"permission,omitempty"`
+	// Collections holds the access the user has to the collections of the database, by collection name.
+	// The AccessWildcard entry holds the default access to all collections of the database.
+	Collections map[string]Grant `json:"collections,omitempty"`
+}
+
+// accessTarget implements AccessTarget for a database & collection identified by name.
+type accessTarget struct {
+	databaseName   string
+	collectionName string
+}
+
+// NewAccessTarget returns an AccessTarget for the collection with given name in the database with given name,
+// to be used with the collection access functions of User.
+// Pass AccessWildcard as database name to target the default access of all databases and
+// pass AccessWildcard as collection name to target the default access of all collections in the database.
+func NewAccessTarget(databaseName, collectionName string) AccessTarget {
+	return &accessTarget{databaseName: databaseName, collectionName: collectionName}
+}
+
+// Name returns the name of the collection.
+func (t *accessTarget) Name() string {
+	return t.collectionName
+}
+
+// ResolvedAccess returns the effective access that a user with given permissions has to the database with given name,
+// or (when collection is not empty) to the collection with given name in that database.
+// The permissions are typically obtained using User.Permissions.
+// The grant is resolved the way the server does:
+// - Database access is the access configured for the database, falling back to the access configured for AccessWildcard.
+// - Collection access is the access configured for the collection, falling back to the AccessWildcard collection
+// of the database, then the collection in the AccessWildcard database and finally the AccessWildcard collection
+// of the AccessWildcard database.
+// - Access to system collections follows the database access, except for `_system/_users` (no access),
+// `_queues` (read-only) and `_frontend` (read/write).
+// If no access is configured, GrantNone is returned.
+func ResolvedAccess(permissions map[string]DatabasePermissions, database, collection string) Grant {
+	if collection == "" {
+		return resolvedDatabaseAccess(permissions, database)
+	}
+	if collection[0] == '_' {
+		switch {
+		case database == "_system" && collection == "_users":
+			return GrantNone
+		case collection == "_queues":
+			return GrantReadOnly
+		case collection == "_frontend":
+			return GrantReadWrite
+		}
+		return resolvedDatabaseAccess(permissions, database)
+	}
+	for _, dbName := range []string{database, AccessWildcard} {
+		if p, found := permissions[dbName]; found {
+			for _, colName := range []string{collection, AccessWildcard} {
+				if g, found := p.Collections[colName]; found && isConfiguredGrant(g) {
+					return g
+				}
+			}
+		}
+	}
+	return GrantNone
+}
+
+// resolvedDatabaseAccess returns the effective access to the database with given name.
+func resolvedDatabaseAccess(permissions map[string]DatabasePermissions, database string) Grant {
+	for _, dbName := range []string{database, AccessWildcard} {
+		if p, found := permissions[dbName]; found && isConfiguredGrant(p.Permission) {
+			return p.Permission
+		}
+	}
+	return GrantNone
+}
+
+// isConfiguredGrant returns true if the given grant is a configured (non-empty, defined) grant.
+func isConfiguredGrant(g Grant) bool {
+	return g != "" && g != "undefined"
+}
@@ -0,0 +1,100 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+import "testing"
+
+func TestResolvedAccess(t *testing.T) {
+	permissions := map[string]DatabasePermissions{
+		"shop": {
+			Permission: GrantReadWrite,
+			Collections: map[string]Grant{
+				"orders":   GrantReadOnly,
+				"invoices": "undefined",
+				"*":        GrantReadWrite,
+			},
+		},
+		"archive": {
+			Permission:  "undefined",
+			Collections: map[string]Grant{"secret": GrantNone},
+		},
+		"*": {
+			Permission: GrantReadOnly,
+			Collections: map[string]Grant{
+				"invoices": GrantNone,
+				"*":        GrantReadOnly,
+			},
+		},
+	}
+	tests := []struct {
+		Database   string
+		Collection string
+		Expected   Grant
+	}{
+		{"shop", "", GrantReadWrite},
+		{"archive", "", GrantReadOnly},
+		{"other", "", GrantReadOnly},
+		{"shop", "orders", GrantReadOnly},
+		{"shop", "products", GrantReadWrite},
+		{"shop", "invoices", GrantReadWrite},
+		{"archive", "secret", GrantNone},
+		{"archive", "invoices", GrantNone},
+		{"archive", "logs", GrantReadOnly},
+		{"shop", "_apps", GrantReadWrite},
+		{"_system", "_users", GrantNone},
+		{"shop", "_queues", GrantReadOnly},
+		{"shop", "_frontend", GrantReadWrite},
+	}
+	for _, test := range tests {
+		if result := ResolvedAccess(permissions, test.Database, test.Collection); result != test.Expected {
+			t.Errorf("Expected '%s' for %s/%s, got '%s'", test.Expected, test.Database, test.Collection, result)
+		}
+	}
+	if result := ResolvedAccess(nil, "shop", "orders"); result != GrantNone {
+		t.Errorf("Expected 'none' without permissions, got '%s'", result)
+	}
+}
+
+func TestNewAccessTarget(t *testing.T) {
+	tests := []struct {
+		Target     AccessTarget
+		Database   string
+		Collection string
+	}{
+		{nil, "*", "*"},
+		{NewAccessTarget("shop", "orders"), "shop", "orders"},
+		{NewAccessTarget("shop", ""), "shop", "*"},
+		{NewAccessTarget(AccessWildcard, AccessWildcard), "*", "*"},
+	}
+	for _, test := range tests {
+		dbName, colName, err := getDatabaseAndCollectionName(test.Target)
+		if err != nil {
+			t.Errorf("getDatabaseAndCollectionName failed: %s", err)
+		} else if dbName != test.Database || colName != test.Collection {
+			t.Errorf("Expected %s/%s, got %s/%s", test.Database, test.Collection, dbName, colName)
+		}
+	}
+	if _, _, err := getDatabaseAndCollectionName(NewAccessTarget("", "orders")); !IsInvalidArgument(err) {
+		t.Errorf("Expected InvalidArgumentError, got %v", err)
+	}
+}
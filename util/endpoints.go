@@ -36,3 +36,11 @@ var (
 func FixupEndpointURLScheme(u string) string {
 	return urlFixer.Replace(u)
 }
+
+// IsUnixEndpoint returns true if the given endpoint URL refers to a unix domain socket.
+// The path of the URL is the path of the socket.
+// E.g. "unix:///tmp/arangod.sock" or "http+unix:///tmp/arangod.sock"
+func IsUnixEndpoint(u string) bool {
+	lower := strings.ToLower(u)
+	return strings.HasPrefix(lower, "unix://") || strings.HasPrefix(lower, "http+unix://")
+}
@@ -0,0 +1,42 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package util
+
+import "testing"
+
+// TestIsUnixEndpoint tests IsUnixEndpoint.
+func TestIsUnixEndpoint(t *testing.T) {
+	tests := map[string]bool{
+		"unix:///tmp/arangod.sock":      true,
+		"http+unix:///tmp/arangod.sock": true,
+		"UNIX:///tmp/arangod.sock":      true,
+		"http://localhost:8529":         false,
+		"tcp://localhost:8529":          false,
+		"ssl://localhost:8529":          false,
+	}
+	for endpoint, expected := range tests {
+		if result := IsUnixEndpoint(endpoint); result != expected {
+			t.Errorf("Expected %v for '%s', got %v", expected, endpoint, result)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// JWTRenewalMargin is the maximum time before the expiry of a JWT token at which the token is renewed.
+	JWTRenewalMargin = time.Minute
+)
+
+// JWTExpiresAt returns the expiry time (`exp` claim) of the given JWT token.
+// The signature of the token is not verified.
+// If the token cannot be decoded or has no `exp` claim, false is returned.
+func JWTExpiresAt(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		ExpiresAt *float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	sec := int64(*claims.ExpiresAt)
+	nsec := int64((*claims.ExpiresAt - float64(sec)) * float64(time.Second))
+	return time.Unix(sec, nsec), true
+}
+
+// JWTRenewAt returns the time at which a JWT token that was obtained at the given time
+// and expires at the given time must be renewed.
+// That is JWTRenewalMargin before expiry, or half-way the lifetime of the token for short-lived tokens.
+func JWTRenewAt(obtainedAt, expiresAt time.Time) time.Time {
+	margin := JWTRenewalMargin
+	if lifetime := expiresAt.Sub(obtainedAt); lifetime < 2*margin {
+		margin = lifetime / 2
+	}
+	return expiresAt.Add(-margin)
+}
@@ -0,0 +1,65 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package util
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestJWTExpiresAt(t *testing.T) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	tests := []struct {
+		Payload  string
+		Expected time.Time
+		Valid    bool
+	}{
+		{`{"exp":1500000000,"iss":"arangodb"}`, time.Unix(1500000000, 0), true},
+		{`{"exp":1500000000.5}`, time.Unix(1500000000, int64(time.Second/2)), true},
+		{`{"iss":"arangodb"}`, time.Time{}, false},
+		{`not json`, time.Time{}, false},
+	}
+	for _, test := range tests {
+		token := header + "." + base64.RawURLEncoding.EncodeToString([]byte(test.Payload)) + ".signature"
+		expiresAt, valid := JWTExpiresAt(token)
+		if valid != test.Valid {
+			t.Errorf("Expected valid=%v for %s, got %v", test.Valid, test.Payload, valid)
+		} else if valid && !expiresAt.Equal(test.Expected) {
+			t.Errorf("Expected %s for %s, got %s", test.Expected, test.Payload, expiresAt)
+		}
+	}
+	if _, valid := JWTExpiresAt("no-token"); valid {
+		t.Error("Expected invalid token")
+	}
+}
+
+func TestJWTRenewAt(t *testing.T) {
+	now := time.Now()
+	if renewAt := JWTRenewAt(now, now.Add(time.Hour)); !renewAt.Equal(now.Add(time.Hour - JWTRenewalMargin)) {
+		t.Errorf("Unexpected renewal time %s for a 1 hour token", renewAt.Sub(now))
+	}
+	if renewAt := JWTRenewAt(now, now.Add(time.Minute)); !renewAt.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("Unexpected renewal time %s for a 1 minute token", renewAt.Sub(now))
+	}
+}
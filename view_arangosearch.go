@@ -0,0 +1,144 @@
+//
+// DISCLAIMER
+//
+// Copyright 2018 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+// Author Ewout Prangsma
+//
+
+package driver
+
+// ArangoSearchViewProperties contains the properties of an ArangoSearch view.
+type ArangoSearchViewProperties struct {
+	// CleanupIntervalStep specifies the minimum number of commits to wait between
+	// removing unused files in the data directory.
+	// Defaults to 10.
+	// Use 0 to disable waiting.
+	// For the case where the consolidation policies merge segments often
+	// (i.e. a lot of commit+consolidate), a lower value will cause a lot of
+	// disk space to be wasted.
+	// For the case where the consolidation policies rarely merge segments
+	// (i.e. few inserts/deletes), a higher value will impact performance
+	// without any added benefits.
+	CleanupIntervalStep *int64 `json:"cleanupIntervalStep,omitempty"`
+	// ConsolidationIntervalMsec specifies the minimum number of milliseconds that must be waited
+	// between applying the consolidation policy to the index data.
+	// Defaults to 60000.
+	// Use 0 to disable.
+	ConsolidationIntervalMsec *int64 `json:"consolidationIntervalMsec,omitempty"`
+	// CommitIntervalMsec specifies the minimum number of milliseconds that must be waited
+	// between committing index data changes and making them visible to queries.
+	// Defaults to 1000.
+	// Use 0 to disable.
+	CommitIntervalMsec *int64 `json:"commitIntervalMsec,omitempty"`
+	// ConsolidationPolicy specifies thresholds for consolidation.
+	ConsolidationPolicy *ArangoSearchConsolidationPolicy `json:"consolidationPolicy,omitempty"`
+	// PrimarySort specifies the order in which documents are stored in the view.
+	// The primary sort can only be set when creating a view, it cannot be changed later.
+	PrimarySort []ArangoSearchPrimarySortEntry `json:"primarySort,omitempty"`
+	// Links contains the properties for how individual collections
+	// are indexed in the view.
+	// The keys of the map are collection names.
+	Links ArangoSearchLinks `json:"links,omitempty"`
+}
+
+// ArangoSearchLinks is a strongly typed map containing links between a
+// collection and a view.
+// The keys in the map are collection names.
+type ArangoSearchLinks map[string]ArangoSearchElementProperties
+
+// ArangoSearchFields is a strongly typed map containing properties per field.
+// The keys in the map are field names.
+type ArangoSearchFields map[string]ArangoSearchElementProperties
+
+// ArangoSearchElementProperties contains properties that specify how an element
+// is indexed in an ArangoSearch view.
+// Note that this structure is recursive. Settings not specified (nil)
+// at a given level will inherit their setting from a lower level.
+type ArangoSearchElementProperties struct {
+	// Analyzers contains the names of the analyzers used to process this element.
+	// Defaults to []string{"identity"}.
+	Analyzers []string `json:"analyzers,omitempty"`
+	// Fields contains the properties for individual fields of the element.
+	// The key of the map are field names.
+	Fields ArangoSearchFields `json:"fields,omitempty"`
+	// IncludeAllFields specifies whether or not to include all fields of the element.
+	// If set, all fields not explicitly listed in Fields are indexed as well.
+	IncludeAllFields *bool `json:"includeAllFields,omitempty"`
+	// TrackListPositions, if set, causes the position of values in array values to be tracked.
+	// The default is false, meaning that all array elements are treated equally.
+	TrackListPositions *bool `json:"trackListPositions,omitempty"`
+	// StoreValues specifies how the view should track values.
+	StoreValues ArangoSearchStoreValues `json:"storeValues,omitempty"`
+}
+
+// ArangoSearchStoreValues is the type of the StoreValues option of an ArangoSearch element.
+type ArangoSearchStoreValues string
+
+const (
+	// ArangoSearchStoreValuesNone specifies that a view should not store values.
+	ArangoSearchStoreValuesNone ArangoSearchStoreValues = "none"
+	// ArangoSearchStoreValuesID specifies that a view should only store
+	// information about value presence, to allow use of the EXISTS() function.
+	ArangoSearchStoreValuesID ArangoSearchStoreValues = "id"
+)
+
+// ArangoSearchConsolidationPolicyType strings for consolidation types.
+type ArangoSearchConsolidationPolicyType string
+
+const (
+	// ArangoSearchConsolidationPolicyTypeTier consolidates based on segment byte size and live document count as dictated by the customization attributes.
+	ArangoSearchConsolidationPolicyTypeTier ArangoSearchConsolidationPolicyType = "tier"
+	// ArangoSearchConsolidationPolicyTypeBytesAccum consolidates if and only if ({threshold} range [0.0, 1.0])
+	// {threshold} > (segment_bytes + sum_of_merge_candidate_segment_bytes) / all_segment_bytes,
+	// i.e. the sum of all candidate segment's byte size is less than the total segment byte size multiplied by the {threshold}.
+	ArangoSearchConsolidationPolicyTypeBytesAccum ArangoSearchConsolidationPolicyType = "bytes_accum"
+)
+
+// ArangoSearchConsolidationPolicy holds threshold values specifying when to
+// consolidate view data.
+// Semantics of the values depend on where they are used.
+type ArangoSearchConsolidationPolicy struct {
+	// Type returns the type of the ConsolidationPolicy.
+	Type ArangoSearchConsolidationPolicyType `json:"type,omitempty"`
+	// Threshold is used by the bytes_accum policy. Value must be in the range [0.0, 1.0].
+	Threshold *float64 `json:"threshold,omitempty"`
+	// SegmentsMin is used by the tier policy and specifies the minimum number of segments that will be evaluated as candidates for consolidation.
+	SegmentsMin *int64 `json:"segmentsMin,omitempty"`
+	// SegmentsMax is used by the tier policy and specifies the maximum number of segments that will be evaluated as candidates for consolidation.
+	SegmentsMax *int64 `json:"segmentsMax,omitempty"`
+	// SegmentsBytesMax is used by the tier policy and specifies the maximum allowed size of all consolidated segments in bytes.
+	SegmentsBytesMax *int64 `json:"segmentsBytesMax,omitempty"`
+	// SegmentsBytesFloor is used by the tier policy and defines the value (in bytes) to treat all smaller segments as equal for consolidation selection.
+	SegmentsBytesFloor *int64 `json:"segmentsBytesFloor,omitempty"`
+}
+
+// ArangoSearchPrimarySortEntry describes an entry for the primarySort list of an ArangoSearch view.
+type ArangoSearchPrimarySortEntry struct {
+	// Field is the name of the field to sort on.
+	Field string `json:"field"`
+	// Ascending specifies the sort direction. If not set, the server default (ascending) is used.
+	Ascending *bool `json:"asc,omitempty"`
+}
+
+// GetAscending returns the sort direction of the entry, defaulting to ascending.
+func (e ArangoSearchPrimarySortEntry) GetAscending() bool {
+	if e.Ascending == nil {
+		return true
+	}
+	return *e.Ascending
+}
@@ -24,8 +24,12 @@ package vst
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/util"
 	"github.com/arangodb/go-driver/vst/protocol"
 	velocypack "github.com/arangodb/go-velocypack"
 )
@@ -37,6 +41,49 @@ type vstAuthentication interface {
 	PrepareFunc(c *vstConnection) func(ctx context.Context, conn *protocol.Connection) error
 }
 
+// vstRenewableAuthentication is implemented by authentications that use credentials which expire.
+type vstRenewableAuthentication interface {
+	vstAuthentication
+
+	// NeedsRenewal returns true when the credentials must be renewed before making another request.
+	NeedsRenewal() bool
+	// Generation returns a number that is incremented every time the credentials are renewed.
+	Generation() int64
+	// Renew obtains new credentials and re-authenticates all existing connections of the given connection,
+	// unless that has already been done since the given generation of the credentials was used.
+	Renew(ctx context.Context, c *vstConnection, generation int64) error
+}
+
+// newVSTAuthentication creates an authentication implementation for the given authentication.
+func newVSTAuthentication(auth driver.Authentication) (vstAuthentication, error) {
+	switch auth.Type() {
+	case driver.AuthenticationTypeBasic:
+		userName := auth.Get("username")
+		password := auth.Get("password")
+		return newBasicAuthentication(userName, password), nil
+	case driver.AuthenticationTypeJWT:
+		userName := auth.Get("username")
+		password := auth.Get("password")
+		return newJWTAuthentication(userName, password), nil
+	case driver.AuthenticationTypeJWTSecret:
+		source, ok := auth.(driver.JWTTokenSource)
+		if !ok {
+			return nil, driver.WithStack(driver.InvalidArgumentError{Message: "JWT secret authentication must implement JWTTokenSource"})
+		}
+		return newJWTSecretAuthentication(source), nil
+	case driver.AuthenticationTypeProvided:
+		provider, ok := auth.(driver.CredentialsProvider)
+		if !ok {
+			return nil, driver.WithStack(driver.InvalidArgumentError{Message: "Provided authentication must implement CredentialsProvider"})
+		}
+		return newProvidedAuthentication(provider), nil
+	case driver.AuthenticationTypeRaw:
+		return nil, driver.WithStack(driver.InvalidArgumentError{Message: "Raw authentication is not supported by VST connections"})
+	default:
+		return nil, driver.WithStack(fmt.Errorf("Unsupported authentication type %d", int(auth.Type())))
+	}
+}
+
 // newBasicAuthentication creates an authentication implementation based on the given username & password.
 func newBasicAuthentication(userName, password string) vstAuthentication {
 	return &vstAuthenticationImpl{
@@ -48,14 +95,21 @@ func newBasicAuthentication(userName, password string) vstAuthentication {
 
 // newJWTAuthentication creates a JWT token authentication implementation based on the given username & password.
 func newJWTAuthentication(userName, password string) vstAuthentication {
-	return &vstAuthenticationImpl{
-		encryption: "jwt",
-		userName:   userName,
-		password:   password,
+	return &vstJWTAuthentication{
+		userName: userName,
+		password: password,
 	}
 }
 
-// vstAuthenticationImpl implements VST implementation for JWT & Plain.
+// newJWTSecretAuthentication creates a JWT token authentication implementation that obtains
+// its tokens from the given source, instead of requesting them from the server.
+func newJWTSecretAuthentication(source driver.JWTTokenSource) vstAuthentication {
+	return &vstJWTAuthentication{
+		source: source,
+	}
+}
+
+// vstAuthenticationImpl implements VST implementation for Plain.
 type vstAuthenticationImpl struct {
 	encryption string
 	userName   string
@@ -75,81 +129,330 @@ type jwtOpenResponse struct {
 // Prepare is called before the first request of the given connection is made.
 func (a *vstAuthenticationImpl) PrepareFunc(vstConn *vstConnection) func(ctx context.Context, conn *protocol.Connection) error {
 	return func(ctx context.Context, conn *protocol.Connection) error {
-		var authReq velocypack.Slice
-		var err error
+		// Create request
+		var b velocypack.Builder
+		b.OpenArray()
+		b.AddValue(velocypack.NewIntValue(1))               // Version
+		b.AddValue(velocypack.NewIntValue(1000))            // Type (1000=Auth)
+		b.AddValue(velocypack.NewStringValue(a.encryption)) // Encryption type
+		b.AddValue(velocypack.NewStringValue(a.userName))   // Username
+		b.AddValue(velocypack.NewStringValue(a.password))   // Password
+		b.Close()                                           // request
+		authReq, err := b.Slice()
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if err := sendAuthRequest(ctx, conn, authReq); err != nil {
+			return driver.WithStack(err)
+		}
+		return nil
+	}
+}
 
-		if a.encryption == "jwt" {
-			// Call _open/auth
-			// Prepare request
-			r, err := vstConn.NewRequest("POST", "/_open/auth")
-			if err != nil {
-				return driver.WithStack(err)
-			}
-			r.SetBody(jwtOpenRequest{
-				UserName: a.userName,
-				Password: a.password,
-			})
+// vstJWTAuthentication implements VST implementation for JWT.
+// The token is obtained from the server using the username & password
+// (or from a token source) and renewed shortly before it expires.
+type vstJWTAuthentication struct {
+	userName   string
+	password   string
+	source     driver.JWTTokenSource // If set, tokens are obtained from here instead of the server
+	renewMutex sync.Mutex            // Serializes renewals
+	mutex      sync.Mutex            // Protects the fields below
+	token      string
+	renewAt    time.Time
+	generation int64
+}
 
-			// Perform request
-			resp, err := vstConn.do(ctx, r, conn)
-			if err != nil {
-				return driver.WithStack(err)
-			}
-			if err := resp.CheckStatus(200); err != nil {
-				return driver.WithStack(err)
-			}
+// Prepare is called before the first request of the given connection is made.
+func (a *vstJWTAuthentication) PrepareFunc(vstConn *vstConnection) func(ctx context.Context, conn *protocol.Connection) error {
+	return func(ctx context.Context, conn *protocol.Connection) error {
+		token, err := a.currentToken(ctx, vstConn, conn)
+		if err != nil {
+			return driver.WithStack(err)
+		}
+		if err := a.authenticate(ctx, conn, token); err != nil {
+			return driver.WithStack(err)
+		}
+		return nil
+	}
+}
 
-			// Parse response
-			var data jwtOpenResponse
-			if err := resp.ParseBody("", &data); err != nil {
-				return driver.WithStack(err)
-			}
+// NeedsRenewal returns true when the token is about to expire.
+func (a *vstJWTAuthentication) NeedsRenewal() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.needsRenewal()
+}
+
+// needsRenewal returns true when the token is about to expire.
+// Requires a.mutex to be locked.
+func (a *vstJWTAuthentication) needsRenewal() bool {
+	return !a.renewAt.IsZero() && !time.Now().Before(a.renewAt)
+}
+
+// Generation returns a number that is incremented every time the token is renewed.
+func (a *vstJWTAuthentication) Generation() int64 {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.generation
+}
+
+// Renew obtains a new token and re-authenticates all existing connections of the given connection,
+// unless that has already been done since the given generation of the token was used.
+func (a *vstJWTAuthentication) Renew(ctx context.Context, vstConn *vstConnection, generation int64) error {
+	a.renewMutex.Lock()
+	defer a.renewMutex.Unlock()
+	if a.Generation() != generation {
+		// Another request has already renewed the token
+		return nil
+	}
+	token, err := a.fetchToken(ctx, vstConn, vstConn.transport)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	vstConn.transport.ReconfigureConnections(ctx, func(ctx context.Context, conn *protocol.Connection) error {
+		return a.authenticate(ctx, conn, token)
+	})
+	return nil
+}
+
+// currentToken returns the current token.
+// If there is no token yet, or the token is about to expire, a new token is fetched.
+func (a *vstJWTAuthentication) currentToken(ctx context.Context, vstConn *vstConnection, transport messageTransport) (string, error) {
+	a.mutex.Lock()
+	token, due := a.token, a.token == "" || a.needsRenewal()
+	a.mutex.Unlock()
+	if !due {
+		return token, nil
+	}
+	token, err := a.fetchToken(ctx, vstConn, transport)
+	if err != nil {
+		return "", driver.WithStack(err)
+	}
+	return token, nil
+}
+
+// fetchToken obtains a new token, either from the token source or by calling _open/auth
+// using the given transport.
+func (a *vstJWTAuthentication) fetchToken(ctx context.Context, vstConn *vstConnection, transport messageTransport) (string, error) {
+	var token string
+	var err error
+	if a.source != nil {
+		token, err = a.source.Token()
+	} else {
+		token, err = a.requestToken(ctx, vstConn, transport)
+	}
+	if err != nil {
+		return "", driver.WithStack(err)
+	}
+
+	// Store token
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.token = token
+	if expiresAt, found := util.JWTExpiresAt(token); found {
+		a.renewAt = util.JWTRenewAt(time.Now(), expiresAt)
+	} else {
+		a.renewAt = time.Time{}
+	}
+	a.generation++
+	return token, nil
+}
+
+// requestToken calls _open/auth to obtain a new token, using the given transport.
+func (a *vstJWTAuthentication) requestToken(ctx context.Context, vstConn *vstConnection, transport messageTransport) (string, error) {
+	// Prepare request
+	r, err := vstConn.NewRequest("POST", "/_open/auth")
+	if err != nil {
+		return "", driver.WithStack(err)
+	}
+	r.SetBody(jwtOpenRequest{
+		UserName: a.userName,
+		Password: a.password,
+	})
+
+	// Perform request
+	resp, err := vstConn.do(ctx, r, transport)
+	if err != nil {
+		return "", driver.WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return "", driver.WithStack(err)
+	}
+
+	// Parse response
+	var data jwtOpenResponse
+	if err := resp.ParseBody("", &data); err != nil {
+		return "", driver.WithStack(err)
+	}
+	return data.Token, nil
+}
+
+// authenticate sends an authentication message with the given token on the given connection.
+func (a *vstJWTAuthentication) authenticate(ctx context.Context, conn *protocol.Connection, token string) error {
+	// Create request
+	var b velocypack.Builder
+	b.OpenArray()
+	b.AddValue(velocypack.NewIntValue(1))        // Version
+	b.AddValue(velocypack.NewIntValue(1000))     // Type (1000=Auth)
+	b.AddValue(velocypack.NewStringValue("jwt")) // Encryption type
+	b.AddValue(velocypack.NewStringValue(token)) // Token
+	b.Close()                                    // request
+	authReq, err := b.Slice()
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if err := sendAuthRequest(ctx, conn, authReq); err != nil {
+		return driver.WithStack(err)
+	}
+	return nil
+}
+
+// sendAuthRequest sends the given authentication request on the given connection and waits for the response.
+func sendAuthRequest(ctx context.Context, conn *protocol.Connection, authReq velocypack.Slice) error {
+	// Send request
+	respChan, err := conn.Send(ctx, authReq)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+
+	// Wait for response
+	m := <-respChan
+	resp, err := newResponse(m.Data, "", nil)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return driver.WithStack(err)
+	}
+
+	// Ok
+	return nil
+}
+
+// newProvidedAuthentication creates an authentication implementation that obtains
+// its credentials from the given provider.
+func newProvidedAuthentication(provider driver.CredentialsProvider) vstAuthentication {
+	return &vstProvidedAuthentication{
+		provider: provider,
+	}
+}
 
-			// Create request
-			var b velocypack.Builder
-			b.OpenArray()
-			b.AddValue(velocypack.NewIntValue(1))             // Version
-			b.AddValue(velocypack.NewIntValue(1000))          // Type (1000=Auth)
-			b.AddValue(velocypack.NewStringValue("jwt"))      // Encryption type
-			b.AddValue(velocypack.NewStringValue(data.Token)) // Token
-			b.Close()                                         // request
-			authReq, err = b.Slice()
+// vstProvidedAuthentication implements authentication with credentials obtained from a provider.
+// It wraps the authentication implementation for the current credentials and
+// replaces it when the provider returns different credentials.
+type vstProvidedAuthentication struct {
+	provider   driver.CredentialsProvider
+	renewMutex sync.Mutex // Serializes renewals
+	mutex      sync.Mutex // Protects the fields below
+	current    driver.Authentication
+	auth       vstAuthentication
+	generation int64
+}
+
+// Prepare is called before the first request of the given connection is made.
+func (a *vstProvidedAuthentication) PrepareFunc(vstConn *vstConnection) func(ctx context.Context, conn *protocol.Connection) error {
+	return func(ctx context.Context, conn *protocol.Connection) error {
+		a.mutex.Lock()
+		auth := a.auth
+		a.mutex.Unlock()
+		if auth == nil {
+			creds, err := a.provider.Credentials(ctx)
 			if err != nil {
 				return driver.WithStack(err)
 			}
-		} else {
-			// Create request
-			var b velocypack.Builder
-			b.OpenArray()
-			b.AddValue(velocypack.NewIntValue(1))               // Version
-			b.AddValue(velocypack.NewIntValue(1000))            // Type (1000=Auth)
-			b.AddValue(velocypack.NewStringValue(a.encryption)) // Encryption type
-			b.AddValue(velocypack.NewStringValue(a.userName))   // Username
-			b.AddValue(velocypack.NewStringValue(a.password))   // Password
-			b.Close()                                           // request
-			authReq, err = b.Slice()
-			if err != nil {
+			if auth, err = a.use(creds); err != nil {
 				return driver.WithStack(err)
 			}
 		}
-
-		// Send request
-		respChan, err := conn.Send(ctx, authReq)
-		if err != nil {
+		if err := auth.PrepareFunc(vstConn)(ctx, conn); err != nil {
 			return driver.WithStack(err)
 		}
+		return nil
+	}
+}
 
-		// Wait for response
-		m := <-respChan
-		resp, err := newResponse(m.Data, "", nil)
-		if err != nil {
-			return driver.WithStack(err)
+// NeedsRenewal returns true when the provider returns different credentials,
+// or the credentials of the current authentication must be renewed.
+func (a *vstProvidedAuthentication) NeedsRenewal() bool {
+	a.mutex.Lock()
+	current, auth := a.current, a.auth
+	a.mutex.Unlock()
+	if auth == nil {
+		// Not yet authenticated
+		return false
+	}
+	if renewable, ok := auth.(vstRenewableAuthentication); ok && renewable.NeedsRenewal() {
+		return true
+	}
+	creds, err := a.provider.Credentials(context.Background())
+	if err != nil {
+		// Let Renew report the error
+		return true
+	}
+	return creds != current
+}
+
+// Generation returns a number that is incremented every time the credentials are renewed.
+func (a *vstProvidedAuthentication) Generation() int64 {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.generation
+}
+
+// Renew re-authenticates all existing connections of the given connection with the credentials
+// of the provider, unless that has already been done since the given generation of the credentials was used.
+// If the credentials have not changed, they have been rejected by the server and the provider is
+// asked to invalidate them.
+func (a *vstProvidedAuthentication) Renew(ctx context.Context, vstConn *vstConnection, generation int64) error {
+	a.renewMutex.Lock()
+	defer a.renewMutex.Unlock()
+	if a.Generation() != generation {
+		// Another request has already renewed the credentials
+		return nil
+	}
+	a.mutex.Lock()
+	current, auth := a.current, a.auth
+	a.mutex.Unlock()
+
+	creds, err := a.provider.Credentials(ctx)
+	if err != nil {
+		return driver.WithStack(err)
+	}
+	if auth != nil && creds == current {
+		if renewable, ok := auth.(vstRenewableAuthentication); ok && renewable.NeedsRenewal() {
+			// Renew the credentials of the current authentication
+			if err := renewable.Renew(ctx, vstConn, renewable.Generation()); err != nil {
+				return driver.WithStack(err)
+			}
+			a.mutex.Lock()
+			a.generation++
+			a.mutex.Unlock()
+			return nil
 		}
-		if err := resp.CheckStatus(200); err != nil {
+		// The credentials have not changed, so they were rejected by the server.
+		a.provider.Invalidate(creds)
+		if creds, err = a.provider.Credentials(ctx); err != nil {
 			return driver.WithStack(err)
 		}
+	}
+	if auth, err = a.use(creds); err != nil {
+		return driver.WithStack(err)
+	}
+	vstConn.transport.ReconfigureConnections(ctx, auth.PrepareFunc(vstConn))
+	return nil
+}
 
-		// Ok
-		return nil
+// use creates an authentication implementation for the given credentials and makes it the current one.
+func (a *vstProvidedAuthentication) use(creds driver.Authentication) (vstAuthentication, error) {
+	auth, err := newVSTAuthentication(creds)
+	if err != nil {
+		return nil, driver.WithStack(err)
 	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.current = creds
+	a.auth = auth
+	a.generation++
+	return auth, nil
 }
@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	driver "github.com/arangodb/go-driver"
 	"github.com/arangodb/go-driver/cluster"
@@ -40,12 +41,14 @@ import (
 const (
 	keyRawResponse driver.ContextKey = "arangodb-rawResponse"
 	keyResponse    driver.ContextKey = "arangodb-response"
+	keySpanEndInfo driver.ContextKey = "arangodb-spanEndInfo"
 )
 
 // ConnectionConfig provides all configuration options for a Velocypack connection.
 type ConnectionConfig struct {
 	// Endpoints holds 1 or more URL's used to connect to the database.
 	// In case of a connection to an ArangoDB cluster, you must provide the URL's of all coordinators.
+	// Use `unix:///path/to/socket` to connect to a unix domain socket.
 	Endpoints []string
 	// TLSConfig holds settings used to configure a TLS (HTTPS) connection.
 	// This is only used for endpoints using the HTTPS scheme.
@@ -60,6 +63,9 @@ type ConnectionConfig struct {
 	Transport protocol.TransportConfig
 	// Cluster configuration settings
 	cluster.ConnectionConfig
+	// Tracer is called around every request sent to a server.
+	// If nil, requests are not traced, but a traceparent set with driver.WithTraceParent is still sent.
+	Tracer driver.Tracer
 }
 
 type messageTransport interface {
@@ -98,9 +104,17 @@ func newVSTConnection(endpoint string, config ConnectionConfig) (driver.Connecti
 			tlsConfig = &tls.Config{}
 		}
 	}
+	var transport *protocol.Transport
+	if util.IsUnixEndpoint(endpoint) {
+		transport = protocol.NewUnixTransport(u.Path, config.Transport)
+	} else {
+		transport = protocol.NewTransport(hostAddr, tlsConfig, config.Transport)
+	}
 	c := &vstConnection{
 		endpoint:  *u,
-		transport: protocol.NewTransport(hostAddr, tlsConfig, config.Transport),
+		transport: transport,
+		tracer:    config.Tracer,
+		metrics:   config.Metrics,
 	}
 	return c, nil
 }
@@ -109,6 +123,9 @@ func newVSTConnection(endpoint string, config ConnectionConfig) (driver.Connecti
 type vstConnection struct {
 	endpoint  url.URL
 	transport *protocol.Transport
+	auth      vstAuthentication
+	tracer    driver.Tracer
+	metrics   driver.Metrics
 }
 
 // String returns the endpoint as string
@@ -133,10 +150,92 @@ func (c *vstConnection) NewRequest(method, path string) (driver.Request, error)
 
 // Do performs a given request, returning its response.
 func (c *vstConnection) Do(ctx context.Context, req driver.Request) (driver.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c.tracer == nil && c.metrics == nil {
+		if v := driver.TraceParentFromContext(ctx); v != "" {
+			req.SetHeader(driver.TraceParentHeader, v)
+		}
+		return c.doAuthenticated(ctx, req)
+	}
+
+	// Trace the request and/or collect its metrics
+	endpoint := c.endpoint.String()
+	var span driver.Span
+	if c.tracer != nil {
+		ctx, span = c.tracer.StartSpan(ctx, driver.SpanStartInfo{
+			Method:   req.Method(),
+			Path:     req.Path(),
+			Endpoint: endpoint,
+		})
+	}
+	traceParent := ""
+	if span != nil {
+		traceParent = span.TraceParent()
+	}
+	if traceParent == "" {
+		traceParent = driver.TraceParentFromContext(ctx)
+	}
+	if traceParent != "" {
+		req.SetHeader(driver.TraceParentHeader, traceParent)
+	}
+	var start time.Time
+	if c.metrics != nil {
+		c.metrics.RequestStarted(endpoint)
+		start = time.Now()
+	}
+	var info driver.SpanEndInfo
+	resp, err := c.doAuthenticated(context.WithValue(ctx, keySpanEndInfo, &info), req)
+	if resp != nil {
+		info.StatusCode = resp.StatusCode()
+	}
+	info.Err = err
+	if span != nil {
+		span.End(info)
+	}
+	if c.metrics != nil {
+		c.metrics.RequestDone(driver.RequestMetrics{
+			Endpoint:      endpoint,
+			Operation:     driver.RequestOperation(req.Method(), req.Path()),
+			Duration:      time.Since(start),
+			StatusCode:    info.StatusCode,
+			BytesSent:     info.RequestBytes,
+			BytesReceived: info.ResponseBytes,
+			Err:           err,
+		})
+	}
+	return resp, err
+}
+
+// doAuthenticated performs a given request, renewing the credentials when needed.
+func (c *vstConnection) doAuthenticated(ctx context.Context, req driver.Request) (driver.Response, error) {
+	auth, renewable := c.auth.(vstRenewableAuthentication)
+	var generation int64
+	if renewable {
+		if auth.NeedsRenewal() {
+			// Our credentials are about to expire
+			if err := auth.Renew(ctx, c, auth.Generation()); err != nil {
+				return nil, driver.WithStack(err)
+			}
+		}
+		generation = auth.Generation()
+	}
 	resp, err := c.do(ctx, req, c.transport)
 	if err != nil {
 		return nil, driver.WithStack(err)
 	}
+	if renewable && resp.StatusCode() == 401 {
+		// Our credentials may have expired unexpectedly.
+		// Renew them and try once more.
+		if err := auth.Renew(ctx, c, generation); err != nil {
+			return nil, driver.WithStack(err)
+		}
+		resp, err = c.do(ctx, req, c.transport)
+		if err != nil {
+			return nil, driver.WithStack(err)
+		}
+	}
 	return resp, nil
 }
 
@@ -188,6 +287,13 @@ func (c *vstConnection) do(ctx context.Context, req driver.Request, transport me
 		fmt.Printf("Cannot decode msg %d: %#v\n", msg.ID, err)
 		return nil, driver.WithStack(err)
 	}
+	if info, ok := ctx.Value(keySpanEndInfo).(*driver.SpanEndInfo); ok {
+		info.RequestBytes = 0
+		for _, part := range msgParts {
+			info.RequestBytes += int64(len(part))
+		}
+		info.ResponseBytes = int64(len(msg.Data))
+	}
 	if ctx != nil {
 		if v := ctx.Value(keyResponse); v != nil {
 			if respPtr, ok := v.(*driver.Response); ok {
@@ -238,21 +344,13 @@ func (c *vstConnection) UpdateEndpoints(endpoints []string) error {
 
 // Configure the authentication used for this connection.
 func (c *vstConnection) SetAuthentication(auth driver.Authentication) (driver.Connection, error) {
-	var vstAuth vstAuthentication
-	switch auth.Type() {
-	case driver.AuthenticationTypeBasic:
-		userName := auth.Get("username")
-		password := auth.Get("password")
-		vstAuth = newBasicAuthentication(userName, password)
-	case driver.AuthenticationTypeJWT:
-		userName := auth.Get("username")
-		password := auth.Get("password")
-		vstAuth = newJWTAuthentication(userName, password)
-	default:
-		return nil, driver.WithStack(fmt.Errorf("Unsupported authentication type %d", int(auth.Type())))
+	vstAuth, err := newVSTAuthentication(auth)
+	if err != nil {
+		return nil, driver.WithStack(err)
 	}
 
 	// Set authentication callback
+	c.auth = vstAuth
 	c.transport.SetOnConnectionCreated(vstAuth.PrepareFunc(c))
 	// Close all existing connections
 	c.transport.CloseAllConnections()
@@ -58,10 +58,10 @@ var (
 	vstProtocolHeader1_1 = []byte("VST/1.1\r\n\r\n")
 )
 
-// dial opens a new connection to the server on the given address.
-func dial(version Version, addr string, tlsConfig *tls.Config) (*Connection, error) {
-	// Create TCP connection
-	conn, err := net.Dial("tcp", addr)
+// dial opens a new connection to the server on the given network & address.
+func dial(version Version, network, addr string, tlsConfig *tls.Config) (*Connection, error) {
+	// Create TCP (or unix domain socket) connection
+	conn, err := net.Dial(network, addr)
 	if err != nil {
 		return nil, driver.WithStack(err)
 	}
@@ -58,6 +58,7 @@ type TransportConfig struct {
 type Transport struct {
 	TransportConfig
 
+	network             string
 	hostAddr            string
 	tlsConfig           *tls.Config
 	connMutex           sync.Mutex
@@ -67,6 +68,16 @@ type Transport struct {
 
 // NewTransport creates a new Transport for given address & tls settings.
 func NewTransport(hostAddr string, tlsConfig *tls.Config, config TransportConfig) *Transport {
+	return newTransport("tcp", hostAddr, tlsConfig, config)
+}
+
+// NewUnixTransport creates a new Transport for the unix domain socket with given path.
+func NewUnixTransport(socketPath string, config TransportConfig) *Transport {
+	return newTransport("unix", socketPath, nil, config)
+}
+
+// newTransport creates a new Transport for given network, address & tls settings.
+func newTransport(network, hostAddr string, tlsConfig *tls.Config, config TransportConfig) *Transport {
 	if config.IdleConnTimeout == 0 {
 		config.IdleConnTimeout = DefaultIdleConnTimeout
 	}
@@ -75,6 +86,7 @@ func NewTransport(hostAddr string, tlsConfig *tls.Config, config TransportConfig
 	}
 	return &Transport{
 		TransportConfig: config,
+		network:         network,
 		hostAddr:        hostAddr,
 		tlsConfig:       tlsConfig,
 	}
@@ -129,6 +141,24 @@ func (c *Transport) CloseAllConnections() {
 	}
 }
 
+// ReconfigureConnections calls the given handler for all open & configured connections,
+// e.g. to re-authenticate them.
+// Connections for which the handler fails are closed.
+func (c *Transport) ReconfigureConnections(ctx context.Context, handler func(context.Context, *Connection) error) {
+	c.connMutex.Lock()
+	connections := append([]*Connection(nil), c.connections...)
+	c.connMutex.Unlock()
+
+	for _, conn := range connections {
+		if conn.IsClosed() || !conn.IsConfigured() {
+			continue
+		}
+		if err := handler(ctx, conn); err != nil {
+			conn.Close()
+		}
+	}
+}
+
 // SetOnConnectionCreated stores a callback function that is called every time a new connection has been created.
 func (c *Transport) SetOnConnectionCreated(handler func(context.Context, *Connection) error) {
 	c.onConnectionCreated = handler
@@ -205,7 +235,7 @@ func (c *Transport) getAvailableConnection() *Connection {
 
 // createConnection creates a new connection.
 func (c *Transport) createConnection() (*Connection, error) {
-	conn, err := dial(c.Version, c.hostAddr, c.tlsConfig)
+	conn, err := dial(c.Version, c.network, c.hostAddr, c.tlsConfig)
 	if err != nil {
 		return nil, driver.WithStack(err)
 	}
@@ -43,6 +43,9 @@ type vstRequest struct {
 	written bool
 }
 
+// Ensure the request supports a raw body
+var _ driver.RawBodyRequest = &vstRequest{}
+
 // Clone creates a new request containing the same data as this request
 func (r *vstRequest) Clone() driver.Request {
 	clone := *r
@@ -197,6 +200,14 @@ func (r *vstRequest) SetBodyImportArray(bodyArray interface{}) (driver.Request,
 	return r, nil
 }
 
+// SetRawBody sets the content of the request to the given bytes, sent as-is with the given content type
+// (e.g. "application/zip"). No marshalling is taking place.
+func (r *vstRequest) SetRawBody(body []byte, contentType string) (driver.Request, error) {
+	r.body = body
+	r.SetHeader("content-type", contentType)
+	return r, nil
+}
+
 // SetHeader sets a single header arguments of the request.
 // Any existing header argument with the same key is overwritten.
 func (r *vstRequest) SetHeader(key, value string) driver.Request {
@@ -207,6 +218,16 @@ func (r *vstRequest) SetHeader(key, value string) driver.Request {
 	return r
 }
 
+// Method returns the method of the request (GET, POST, ...).
+func (r *vstRequest) Method() string {
+	return r.method
+}
+
+// Path returns the path of the request, relative to the endpoint.
+func (r *vstRequest) Path() string {
+	return r.path
+}
+
 // Written returns true as soon as this request has been written completely to the network.
 // This does not guarantee that the server has received or processed the request.
 func (r *vstRequest) Written() bool {